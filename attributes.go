@@ -0,0 +1,243 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// AttributeState is the state of a git attribute for a path.
+type AttributeState int
+
+const (
+	// AttributeUnspecified means no pattern mentions the attribute, or it
+	// has been reset with "!attr".
+	AttributeUnspecified AttributeState = iota
+	// AttributeSet means the attribute is set, like "text".
+	AttributeSet
+	// AttributeUnset means the attribute is unset, like "-text".
+	AttributeUnset
+	// AttributeValue means the attribute is set to a value, like
+	// "eol=lf".
+	AttributeValue
+)
+
+// String returns the state like "git check-attr" prints it.
+func (s AttributeState) String() string {
+	switch s {
+	case AttributeSet:
+		return "set"
+	case AttributeUnset:
+		return "unset"
+	case AttributeValue:
+		return "value"
+	default:
+		return "unspecified"
+	}
+}
+
+// Attribute is the state of a single git attribute.
+type Attribute struct {
+	Name  string
+	State AttributeState
+	// Value is the value of the attribute if State is AttributeValue.
+	Value string
+}
+
+// String formats the attribute like it is written in .gitattributes, e.g.
+// "text", "-text", "!text" or "eol=lf".
+func (a Attribute) String() string {
+	switch a.State {
+	case AttributeSet:
+		return a.Name
+	case AttributeUnset:
+		return "-" + a.Name
+	case AttributeValue:
+		return a.Name + "=" + a.Value
+	default:
+		return "!" + a.Name
+	}
+}
+
+// attributeRule is a single line of a .gitattributes file.
+type attributeRule struct {
+	pattern    *Pattern
+	dirOnly    bool
+	attributes []Attribute
+}
+
+// Attributes matches paths against the lines of a .gitattributes file. The
+// patterns use the same syntax as gitignore, except that negated patterns
+// are not allowed and patterns ending with a slash only match directories
+// themselves, not the paths inside them.
+//
+// Like in git, attributes are resolved per attribute: for every attribute,
+// the last matching line mentioning it decides. Macros are defined with
+// "[attr]name attr..." lines, and the built-in "binary" macro expands to
+// "-diff -merge -text".
+type Attributes struct {
+	rules  []attributeRule
+	macros map[string][]Attribute
+}
+
+// AttributesFromLines parses the lines of a .gitattributes file. Blank lines
+// and comments are skipped.
+func AttributesFromLines(lines ...string) (*Attributes, error) {
+	a := &Attributes{macros: map[string][]Attribute{
+		"binary": {
+			{Name: "diff", State: AttributeUnset},
+			{Name: "merge", State: AttributeUnset},
+			{Name: "text", State: AttributeUnset},
+		},
+	}}
+	var errs ParseErrors
+	for i, line := range lines {
+		// Unlike gitignore files, leading whitespace is insignificant.
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		if err := a.parseLine(line); err != nil {
+			errs = append(errs, &ParseError{Line: i + 1, Pattern: line, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return a, nil
+}
+
+// ReadAttributes parses a .gitattributes file, line by line.
+func ReadAttributes(r io.Reader) (*Attributes, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return AttributesFromLines(lines...)
+}
+
+// parseLine parses a single trimmed line, which is neither blank nor a
+// comment.
+func (a *Attributes) parseLine(line string) error {
+	pattern, rest, err := splitAttributePattern(line)
+	if err != nil {
+		return err
+	}
+	var attributes []Attribute
+	for _, field := range strings.Fields(rest) {
+		attributes = append(attributes, parseAttribute(field))
+	}
+
+	if strings.HasPrefix(pattern, "[attr]") {
+		a.macros[strings.TrimPrefix(pattern, "[attr]")] = attributes
+		return nil
+	}
+	if strings.HasPrefix(pattern, "!") {
+		return fmt.Errorf("negative patterns are not allowed in git attributes: %q", pattern)
+	}
+	dirOnly := strings.HasSuffix(pattern, "/") && pattern != "/"
+	p, err := NewPattern(strings.TrimSuffix(pattern, "/"))
+	if err != nil {
+		return err
+	}
+	a.rules = append(a.rules, attributeRule{pattern: p, dirOnly: dirOnly, attributes: attributes})
+	return nil
+}
+
+// splitAttributePattern splits a line into its pattern, which may be quoted
+// like a Go or C string, and the attributes following it.
+func splitAttributePattern(line string) (pattern, rest string, err error) {
+	if strings.HasPrefix(line, `"`) {
+		for i := 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				pattern, err = strconv.Unquote(line[:i+1])
+				return pattern, line[i+1:], err
+			}
+		}
+		return "", "", fmt.Errorf("unterminated quoted pattern %q", line)
+	}
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		return line[:i], line[i:], nil
+	}
+	return line, "", nil
+}
+
+// parseAttribute parses a single attribute assignment.
+func parseAttribute(field string) Attribute {
+	switch {
+	case strings.HasPrefix(field, "-"):
+		return Attribute{Name: field[1:], State: AttributeUnset}
+	case strings.HasPrefix(field, "!"):
+		return Attribute{Name: field[1:], State: AttributeUnspecified}
+	}
+	if i := strings.IndexByte(field, '='); i >= 0 {
+		return Attribute{Name: field[:i], State: AttributeValue, Value: field[i+1:]}
+	}
+	return Attribute{Name: field, State: AttributeSet}
+}
+
+// Match returns the effective attributes of name, keyed by attribute name.
+// Unspecified attributes are not included. Directories are denoted by a
+// trailing slash, e.g. "docs/".
+func (a *Attributes) Match(name string) map[string]Attribute {
+	name = filepath.ToSlash(name)
+	isDir := strings.HasSuffix(name, "/")
+	trimmed := strings.TrimSuffix(name, "/")
+	result := make(map[string]Attribute)
+	for _, rule := range a.rules {
+		if rule.dirOnly && !isDir || !rule.pattern.match(trimmed) {
+			continue
+		}
+		for _, attr := range rule.attributes {
+			a.apply(result, attr, 0)
+		}
+	}
+	return result
+}
+
+// Get returns the effective state of the attribute attr for name.
+func (a *Attributes) Get(name, attr string) Attribute {
+	if v, ok := a.Match(name)[attr]; ok {
+		return v
+	}
+	return Attribute{Name: attr}
+}
+
+// maxMacroDepth limits the expansion of macros referring to each other.
+const maxMacroDepth = 16
+
+// apply assigns attr to result, expanding it if it is a set macro.
+func (a *Attributes) apply(result map[string]Attribute, attr Attribute, depth int) {
+	if attr.State == AttributeUnspecified {
+		delete(result, attr.Name)
+	} else {
+		result[attr.Name] = attr
+	}
+	if macro, ok := a.macros[attr.Name]; ok && attr.State == AttributeSet && depth < maxMacroDepth {
+		for _, m := range macro {
+			a.apply(result, m, depth+1)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAttributes(t *testing.T) {
+	content := `# attributes
+* text=auto
+*.jpg binary
+*.sh eol=lf
+[attr]generated -diff linguist-generated
+gen/** generated
+gen/keep.go !linguist-generated diff
+"with space.txt" -text
+docs/ export-ignore
+`
+	tests := map[string]string{
+		"main.go":        "text=auto",
+		"img/a.jpg":      "-diff -merge -text binary",
+		"run.sh":         "eol=lf text=auto",
+		"gen/a.go":       "-diff generated linguist-generated text=auto",
+		"gen/keep.go":    "diff generated text=auto",
+		"with space.txt": "-text",
+		"docs/":          "export-ignore text=auto",
+		"docs/index.md":  "text=auto",
+	}
+
+	a, err := ReadAttributes(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for name, want := range tests {
+		var got []string
+		for _, attr := range a.Match(name) {
+			got = append(got, attr.String())
+		}
+		if s := sortedJoin(got); s != want {
+			t.Errorf("Attributes.Match(%s) returned '%s', want '%s'", name, s, want)
+		}
+	}
+
+	if got := a.Get("run.sh", "eol"); got.State != AttributeValue || got.Value != "lf" {
+		t.Errorf("Attributes.Get(run.sh, eol) returned '%s', want 'eol=lf'", got)
+	}
+	if got := a.Get("run.sh", "diff"); got.State != AttributeUnspecified {
+		t.Errorf("Attributes.Get(run.sh, diff) returned '%s', want '!diff'", got)
+	}
+
+	if _, err := AttributesFromLines("!*.txt text"); err == nil {
+		t.Errorf("AttributesFromLines(!*.txt text) returned no error, want one")
+	}
+}
+
+// sortedJoin sorts s and joins it with spaces.
+func sortedJoin(s []string) string {
+	sort.Strings(s)
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,84 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"runtime"
+	"sync"
+)
+
+// MatchAll matches names and returns whether each name is ignored, at the
+// same index as the name. The results are stored in results, which is grown
+// if its capacity is too small, so a caller matching many batches can reuse a
+// single buffer and avoid allocations.
+func (ps *PathSpec) MatchAll(names []string, results []bool) []bool {
+	if cap(results) < len(names) {
+		results = make([]bool, len(names))
+	}
+	results = results[:len(names)]
+	for i, name := range names {
+		name = ps.slashPath(name)
+		state := StateIncluded
+		if !isTracked(ps.tracked, name) {
+			state = patternState(ps.lastMatch(name))
+		}
+		ps.decided(name, state)
+		results[i] = state == StateIgnored
+	}
+	return results
+}
+
+// MatchAllParallel matches names on up to workers goroutines and returns
+// whether each name is ignored, at the same index as the name. If workers is
+// zero or negative, runtime.GOMAXPROCS(0) goroutines are used. A compiled
+// PathSpec is safe for concurrent use, as long as it is not modified.
+func (ps *PathSpec) MatchAllParallel(names []string, workers int) []bool {
+	results := make([]bool, len(names))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(names) {
+		workers = len(names)
+	}
+	if workers <= 1 {
+		for i, name := range names {
+			results[i] = ps.Match(name)
+		}
+		return results
+	}
+
+	// Every worker matches a contiguous shard of names and writes to its
+	// own part of results, so no further synchronization is needed.
+	var wg sync.WaitGroup
+	shard := (len(names) + workers - 1) / workers
+	for start := 0; start < len(names); start += shard {
+		end := start + shard
+		if end > len(names) {
+			end = len(names)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				results[i] = ps.Match(names[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return results
+}
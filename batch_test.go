@@ -0,0 +1,89 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPathSpecMatchAllParallel(t *testing.T) {
+	ps, err := FromLines("*.log", "!keep*.log", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	var names []string
+	for i := 0; i < 1000; i++ {
+		names = append(names, fmt.Sprintf("dir%d/file%d.log", i%7, i), fmt.Sprintf("keep%d.log", i), fmt.Sprintf("build/%d", i))
+	}
+
+	for _, workers := range []int{-1, 0, 1, 3, 8, len(names) + 1} {
+		results := ps.MatchAllParallel(names, workers)
+		if len(results) != len(names) {
+			t.Fatalf("MatchAllParallel(%d) returned %d results, want %d", workers, len(results), len(names))
+		}
+		for i, name := range names {
+			if want := ps.Match(name); results[i] != want {
+				t.Errorf("MatchAllParallel(%d)[%d] for %s returned '%v', want '%v'", workers, i, name, results[i], want)
+			}
+		}
+	}
+
+	if results := ps.MatchAllParallel(nil, 4); len(results) != 0 {
+		t.Errorf("MatchAllParallel(nil) returned %d results, want 0", len(results))
+	}
+}
+
+func TestPathSpecMatchAll(t *testing.T) {
+	ps, err := FromLines("*.log", "!keep.log", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	names := []string{"a.log", "keep.log", "build/", "main.go"}
+	want := []bool{true, false, true, false}
+
+	buf := make([]bool, 0, 16)
+	results := ps.MatchAll(names, buf)
+	if &results[0] != &buf[:1][0] {
+		t.Errorf("MatchAll() did not reuse the results buffer")
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("MatchAll()[%d] for %s returned '%v', want '%v'", i, names[i], results[i], want[i])
+		}
+	}
+
+	if results := ps.MatchAll(names, nil); len(results) != len(names) {
+		t.Errorf("MatchAll(nil) returned %d results, want %d", len(results), len(names))
+	}
+	if results := ps.MatchAll(names[:1], buf[:4]); len(results) != 1 {
+		t.Errorf("MatchAll() returned %d results, want 1", len(results))
+	}
+
+	if raceEnabled {
+		// Allocation counts are unreliable with the race detector.
+		return
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		ps.MatchAll(names, buf)
+	})
+	if allocs != 0 {
+		t.Errorf("MatchAll() allocated %v times per run, want 0", allocs)
+	}
+}
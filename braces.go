@@ -0,0 +1,129 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WithBraceExpansion enables brace expansion as known from shells,
+// EditorConfig and minimatch. A pattern like "*.{js,ts,jsx}" matches whatever
+// one of "*.js", "*.ts" and "*.jsx" matches. Braces may be nested, and a
+// backslash escapes a brace or comma. Braces without a comma, like "{a}",
+// and unbalanced braces are taken literally.
+func WithBraceExpansion() Option {
+	return func(o *options) {
+		o.braceExpansion = true
+	}
+}
+
+// newBracePattern compiles a gitignore pattern after brace expansion. The
+// expansion and the regular expression are checked against limits before
+// they are built.
+func newBracePattern(line string, limits Limits) (*Pattern, error) {
+	if err := limits.checkExpansion(line); err != nil {
+		return nil, err
+	}
+	alternatives := expandBraces(line)
+	if len(alternatives) == 1 {
+		return NewPattern(line)
+	}
+	exprs := make([]string, 0, len(alternatives))
+	for _, alternative := range alternatives {
+		p, err := parsePattern(alternative)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, p.Regex)
+	}
+	expr := strings.Join(exprs, "|")
+	if err := limits.checkExpr(line, expr); err != nil {
+		return nil, err
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{
+		syntax: "braces",
+		text:   line,
+		negate: strings.HasPrefix(line, "!"),
+		regex:  regex,
+		dir:    strings.HasSuffix(line, "/"),
+	}, nil
+}
+
+// expandBraces returns all alternatives of pattern described by its brace
+// expressions, in order.
+func expandBraces(pattern string) []string {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			// Skip the escaped character.
+			i++
+		case '{':
+			alternatives, end, ok := splitBraces(pattern, i)
+			if !ok {
+				continue
+			}
+			prefix := pattern[:i]
+			suffixes := expandBraces(pattern[end+1:])
+			var expanded []string
+			for _, alternative := range alternatives {
+				for _, a := range expandBraces(alternative) {
+					for _, suffix := range suffixes {
+						expanded = append(expanded, prefix+a+suffix)
+					}
+				}
+			}
+			return expanded
+		}
+	}
+	return []string{pattern}
+}
+
+// splitBraces finds the closing brace matching the opening brace at index
+// open and splits the expression in between at its top-level commas. It
+// reports false if there is no closing brace or no top-level comma.
+func splitBraces(pattern string, open int) (alternatives []string, end int, ok bool) {
+	depth := 0
+	start := open + 1
+	for i := open; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[start:i])
+				start = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				if len(alternatives) == 0 {
+					return nil, 0, false
+				}
+				return append(alternatives, pattern[start:i]), i, true
+			}
+		}
+	}
+	return nil, 0, false
+}
@@ -0,0 +1,73 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := map[string][]string{
+		"*.{js,ts,jsx}":    {"*.js", "*.ts", "*.jsx"},
+		"{a,b}/{c,d}":      {"a/c", "a/d", "b/c", "b/d"},
+		"a{b,c{d,e}}f":     {"abf", "acdf", "acef"},
+		"{a}":              {"{a}"},
+		"{a,b":             {"{a,b"},
+		"\\{a,b}":          {"\\{a,b}"},
+		"{a\\,b,c}":        {"a\\,b", "c"},
+		"src/{,test/}*.go": {"src/*.go", "src/test/*.go"},
+	}
+
+	for pattern, want := range tests {
+		if got := expandBraces(pattern); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("expandBraces(%s) returned '%q', want '%q'", pattern, got, want)
+		}
+	}
+}
+
+func TestWithBraceExpansion(t *testing.T) {
+	lines := []string{"*.{js,ts,jsx}", "{build,dist}/", "!src/{keep,main}.{js,ts}"}
+	toInclude := []string{"main.go", "src/keep.ts", "src/main.js", "build", "{build,dist}/a"}
+	toIgnore := []string{"a.js", "lib/b.ts", "c.jsx", "src/other.js", "build/out", "dist/"}
+
+	ps, err := FromLinesWithOptions(lines, WithBraceExpansion())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for _, f := range toInclude {
+		if ps.Match(f) {
+			t.Errorf("Match('%s', %s) returned 'true', want 'false'", lines, f)
+		}
+	}
+
+	for _, f := range toIgnore {
+		if !ps.Match(f) {
+			t.Errorf("Match('%s', %s) returned 'false', want 'true'", lines, f)
+		}
+	}
+
+	ps, err = FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !ps.Match("{build,dist}/a") {
+		t.Errorf("Match('%s', {build,dist}/a) without brace expansion returned 'false', want 'true'", lines)
+	}
+}
@@ -0,0 +1,63 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// dirBufPool holds the buffers MatchBytes appends the trailing slash of
+// directories to, so matching directories does not allocate either.
+var dirBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+// MatchBytes is like MatchPath, but takes the path as a byte slice, e.g.
+// straight from a directory read buffer, and does not allocate: the bytes are
+// matched in place instead of being copied to a string.
+//
+// MatchBytes copies path anyway if the PathSpec caches decisions, has hooks,
+// tracked paths or pattern statistics, since they keep the path beyond the
+// call or must see every decision. Converting paths, as
+// WithWindowsPaths and WithUnicodeNormalization do, and backslashes on
+// Windows allocate, too. Custom Matchers must not keep the names they match,
+// because the bytes of path may change after MatchBytes returns.
+func (ps *PathSpec) MatchBytes(path []byte, isDir bool) bool {
+	if ps.cache != nil || ps.hooks != nil || ps.tracked != nil || ps.counters != nil {
+		return ps.MatchPath(string(path), isDir)
+	}
+	if !isDir || (len(path) > 0 && path[len(path)-1] == '/') {
+		return patternState(ps.findLastMatch(ps.slashPath(bytesToString(path)))) == StateIgnored
+	}
+	bp := dirBufPool.Get().(*[]byte)
+	buf := append(append((*bp)[:0], path...), '/')
+	matched := patternState(ps.findLastMatch(ps.slashPath(bytesToString(buf)))) == StateIgnored
+	*bp = buf
+	dirBufPool.Put(bp)
+	return matched
+}
+
+// bytesToString returns a string sharing the memory of b. The string must not
+// be used after b is modified.
+func bytesToString(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
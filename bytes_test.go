@@ -0,0 +1,80 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestMatchBytes(t *testing.T) {
+	ps, err := FromLines("*.log", "!keep.log", "build/", "/docs/*.md")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := []struct {
+		name  string
+		isDir bool
+		want  bool
+	}{
+		{"debug.log", false, true},
+		{"a/keep.log", false, false},
+		{"build", true, true},
+		{"build/", true, true},
+		{"build", false, false},
+		{"a/build/out.o", false, true},
+		{"docs/index.md", false, true},
+		{"a/docs/index.md", false, false},
+	}
+	for _, test := range tests {
+		if got := ps.MatchBytes([]byte(test.name), test.isDir); got != test.want {
+			t.Errorf("MatchBytes('%s', %v) returned '%v', want '%v'", test.name, test.isDir, got, test.want)
+		}
+	}
+
+	ps, err = FromLinesWithOptions([]string{"*.log"}, WithCache(4))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	buf := []byte("debug.log")
+	if got := ps.MatchBytes(buf, false); !got {
+		t.Errorf("MatchBytes('debug.log', false) returned '%v', want '%v'", got, true)
+	}
+	copy(buf, "debug.txt")
+	if got := ps.MatchBytes(buf, false); got {
+		t.Errorf("MatchBytes('debug.txt', false) returned '%v', want '%v'", got, false)
+	}
+}
+
+func TestMatchBytesAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are unreliable with the race detector")
+	}
+	ps, err := FromLines("*.log", "!keep.log", "build/", "/docs/*.md", "vendor")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	file := []byte("src/docs/debug.log")
+	dir := []byte("src/build")
+	allocs := testing.AllocsPerRun(100, func() {
+		ps.MatchBytes(file, false)
+		ps.MatchBytes(dir, true)
+	})
+	if allocs != 0 {
+		t.Errorf("MatchBytes allocated %v times, want 0", allocs)
+	}
+}
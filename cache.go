@@ -0,0 +1,104 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"container/list"
+	"sync"
+)
+
+// WithCache caches the decisions about the n most recently matched paths,
+// so matching a path again, as file watchers do, skips evaluating the
+// patterns. Paths are cached by their slash-separated form, including the
+// trailing slash of directories. OnMatch hooks are not called for cached
+// decisions. n must be positive.
+func WithCache(n int) Option {
+	return func(o *options) {
+		o.cacheSize = n
+	}
+}
+
+// Invalidate drops all cached decisions, see WithCache. Modifying the
+// patterns with Append or Prepend invalidates the cache automatically.
+func (ps *PathSpec) Invalidate() {
+	if ps.cache != nil {
+		ps.cache.clear()
+	}
+}
+
+// matchCache is a least recently used cache of match decisions, safe for
+// concurrent use.
+type matchCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// cacheEntry is an element of matchCache.lru.
+type cacheEntry struct {
+	name  string
+	state MatchState
+}
+
+// newMatchCache returns a cache holding up to size decisions.
+func newMatchCache(size int) *matchCache {
+	return &matchCache{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		lru:     list.New(),
+	}
+}
+
+// get returns the cached decision about name.
+func (c *matchCache) get(name string) (MatchState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[name]
+	if !ok {
+		return StateUnmatched, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*cacheEntry).state, true
+}
+
+// put caches the decision about name, evicting the least recently used
+// decision if the cache is full.
+func (c *matchCache) put(name string, state MatchState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[name]; ok {
+		e.Value.(*cacheEntry).state = state
+		c.lru.MoveToFront(e)
+		return
+	}
+	if c.lru.Len() >= c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).name)
+	}
+	c.entries[name] = c.lru.PushFront(&cacheEntry{name: name, state: state})
+}
+
+// clear drops all cached decisions.
+func (c *matchCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element, c.size)
+	c.lru.Init()
+}
@@ -0,0 +1,65 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestWithCache(t *testing.T) {
+	evaluated := 0
+	hooks := Hooks{OnMatch: func(p *Pattern, name string) { evaluated++ }}
+	ps, err := FromLinesWithOptions([]string{"*.log"}, WithCache(2), WithHooks(hooks))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if !ps.Match("a.log") {
+			t.Errorf("Match('a.log') returned 'false', want 'true'")
+		}
+	}
+	if evaluated != 1 {
+		t.Errorf("Match('a.log') evaluated the patterns %d times, want 1", evaluated)
+	}
+
+	// b.log and c.log evict a.log.
+	ps.Match("b.log")
+	ps.Match("c.log")
+	ps.Match("a.log")
+	if evaluated != 4 {
+		t.Errorf("Match() evaluated the patterns %d times, want 4", evaluated)
+	}
+	if err := ps.AppendLines("!a.log"); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if ps.Match("a.log") {
+		t.Errorf("Match('a.log') returned a stale cached decision after AppendLines")
+	}
+
+	ps.Invalidate()
+	if len(ps.cache.entries) != 0 || ps.cache.lru.Len() != 0 {
+		t.Errorf("Invalidate() left %d cached decisions", ps.cache.lru.Len())
+	}
+
+	ps.MatchPath("build", true)
+	ps.Match("build")
+	if ps.cache.lru.Len() != 2 {
+		t.Errorf("Match() cached %d decisions for a file and a directory of the same name, want 2", ps.cache.lru.Len())
+	}
+}
@@ -0,0 +1,137 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckResult describes the decision about a path with the fields
+// "git check-ignore --verbose" prints.
+type CheckResult struct {
+	// Source is the name of the file the deciding pattern was read from,
+	// if known.
+	Source string
+	// Line is the line number of the deciding pattern in Source, or 0 if
+	// no pattern matched or it is unknown.
+	Line int
+	// Pattern is the deciding pattern as it was written, including a
+	// leading "!", or an empty string if no pattern matched.
+	Pattern string
+	// Path is the path as it was passed to CheckIgnore.
+	Path string
+	// Matched is true if a pattern matched the path.
+	Matched bool
+	// Negate is true if the deciding pattern re-included the path.
+	Negate bool
+}
+
+// Ignored reports whether the path is ignored, which is what
+// "git check-ignore" without --verbose reports.
+func (r CheckResult) Ignored() bool {
+	return r.Matched && !r.Negate
+}
+
+// Format returns the result in the layout of "git check-ignore --verbose",
+// without the line terminator: "<source>:<linenum>:<pattern>\t<path>", or
+// "::\t<path>" if no pattern matched, as printed with --non-matching. Like
+// git, the source and the path are quoted if they contain control
+// characters, double quotes, backslashes or non-ASCII bytes.
+func (r CheckResult) Format() string {
+	if !r.Matched {
+		return "::\t" + quotePath(r.Path)
+	}
+	return fmt.Sprintf("%s:%d:%s\t%s", quotePath(r.Source), r.Line, r.Pattern, quotePath(r.Path))
+}
+
+// CheckIgnore returns the decision about every path, in order, like
+// "git check-ignore --verbose --non-matching" does. Directories are denoted by
+// a trailing slash, e.g. "build/".
+func (ps *PathSpec) CheckIgnore(paths []string) []CheckResult {
+	return checkIgnore(paths, ps.MatchP)
+}
+
+// CheckIgnore is like PathSpec.CheckIgnore.
+func (t *GitIgnoreTree) CheckIgnore(paths []string) []CheckResult {
+	return checkIgnore(paths, t.MatchP)
+}
+
+// checkIgnore describes the results of matchP for every path.
+func checkIgnore(paths []string, matchP func(string) *MatchResult) []CheckResult {
+	results := make([]CheckResult, len(paths))
+	for i, name := range paths {
+		results[i].Path = name
+		if r := matchP(name); r != nil {
+			results[i].Source = r.Source
+			results[i].Line = r.Line
+			results[i].Pattern = r.Text
+			results[i].Matched = true
+			results[i].Negate = r.Negate
+		}
+	}
+	return results
+}
+
+// quotePath quotes s like git does for path names when core.quotePath is
+// enabled, which is the default. s is returned unchanged if no byte needs
+// quoting.
+func quotePath(s string) string {
+	i := 0
+	for i < len(s) && !needsQuote(s[i]) {
+		i++
+	}
+	if i == len(s) {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case !needsQuote(c):
+			b.WriteByte(c)
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\a':
+			b.WriteString(`\a`)
+		case c == '\b':
+			b.WriteString(`\b`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\v':
+			b.WriteString(`\v`)
+		case c == '\f':
+			b.WriteString(`\f`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		default:
+			fmt.Fprintf(&b, `\%03o`, c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// needsQuote reports whether git quotes the byte c in path names.
+func needsQuote(c byte) bool {
+	return c < 0x20 || c == '"' || c == '\\' || c >= 0x7f
+}
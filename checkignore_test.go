@@ -0,0 +1,78 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckIgnore(t *testing.T) {
+	ps, err := FromLines("*.log", "!keep.log", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for _, p := range ps.Patterns() {
+		p.source = ".gitignore"
+	}
+
+	results := ps.CheckIgnore([]string{"debug.log", "keep.log", "build/", "main.go", "tab\there.log"})
+	want := []CheckResult{
+		{Source: ".gitignore", Line: 1, Pattern: "*.log", Path: "debug.log", Matched: true},
+		{Source: ".gitignore", Line: 2, Pattern: "!keep.log", Path: "keep.log", Matched: true, Negate: true},
+		{Source: ".gitignore", Line: 3, Pattern: "build/", Path: "build/", Matched: true},
+		{Path: "main.go"},
+		{Source: ".gitignore", Line: 1, Pattern: "*.log", Path: "tab\there.log", Matched: true},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("CheckIgnore() returned %+v, want %+v", results, want)
+	}
+
+	formats := []string{
+		".gitignore:1:*.log\tdebug.log",
+		".gitignore:2:!keep.log\tkeep.log",
+		".gitignore:3:build/\tbuild/",
+		"::\tmain.go",
+		".gitignore:1:*.log\t\"tab\\there.log\"",
+	}
+	for i, r := range results {
+		if got := r.Format(); got != formats[i] {
+			t.Errorf("Format() returned %q, want %q", got, formats[i])
+		}
+	}
+	if !results[0].Ignored() || results[1].Ignored() || results[3].Ignored() {
+		t.Errorf("Ignored() does not match the decisions")
+	}
+}
+
+func TestQuotePath(t *testing.T) {
+	for _, test := range []struct {
+		name, want string
+	}{
+		{"plain/path.txt", "plain/path.txt"},
+		{"with space", "with space"},
+		{"quote\"d", `"quote\"d"`},
+		{"back\\slash", `"back\\slash"`},
+		{"new\nline", `"new\nline"`},
+		{"café", `"caf\303\251"`},
+	} {
+		if got := quotePath(test.name); got != test.want {
+			t.Errorf("quotePath(%q) returned %s, want %s", test.name, got, test.want)
+		}
+	}
+}
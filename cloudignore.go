@@ -0,0 +1,181 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// helmDefaultRule is the rule Helm adds to every .helmignore file, ignoring
+// hidden files in the templates directory.
+const helmDefaultRule = "templates/.?*"
+
+// HelmIgnore compiles a PathSpec from Helm .helmignore lines, including the
+// rule "templates/.?*" Helm adds by default. Helm implements its own
+// dialect, which this function follows:
+//
+// Lines are trimmed on both sides, so an indented "#" starts a comment as
+// well.
+//
+// Patterns are matched with path.Match, against the base name of a path if
+// they contain no slash, otherwise against the whole path. A leading slash
+// is stripped and only anchors a pattern without further slashes. A trailing
+// slash matches directories only. "**" is not supported.
+//
+// A negated pattern "!p" does not re-include paths, but ignores every path p
+// does not match. A path is ignored if any rule ignores it, regardless of
+// order.
+func HelmIgnore(lines ...string) (*PathSpec, error) {
+	ps, err := compileLinesWith("", lines, trimmedPatternFromLine, newHelmPattern)
+	if err != nil {
+		return nil, err
+	}
+	def, err := newHelmPattern(helmDefaultRule)
+	if err != nil {
+		return nil, err
+	}
+	ps.setPatterns(concatPatterns([]*Pattern{def}, ps.patterns))
+	return ps, nil
+}
+
+// FromHelmignore compiles a PathSpec from a Helm .helmignore file, line by
+// line. See HelmIgnore for the syntax.
+func FromHelmignore(r io.Reader) (*PathSpec, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return HelmIgnore(lines...)
+}
+
+// newHelmPattern compiles a single .helmignore rule. Negated rules are
+// compiled into patterns matching the paths the rule does not match, so no
+// pattern of a Helm PathSpec is negated.
+func newHelmPattern(line string) (*Pattern, error) {
+	m := &helmMatcher{pattern: line}
+	if strings.Contains(line, "**") {
+		return nil, errors.New("double-star (**) syntax is not supported")
+	}
+	if strings.HasPrefix(m.pattern, "!") && len(m.pattern) > 1 {
+		m.negate = true
+		m.pattern = m.pattern[1:]
+	}
+	if strings.HasSuffix(m.pattern, "/") {
+		m.dir = true
+		m.pattern = strings.TrimSuffix(m.pattern, "/")
+	}
+	if strings.HasPrefix(m.pattern, "/") {
+		m.pattern = m.pattern[1:]
+		m.full = true
+	} else {
+		m.full = strings.Contains(m.pattern, "/")
+	}
+	if _, err := path.Match(m.pattern, "abc"); err != nil {
+		return nil, err
+	}
+	return &Pattern{syntax: "helmignore", text: line, matcher: m, dir: m.dir}, nil
+}
+
+// helmMatcher matches a .helmignore rule like Helm does.
+type helmMatcher struct {
+	pattern string
+	negate  bool
+	dir     bool
+	full    bool
+}
+
+// Match reports whether the rule ignores name.
+func (m *helmMatcher) Match(name string) bool {
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.TrimSuffix(name, "/")
+	if m.dir && !isDir {
+		// Helm ignores all files for negated directory rules.
+		return m.negate
+	}
+	if !m.full {
+		name = path.Base(name)
+	}
+	ok, _ := path.Match(m.pattern, name)
+	return ok != m.negate
+}
+
+// gcloudIncludeDirective includes the patterns of another file into a
+// .gcloudignore file.
+const gcloudIncludeDirective = "#!include:"
+
+// FromGcloudignore compiles a PathSpec from the .gcloudignore file name of
+// fsys. The syntax is gitignore, plus a "#!include:file" directive at the
+// start of a line which inserts the patterns of file, relative to the
+// directory of name, in its place. Like in gcloud, included files must not
+// include other files. The patterns remember the file they were read from as
+// their source.
+func FromGcloudignore(fsys fs.FS, name string) (*PathSpec, error) {
+	return readGcloudignore(fsys, name, true)
+}
+
+// readGcloudignore compiles the .gcloudignore file name of fsys, resolving
+// include directives if include is true.
+func readGcloudignore(fsys fs.FS, name string, include bool) (*PathSpec, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines, err := readLines(f)
+	if err != nil {
+		return nil, err
+	}
+	// Include directives are comments for compileLines.
+	ps, err := compileLines(name, lines, NewPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []*Pattern
+	rest := ps.patterns
+	for i, line := range lines {
+		// Like comments, directives start at the very start of a line;
+		// an indented one is a pattern.
+		if !strings.HasPrefix(line, gcloudIncludeDirective) {
+			continue
+		}
+		if !include {
+			err := fmt.Errorf("cannot include %q from an included file", line[len(gcloudIncludeDirective):])
+			return nil, ParseErrors{{Source: name, Line: i + 1, Pattern: line, Err: err}}
+		}
+		included := path.Join(path.Dir(name), strings.TrimSpace(line[len(gcloudIncludeDirective):]))
+		other, err := readGcloudignore(fsys, included, false)
+		if err != nil {
+			return nil, err
+		}
+		for len(rest) > 0 && rest[0].line < i+1 {
+			patterns = append(patterns, rest[0])
+			rest = rest[1:]
+		}
+		patterns = append(patterns, other.patterns...)
+	}
+	if patterns == nil {
+		return ps, nil
+	}
+	return NewPathSpec(append(patterns, rest...)...), nil
+}
@@ -0,0 +1,146 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHelmIgnore(t *testing.T) {
+	content := `# Patterns to ignore when building packages.
+.DS_Store
+*.tmp
+/secret.yaml
+ci/*.yaml
+.git/
+`
+	ps, err := FromHelmignore(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{".DS_Store", true},
+		{"templates/.DS_Store", true},
+		{"a.tmp", true},
+		{"templates/x/a.tmp", true},
+		{"secret.yaml", true},
+		{"charts/secret.yaml", false},
+		{"ci/values.yaml", true},
+		{"ci/a/values.yaml", false},
+		{"x/ci/values.yaml", false},
+		{".git/", true},
+		{".git", false},
+		{"templates/.hidden", true},
+		{"templates/deployment.yaml", false},
+	}
+	for _, test := range tests {
+		if got := ps.Match(test.name); got != test.want {
+			t.Errorf("HelmIgnore.Match('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+}
+
+func TestHelmIgnoreNegation(t *testing.T) {
+	ps, err := HelmIgnore("!*.yaml")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := map[string]bool{
+		"values.yaml": false,
+		"README.md":   true,
+	}
+	for name, want := range tests {
+		if got := ps.Match(name); got != want {
+			t.Errorf("HelmIgnore.Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+
+	if _, err := HelmIgnore("**/*.tmp"); err == nil {
+		t.Errorf("HelmIgnore('**/*.tmp') returned no error")
+	}
+}
+
+func TestFromGcloudignore(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/.gcloudignore": {Data: []byte(".gcloudignore\n#!include:.gitignore\n!keep.log\nnode_modules/\n")},
+		"app/.gitignore":    {Data: []byte("*.log\n.env\n")},
+		"bad/.gcloudignore": {Data: []byte("#!include:nested\n")},
+		"bad/nested":        {Data: []byte("#!include:other\n")},
+	}
+
+	ps, err := FromGcloudignore(fsys, "app/.gcloudignore")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := map[string]bool{
+		".gcloudignore":       true,
+		"debug.log":           true,
+		"keep.log":            false,
+		".env":                true,
+		"node_modules/x/y.js": true,
+		"main.go":             false,
+	}
+	for name, want := range tests {
+		if got := ps.Match(name); got != want {
+			t.Errorf("FromGcloudignore().Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+	if r := ps.MatchP("debug.log"); r == nil || r.Source != "app/.gitignore" || r.Line != 1 {
+		t.Errorf("FromGcloudignore().MatchP('debug.log') returned '%+v', want app/.gitignore line 1", r)
+	}
+
+	if _, err := FromGcloudignore(fsys, "bad/.gcloudignore"); err == nil {
+		t.Errorf("FromGcloudignore() with a nested include returned no error")
+	}
+
+	// An indented directive is a pattern, not an include.
+	fsys["indented/.gcloudignore"] = &fstest.MapFile{Data: []byte("  #!include:.gitignore\n")}
+	fsys["indented/.gitignore"] = &fstest.MapFile{Data: []byte("*.log\n")}
+	ps, err = FromGcloudignore(fsys, "indented/.gcloudignore")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if n := len(ps.Patterns()); n != 1 || ps.Match("debug.log") || !ps.Match("  #!include:.gitignore") {
+		t.Errorf("FromGcloudignore() with an indented directive returned %d patterns, want only the directive as a pattern", n)
+	}
+}
+
+func TestHelmIgnoreWhitespace(t *testing.T) {
+	ps, err := HelmIgnore("  foo", "   # comment", "\tbar  ")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	// The default rule ignoring hidden files comes first.
+	if n := len(ps.Patterns()); n != 3 {
+		t.Errorf("HelmIgnore() returned %d patterns, want 3", n)
+	}
+	for _, name := range []string{"foo", "bar"} {
+		if !ps.Match(name) {
+			t.Errorf("HelmIgnore().Match(%s) returned 'false', want 'true'", name)
+		}
+	}
+	if ps.Match("# comment") {
+		t.Errorf("HelmIgnore().Match('# comment') returned 'true', want 'false'")
+	}
+}
@@ -0,0 +1,106 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build cshared
+
+// Command libpathspec exports the gitignore matching of go-pathspec as a
+// shared C library, so tools written in other languages can match paths
+// exactly like git does. Build it with
+//
+//	go build -tags cshared -buildmode=c-shared -o libpathspec.so ./cmd/libpathspec
+//
+// which also writes the header libpathspec.h declaring these functions:
+//
+//	uintptr_t PathspecParse(char *text, char **err);
+//	int PathspecMatch(uintptr_t spec, char *path);
+//	void PathspecFree(uintptr_t spec);
+//
+// PathspecParse compiles the gitignore patterns in the NUL-terminated text
+// and returns a handle to them, which is never 0. For invalid patterns, it
+// returns 0 and, unless err is NULL, stores a message in *err, which the
+// caller must release with free.
+//
+// PathspecMatch reports whether git ignores the slash-separated path, which
+// is relative to the directory of the patterns. Directories are denoted by a
+// trailing slash, like "build/". Like git, paths inside ignored directories
+// are ignored, too. It returns 1 if the path is ignored, 0 if it is not, and
+// -1 if spec is not a valid handle.
+//
+// PathspecFree releases the patterns of the handle spec, which must not be
+// used afterwards. The functions are safe for concurrent use.
+package main
+
+// #include <stdint.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/shibumi/go-pathspec"
+)
+
+// specs maps the handles returned by PathspecParse to their PathSpecs.
+var specs struct {
+	sync.RWMutex
+	m    map[C.uintptr_t]*pathspec.PathSpec
+	next C.uintptr_t
+}
+
+//export PathspecParse
+func PathspecParse(text *C.char, err **C.char) C.uintptr_t {
+	ps, e := pathspec.FromReader(strings.NewReader(C.GoString(text)))
+	if e != nil {
+		if err != nil {
+			*err = C.CString(e.Error())
+		}
+		return 0
+	}
+	specs.Lock()
+	defer specs.Unlock()
+	if specs.m == nil {
+		specs.m = make(map[C.uintptr_t]*pathspec.PathSpec)
+	}
+	specs.next++
+	specs.m[specs.next] = ps
+	return specs.next
+}
+
+//export PathspecMatch
+func PathspecMatch(spec C.uintptr_t, path *C.char) C.int {
+	specs.RLock()
+	ps := specs.m[spec]
+	specs.RUnlock()
+	switch {
+	case ps == nil:
+		return -1
+	case ps.MatchGit(C.GoString(path)):
+		return 1
+	default:
+		return 0
+	}
+}
+
+//export PathspecFree
+func PathspecFree(spec C.uintptr_t) {
+	specs.Lock()
+	delete(specs.m, spec)
+	specs.Unlock()
+}
+
+func main() {}
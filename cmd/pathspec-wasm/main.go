@@ -0,0 +1,154 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build js && wasm
+
+// Command pathspec-wasm exposes the gitignore matching of go-pathspec to
+// JavaScript, so .gitignore files can be evaluated in the browser with the
+// same semantics as in Go. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o pathspec.wasm ./cmd/pathspec-wasm
+//
+// and run it with the wasm_exec.js support file of the Go distribution. It
+// defines a global object pathspec with a single function:
+//
+//	pathspec.parse(text)
+//
+// parse compiles the gitignore patterns in text and returns a spec object.
+// Go functions cannot throw JavaScript exceptions, so for invalid patterns or
+// arguments, the functions return an Error instead. The spec object has these
+// methods:
+//
+//	spec.match(path)   // true if git ignores path
+//	spec.explain(path) // how the patterns decided about path
+//	spec.release()     // frees the spec; it must not be used afterwards
+//
+// Paths are slash-separated and relative to the directory of the patterns;
+// directories are denoted by a trailing slash, like "build/". Like git, match
+// also ignores paths inside ignored directories. explain returns an object
+// {name, state, decider, steps}, where state is "unmatched", "ignored" or
+// "included", decider describes the deciding pattern or is null, and steps
+// lists the outcome of every pattern, see pathspec.Explain.
+package main
+
+import (
+	"strings"
+	"syscall/js"
+
+	"github.com/shibumi/go-pathspec"
+)
+
+func main() {
+	js.Global().Set("pathspec", map[string]interface{}{
+		"parse": js.FuncOf(parse),
+	})
+	select {}
+}
+
+// parse implements pathspec.parse.
+func parse(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return jsError("parse: text must be a string")
+	}
+	ps, err := pathspec.FromReader(strings.NewReader(args[0].String()))
+	if err != nil {
+		return jsError(err.Error())
+	}
+	return newSpec(ps)
+}
+
+// newSpec returns the JavaScript spec object wrapping ps.
+func newSpec(ps *pathspec.PathSpec) js.Value {
+	spec := js.Global().Get("Object").New()
+	var funcs []js.Func
+	method := func(name string, fn func(args []js.Value) interface{}) {
+		f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return fn(args)
+		})
+		funcs = append(funcs, f)
+		spec.Set(name, f)
+	}
+	method("match", func(args []js.Value) interface{} {
+		name, ok := pathArg("match", args)
+		if !ok {
+			return name
+		}
+		return ps.MatchGit(name.(string))
+	})
+	method("explain", func(args []js.Value) interface{} {
+		name, ok := pathArg("explain", args)
+		if !ok {
+			return name
+		}
+		return explain(ps, name.(string))
+	})
+	method("release", func(args []js.Value) interface{} {
+		for _, f := range funcs {
+			f.Release()
+		}
+		return nil
+	})
+	return spec
+}
+
+// pathArg returns the path argument of the method name, or an Error if it is
+// missing.
+func pathArg(name string, args []js.Value) (interface{}, bool) {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return jsError(name + ": path must be a string"), false
+	}
+	return args[0].String(), true
+}
+
+// explain converts the explanation of the decision about name into a
+// JavaScript object.
+func explain(ps *pathspec.PathSpec, name string) interface{} {
+	e := ps.Explain(name)
+	steps := make([]interface{}, len(e.Steps))
+	for i, step := range e.Steps {
+		steps[i] = map[string]interface{}{
+			"pattern": pattern(step.Pattern),
+			"outcome": step.Outcome.String(),
+			"state":   step.State.String(),
+		}
+	}
+	var decider interface{}
+	if e.Decider != nil {
+		decider = pattern(e.Decider)
+	}
+	return map[string]interface{}{
+		"name":    e.Name,
+		"state":   e.State.String(),
+		"decider": decider,
+		"steps":   steps,
+	}
+}
+
+// pattern converts p into a JavaScript object.
+func pattern(p *pathspec.Pattern) map[string]interface{} {
+	return map[string]interface{}{
+		"pattern": p.String(),
+		"source":  p.Source(),
+		"line":    p.Line(),
+		"negate":  p.Negate(),
+	}
+}
+
+// jsError returns a JavaScript Error with message.
+func jsError(message string) js.Value {
+	return js.Global().Get("Error").New(message)
+}
@@ -0,0 +1,191 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Command pathspec checks paths against gitignore files, much like
+// "git check-ignore" does, but without requiring a git repository.
+//
+// Usage:
+//
+//	pathspec check [-v] [-n] [-f file]... path...
+//	pathspec filter [-ignored] [-z] [-f file]...
+//	pathspec explain [-f file]... path...
+//
+// The check subcommand prints the given paths which are ignored. With -v it
+// prints the deciding pattern in the format of "git check-ignore --verbose",
+// that is "<source>:<linenum>:<pattern><TAB><path>", and with -n it also
+// prints paths no pattern matched. The filter subcommand reads a list of
+// paths from standard input and prints the included ones, or with -ignored
+// the ignored ones. The explain subcommand prints every pattern matching the
+// given paths and the final decision.
+//
+// Ignore files are given with -f and default to ".gitignore". Patterns of
+// later files take precedence over patterns of earlier files. Paths which are
+// existing directories, or which end with a slash, are matched as
+// directories.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shibumi/go-pathspec"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// fileList collects the values of a repeatable flag.
+type fileList []string
+
+func (l *fileList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *fileList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// run executes the command line args and returns the exit status: 0 if a
+// path was ignored or filtering succeeded, 1 if no path was ignored and 128
+// on errors, like git check-ignore.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: pathspec check|filter|explain [flags] [path...]")
+		return 128
+	}
+
+	var files fileList
+	var verbose, nonMatching, ignored, nul bool
+	flags := flag.NewFlagSet("pathspec "+args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Var(&files, "f", "read patterns from `file`, may be repeated")
+	switch args[0] {
+	case "check":
+		flags.BoolVar(&verbose, "v", false, "print the matching pattern of each path")
+		flags.BoolVar(&nonMatching, "n", false, "print paths which match no pattern, requires -v")
+	case "filter":
+		flags.BoolVar(&ignored, "ignored", false, "print ignored instead of included paths")
+		flags.BoolVar(&nul, "z", false, "paths are separated by NUL instead of newline characters")
+	case "explain":
+	default:
+		fmt.Fprintf(stderr, "pathspec: unknown subcommand %q\n", args[0])
+		return 128
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 128
+	}
+	if len(files) == 0 {
+		files = fileList{".gitignore"}
+	}
+
+	ps, err := loadFiles(files)
+	if err != nil {
+		fmt.Fprintf(stderr, "pathspec: %s\n", err)
+		return 128
+	}
+
+	switch args[0] {
+	case "check":
+		return check(ps, flags.Args(), verbose, nonMatching, stdout)
+	case "filter":
+		return filter(ps, stdin, stdout, ignored, nul, stderr)
+	default:
+		return explain(ps, flags.Args(), stdout)
+	}
+}
+
+// loadFiles compiles the given ignore files into a single PathSpec.
+func loadFiles(files []string) (*pathspec.PathSpec, error) {
+	var specs []*pathspec.PathSpec
+	for _, name := range files {
+		ps, err := pathspec.FromFile(name)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, ps)
+	}
+	return pathspec.Merge(specs...), nil
+}
+
+// matchName returns name with a trailing slash if it is a directory.
+func matchName(name string) string {
+	if fi, err := os.Stat(name); err == nil && fi.IsDir() && !strings.HasSuffix(name, "/") {
+		return name + "/"
+	}
+	return name
+}
+
+func check(ps *pathspec.PathSpec, names []string, verbose, nonMatching bool, w io.Writer) int {
+	status := 1
+	for _, name := range names {
+		result := ps.CheckIgnore([]string{matchName(name)})[0]
+		result.Path = name
+		if result.Ignored() {
+			status = 0
+		}
+		switch {
+		case verbose && (result.Matched || nonMatching):
+			fmt.Fprintln(w, result.Format())
+		case !verbose && result.Ignored():
+			fmt.Fprintln(w, name)
+		}
+	}
+	return status
+}
+
+func filter(ps *pathspec.PathSpec, r io.Reader, w io.Writer, ignored, nul bool, stderr io.Writer) int {
+	sep := byte('\n')
+	if nul {
+		sep = 0
+	}
+	var err error
+	if ignored {
+		err = ps.FilterIgnoredStream(r, w, sep)
+	} else {
+		err = ps.FilterStream(r, w, sep)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "pathspec: %s\n", err)
+		return 128
+	}
+	return 0
+}
+
+func explain(ps *pathspec.PathSpec, names []string, w io.Writer) int {
+	status := 1
+	for _, name := range names {
+		state := pathspec.StateUnmatched
+		for _, entry := range ps.Trace(matchName(name)) {
+			if !entry.Matched {
+				continue
+			}
+			p := entry.Pattern
+			fmt.Fprintf(w, "%s:%d:%s\t%s\t%s\n", p.Source(), p.Line(), p, name, entry.State)
+			state = entry.State
+		}
+		fmt.Fprintf(w, "%s: %s\n", name, state)
+		if state == pathspec.StateIgnored {
+			status = 0
+		}
+	}
+	return status
+}
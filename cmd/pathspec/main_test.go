@@ -0,0 +1,60 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ignore")
+	if err := os.WriteFile(file, []byte("*.log\n!keep.log\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		args   []string
+		stdin  string
+		want   string
+		status int
+	}{
+		{[]string{"check", "-f", file, "a.log", "keep.log", "a.txt"}, "", "a.log\n", 0},
+		{[]string{"check", "-f", file, "a.txt"}, "", "", 1},
+		{[]string{"check", "-v", "-n", "-f", file, "a.log", "keep.log", "a.txt"}, "", file + ":1:*.log\ta.log\n" + file + ":2:!keep.log\tkeep.log\n::\ta.txt\n", 0},
+		{[]string{"filter", "-f", file}, "a.log\nkeep.log\na.txt\n", "keep.log\na.txt\n", 0},
+		{[]string{"filter", "-ignored", "-z", "-f", file}, "a.log\x00keep.log\x00b.log", "a.log\x00b.log\x00", 0},
+		{[]string{"explain", "-f", file, "keep.log"}, "", file + ":1:*.log\tkeep.log\tignored\n" + file + ":2:!keep.log\tkeep.log\tincluded\nkeep.log: included\n", 1},
+		{[]string{"unknown"}, "", "", 128},
+		{[]string{"check", "-f", file + ".missing", "a.log"}, "", "", 128},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+		if status != test.status {
+			t.Errorf("run(%q) returned status %d, want %d (stderr: %s)", test.args, status, test.status, stderr.String())
+		}
+		if stdout.String() != test.want {
+			t.Errorf("run(%q) printed %q, want %q", test.args, stdout.String(), test.want)
+		}
+	}
+}
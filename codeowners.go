@@ -0,0 +1,145 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// CodeownersRule is a single line of a CODEOWNERS file.
+type CodeownersRule struct {
+	// Pattern is the compiled path pattern.
+	Pattern *Pattern
+	// Owners lists the users, teams and email addresses owning the
+	// matching paths, in order. It is empty for rules removing owners.
+	Owners []string
+}
+
+// Codeowners matches paths against a GitHub CODEOWNERS file.
+type Codeowners struct {
+	rules []CodeownersRule
+	spec  *PathSpec
+	index map[*Pattern]int
+}
+
+// ParseCodeowners parses a CODEOWNERS file. Every line holds a pattern
+// followed by its owners, separated by white space. The patterns are
+// gitignore patterns, except that negations and bracket expressions are not
+// supported. Spaces in a pattern must be escaped with a backslash. Like in
+// gitignore, the last matching rule decides.
+func ParseCodeowners(r io.Reader) (*Codeowners, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	c := &Codeowners{index: make(map[*Pattern]int)}
+	var patterns []*Pattern
+	var errs ParseErrors
+	for i, line := range lines {
+		fields := splitCodeownersLine(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		p, err := newCodeownersPattern(fields[0])
+		if err != nil {
+			errs = append(errs, &ParseError{Line: i + 1, Pattern: fields[0], Err: err})
+			continue
+		}
+		p.line = i + 1
+		var owners []string
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			owners = append(owners, owner)
+		}
+		c.index[p] = len(c.rules)
+		c.rules = append(c.rules, CodeownersRule{Pattern: p, Owners: owners})
+		patterns = append(patterns, p)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	c.spec = NewPathSpec(patterns...)
+	return c, nil
+}
+
+// Rules returns the rules in the order of the file.
+func (c *Codeowners) Rules() []CodeownersRule {
+	return c.rules
+}
+
+// Match returns the last rule matching name, or nil if no rule matches.
+func (c *Codeowners) Match(name string) *CodeownersRule {
+	p := c.spec.lastMatch(c.spec.slashPath(name))
+	if p == nil {
+		return nil
+	}
+	return &c.rules[c.index[p]]
+}
+
+// Owners returns the owners of name, or nil if it has none.
+func (c *Codeowners) Owners(name string) []string {
+	if rule := c.Match(name); rule != nil {
+		return rule.Owners
+	}
+	return nil
+}
+
+// newCodeownersPattern compiles a single CODEOWNERS pattern.
+func newCodeownersPattern(pattern string) (*Pattern, error) {
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		return nil, errors.New("negated patterns are not supported")
+	case strings.Contains(pattern, "["):
+		return nil, errors.New("bracket expressions are not supported")
+	}
+	p, err := NewPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	p.syntax = "codeowners"
+	return p, nil
+}
+
+// splitCodeownersLine splits a line into white space separated fields. A
+// backslash escapes a space, and "\#" is kept for the pattern compiler.
+func splitCodeownersLine(line string) []string {
+	var fields []string
+	var field strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && line[i+1] == ' ':
+			i++
+			field.WriteByte(' ')
+		case c == ' ' || c == '\t' || c == '\r':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteByte(c)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
@@ -0,0 +1,79 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCodeowners(t *testing.T) {
+	content := `# Default owners
+*       @org/everyone
+
+*.js    @js-owner #frontend
+/docs/  docs@example.com @org/writers
+apps/   @octocat
+/build/logs/
+My\ Documents/ @owner
+\#notes.md @notes
+`
+	c, err := ParseCodeowners(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"README.md", []string{"@org/everyone"}},
+		{"src/app.js", []string{"@js-owner"}},
+		{"docs/guide.md", []string{"docs@example.com", "@org/writers"}},
+		{"src/docs/guide.md", []string{"@org/everyone"}},
+		{"apps/web/app.js", []string{"@octocat"}},
+		{"build/logs/out.txt", nil},
+		{"My Documents/a.txt", []string{"@owner"}},
+		{"#notes.md", []string{"@notes"}},
+	}
+	for _, test := range tests {
+		got := c.Owners(test.name)
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("Owners('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+
+	if rule := c.Match("docs/x"); rule == nil || rule.Pattern.Line() != 5 {
+		t.Errorf("Match('docs/x') returned '%+v', want the rule in line 5", rule)
+	}
+	if len(c.Rules()) != 7 {
+		t.Errorf("Rules() returned %d rules, want 7", len(c.Rules()))
+	}
+}
+
+func TestCodeownersErrors(t *testing.T) {
+	_, err := ParseCodeowners(strings.NewReader("!*.js @a\n[ab].go @b\n*.go @c\n"))
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ParseCodeowners() returned '%v', want ParseErrors", err)
+	}
+	if len(errs) != 2 {
+		t.Errorf("ParseCodeowners() returned '%v', want 2 errors", err)
+	}
+}
@@ -0,0 +1,130 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CompiledSpec is a PathSpec whose patterns have been merged into few
+// regular expressions. Every run of consecutive patterns with the same
+// negation is combined into a single alternation, so a typical gitignore
+// file with ignore patterns followed by a few negations is matched with two
+// regular expression evaluations instead of one per pattern. Patterns
+// without a regular expression, like custom Matchers, are kept as they are.
+//
+// A CompiledSpec trades memory for speed and does not report which pattern
+// matched. It is created with PathSpec.Compile.
+type CompiledSpec struct {
+	groups   []compiledGroup
+	pathFunc func(string) string
+	tracked  func(string) bool
+}
+
+// compiledGroup is a run of consecutive patterns with the same negation.
+// Either regex or pattern is set.
+type compiledGroup struct {
+	negate  bool
+	regex   *regexp.Regexp
+	pattern *Pattern
+}
+
+// Compile merges the enabled patterns of the PathSpec into a CompiledSpec. It
+// fails if a merged regular expression exceeds the limits of the regexp
+// package.
+func (ps *PathSpec) Compile() (*CompiledSpec, error) {
+	cs := &CompiledSpec{pathFunc: ps.pathFunc, tracked: ps.tracked}
+	var exprs []string
+	flush := func(negate bool) error {
+		if len(exprs) == 0 {
+			return nil
+		}
+		regex, err := regexp.Compile("(?:" + strings.Join(exprs, ")|(?:") + ")")
+		if err != nil {
+			return err
+		}
+		cs.groups = append(cs.groups, compiledGroup{negate: negate, regex: regex})
+		exprs = exprs[:0]
+		return nil
+	}
+	patterns := ps.active()
+	for i, p := range patterns {
+		if i > 0 && p.negate != patterns[i-1].negate {
+			if err := flush(patterns[i-1].negate); err != nil {
+				return nil, err
+			}
+		}
+		if !p.hasRegex() {
+			if err := flush(p.negate); err != nil {
+				return nil, err
+			}
+			cs.groups = append(cs.groups, compiledGroup{negate: p.negate, pattern: p})
+			continue
+		}
+		exprs = append(exprs, p.regexString())
+	}
+	if len(patterns) > 0 {
+		if err := flush(patterns[len(patterns)-1].negate); err != nil {
+			return nil, err
+		}
+	}
+	return cs, nil
+}
+
+// Match reports whether name is ignored, exactly like PathSpec.Match.
+func (cs *CompiledSpec) Match(name string) bool {
+	return cs.MatchState(name) == StateIgnored
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (cs *CompiledSpec) MatchPath(name string, isDir bool) bool {
+	return cs.Match(dirName(name, isDir))
+}
+
+// MatchState is like Match, but distinguishes names no pattern matched from
+// names a negated pattern explicitly re-included.
+func (cs *CompiledSpec) MatchState(name string) MatchState {
+	if cs.pathFunc != nil {
+		name = cs.pathFunc(name)
+	} else {
+		name = filepath.ToSlash(name)
+	}
+	if isTracked(cs.tracked, name) {
+		return StateIncluded
+	}
+	for i := len(cs.groups) - 1; i >= 0; i-- {
+		g := cs.groups[i]
+		var matched bool
+		if g.regex != nil {
+			matched = g.regex.MatchString(name)
+		} else {
+			matched = g.pattern.match(name)
+		}
+		if !matched {
+			continue
+		}
+		if g.negate {
+			return StateIncluded
+		}
+		return StateIgnored
+	}
+	return StateUnmatched
+}
@@ -0,0 +1,57 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestPathSpecCompile(t *testing.T) {
+	lines := []string{"*.log", "build/", "/tmp", "!keep.log", "!build/keep", "keep/*.log", "!keep/important.log"}
+	names := []string{"a.log", "keep.log", "src/keep.log", "build/", "build/x", "build/keep", "tmp", "src/tmp", "keep/a.log", "keep/important.log", "main.go"}
+
+	suffix := &Pattern{text: "~", matcher: suffixMatcher("~")}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps = NewPathSpec(append(ps.Patterns(), suffix)...)
+
+	cs, err := ps.Compile()
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if len(cs.groups) != 5 {
+		t.Errorf("Compile('%s') returned %d groups, want 5", lines, len(cs.groups))
+	}
+
+	for _, name := range append(names, "a~") {
+		if got, want := cs.MatchState(name), ps.MatchState(name); got != want {
+			t.Errorf("CompiledSpec.MatchState('%s', %s) returned '%v', want '%v'", lines, name, got, want)
+		}
+	}
+
+	empty, err := NewPathSpec().Compile()
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if empty.Match("foo") {
+		t.Errorf("CompiledSpec.Match(foo) of an empty spec returned 'true', want 'false'")
+	}
+}
@@ -0,0 +1,89 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+// Append adds the patterns of other after the patterns of the PathSpec, so
+// they take precedence. Disabled patterns of other are not added. Append
+// must not be called concurrently with matching.
+func (ps *PathSpec) Append(other *PathSpec) {
+	ps.setPatterns(concatPatterns(ps.patterns, other.active()))
+}
+
+// AppendLines compiles gitignore lines and adds them after the patterns of
+// the PathSpec, so they take precedence. Blank lines and comments are
+// skipped. On error the PathSpec is left unchanged.
+func (ps *PathSpec) AppendLines(lines ...string) error {
+	other, err := FromLines(lines...)
+	if err != nil {
+		return err
+	}
+	ps.Append(other)
+	return nil
+}
+
+// Prepend adds the patterns of other before the patterns of the PathSpec, so
+// they are overridden by the patterns already present. Disabled patterns of
+// other are not added. Prepend must not be called concurrently with matching.
+func (ps *PathSpec) Prepend(other *PathSpec) {
+	ps.setPatterns(concatPatterns(other.active(), ps.patterns))
+}
+
+// Merge returns a new PathSpec with the patterns of all specs in order, so
+// patterns of later specs take precedence over patterns of earlier ones, just
+// like later lines of a gitignore file do. Disabled patterns are left out.
+// The result converts names like the first spec, see WithWindowsPaths, calls
+// its hooks and treats its tracked paths as tracked.
+func Merge(specs ...*PathSpec) *PathSpec {
+	var patterns []*Pattern
+	for _, ps := range specs {
+		patterns = concatPatterns(patterns, ps.active())
+	}
+	merged := NewPathSpec(patterns...)
+	if len(specs) > 0 {
+		merged.pathFunc = specs[0].pathFunc
+		merged.hooks = specs[0].hooks
+		merged.tracked = specs[0].tracked
+	}
+	return merged
+}
+
+// setPatterns replaces the patterns of the PathSpec, rebuilds its index and
+// invalidates its cache. Patterns which were disabled stay disabled.
+func (ps *PathSpec) setPatterns(patterns []*Pattern) {
+	ps.patterns = patterns
+	ps.index = newPatternIndex(patterns)
+	if len(ps.disabled) > 0 {
+		disabled := make(map[*Pattern]bool, len(ps.disabled))
+		for i, p := range patterns {
+			if ps.disabled[p] {
+				ps.index.remove(i, p)
+				disabled[p] = true
+			}
+		}
+		ps.disabled = disabled
+	}
+	ps.Invalidate()
+}
+
+// concatPatterns returns a new slice with the patterns of a followed by the
+// patterns of b, so neither slice is modified.
+func concatPatterns(a, b []*Pattern) []*Pattern {
+	patterns := make([]*Pattern, 0, len(a)+len(b))
+	patterns = append(patterns, a...)
+	return append(patterns, b...)
+}
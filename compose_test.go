@@ -0,0 +1,66 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestPathSpecCompose(t *testing.T) {
+	base, err := FromLines("*.log", "/build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	override, err := FromLines("!keep.log", "!/build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	merged := Merge(base, override)
+	if merged.Match("keep.log") || merged.Match("build/out") || !merged.Match("a.log") {
+		t.Errorf("Merge(base, override) does not let override take precedence")
+	}
+	if len(base.Patterns()) != 2 || len(override.Patterns()) != 2 {
+		t.Errorf("Merge(base, override) modified its arguments")
+	}
+
+	prepended := Merge(base)
+	prepended.Prepend(override)
+	if !prepended.Match("keep.log") || !prepended.Match("build/out") {
+		t.Errorf("Prepend(override) lets override take precedence")
+	}
+
+	appended := Merge(base)
+	appended.Append(override)
+	if appended.Match("keep.log") || appended.Match("build/out") {
+		t.Errorf("Append(override) does not let override take precedence")
+	}
+
+	if err := appended.AppendLines("# comment", "keep.log"); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !appended.Match("keep.log") {
+		t.Errorf("AppendLines(keep.log) does not let the lines take precedence")
+	}
+	if err := appended.AppendLines("!"); err == nil {
+		t.Errorf("AppendLines(!) returned no error, want one")
+	}
+	if len(appended.Patterns()) != 5 {
+		t.Errorf("AppendLines() resulted in %d patterns, want 5", len(appended.Patterns()))
+	}
+}
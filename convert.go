@@ -0,0 +1,138 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Dialect names a pattern syntax Convert translates between. Its value is the
+// name of the syntax, see Pattern.Syntax.
+type Dialect string
+
+const (
+	// DialectGitignore is the syntax of .gitignore files.
+	DialectGitignore Dialect = "gitwildmatch"
+	// DialectDockerignore is the syntax of .dockerignore files, see
+	// DockerIgnore.
+	DialectDockerignore Dialect = "dockerignore"
+)
+
+// Convert translates the patterns of spec, which may mix gitignore and
+// dockerignore patterns, into lines of the given dialect, in order, so a
+// .dockerignore file can be kept in sync with a .gitignore file or vice
+// versa. Patterns already written in the dialect are kept as they are.
+// Disabled patterns are left out.
+//
+// The translation preserves which paths are matched, except for two
+// caveats. Dockerignore cannot match directories only, so "build/" becomes
+// "**/build", which matches a regular file called build, too. And in a
+// dockerignore file, an exception can re-include a path beneath an excluded
+// directory, which git never does, see MatchGit.
+//
+// Patterns which cannot be translated, like those of other syntaxes or
+// gitignore patterns with leading or escaped trailing whitespace, which
+// Docker trims, are left out. Convert then returns the translated lines
+// together with a ParseErrors listing them, each wrapping
+// ErrUntranslatable.
+func Convert(spec *PathSpec, dialect Dialect) ([]string, error) {
+	if dialect != DialectGitignore && dialect != DialectDockerignore {
+		return nil, fmt.Errorf("unknown dialect %q", dialect)
+	}
+	var lines []string
+	var errs ParseErrors
+	for _, p := range spec.active() {
+		line, err := convertPattern(p, dialect)
+		if err != nil {
+			errs = append(errs, &ParseError{Source: p.source, Line: p.line, Pattern: p.text, Err: err})
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(errs) > 0 {
+		return lines, errs
+	}
+	return lines, nil
+}
+
+// convertPattern translates the pattern p into a line of dialect.
+func convertPattern(p *Pattern, dialect Dialect) (string, error) {
+	switch {
+	case Dialect(p.syntax) == dialect:
+		return p.text, nil
+	case p.syntax == "gitwildmatch":
+		return gitToDocker(p)
+	case p.syntax == "dockerignore":
+		return dockerToGit(p), nil
+	}
+	return "", fmt.Errorf("%s pattern: %w", p.syntax, ErrUntranslatable)
+}
+
+// gitToDocker translates the gitignore pattern p into a dockerignore line.
+func gitToDocker(p *Pattern) (string, error) {
+	text := p.text
+	negate := ""
+	if p.negate {
+		negate, text = "!", text[1:]
+	}
+	if strings.HasPrefix(text, " ") || strings.HasSuffix(text, "\\ ") {
+		return "", fmt.Errorf("docker trims whitespace: %w", ErrUntranslatable)
+	}
+	segs, _, err := NormalizePattern(text)
+	if err != nil {
+		return "", err
+	}
+	if p.dir {
+		// Docker matches everything beneath a matching directory.
+		segs = segs[:len(segs)-1]
+	}
+	if len(segs) == 0 {
+		// Only "/" itself, which matches everything.
+		segs = []string{"**"}
+	}
+	line := strings.Join(segs, "/")
+	if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		// Escape a comment or negation, Docker matches the character
+		// literally then.
+		line = "\\" + line
+	}
+	return negate + line, nil
+}
+
+// dockerToGit translates the dockerignore pattern p into a gitignore line.
+// Dockerignore patterns are anchored at the root and match everything
+// beneath a matching directory, which a gitignore pattern with a leading
+// slash does under git's rules.
+func dockerToGit(p *Pattern) string {
+	text := p.text
+	negate := ""
+	if p.negate {
+		negate, text = "!", strings.TrimSpace(text[1:])
+	}
+	text = strings.TrimPrefix(path.Clean(text), "/")
+	switch {
+	case text == "" || text == ".":
+		text = "**"
+	case text == "**" || strings.HasPrefix(text, "**/"):
+	default:
+		text = "/" + text
+	}
+	return negate + text
+}
@@ -0,0 +1,112 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// convertNames are the paths converted specs are compared on.
+var convertNames = []string{
+	"debug.log", "logs/debug.log", "build/", "build/out", "src/build/", "src/build/x",
+	"docs/index.md", "docs/guide/index.md", "#notes", "!important", "keep.log", "vendor/",
+	"vendor/x.go", "a/vendor/x.go",
+}
+
+func TestConvertToDockerignore(t *testing.T) {
+	lines := []string{"*.log", "!keep.log", "build/", "/docs/**/index.md", "/#notes", "\\!important", "/vendor"}
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	got, err := Convert(ps, DialectDockerignore)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := []string{"**/*.log", "!**/keep.log", "**/build", "docs/**/index.md", "\\#notes", "**/!important", "vendor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Convert('%v') returned '%v', want '%v'", lines, got, want)
+	}
+
+	docker, err := DockerIgnore(got...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for _, name := range convertNames {
+		if got, want := docker.Match(name), ps.MatchGit(name); got != want {
+			t.Errorf("Convert('%v') matches '%s' '%v', want '%v'", lines, name, got, want)
+		}
+	}
+}
+
+func TestConvertToGitignore(t *testing.T) {
+	lines := []string{"*.log", "!keep.log", "**/build", "docs/**/index.md", "/vendor/", "."}
+	ps, err := DockerIgnore(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	got, err := Convert(ps, DialectGitignore)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := []string{"/*.log", "!/keep.log", "**/build", "/docs/**/index.md", "/vendor", "**"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Convert('%v') returned '%v', want '%v'", lines, got, want)
+	}
+
+	ps, err = DockerIgnore(lines[:5]...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	git, err := FromLines(got[:5]...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for _, name := range convertNames {
+		if got, want := git.MatchGit(name), ps.Match(name); got != want {
+			t.Errorf("Convert('%v') matches '%s' '%v', want '%v'", lines, name, got, want)
+		}
+	}
+}
+
+func TestConvertUntranslatable(t *testing.T) {
+	git, err := FromLines("*.o", " leading", "trailing\\ ")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	regex, err := NewRegexPattern(`\.tmp$`)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps := Merge(git, NewPathSpec(regex))
+
+	got, err := Convert(ps, DialectDockerignore)
+	if want := []string{"**/*.o"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Convert() returned '%v', want '%v'", got, want)
+	}
+	var errs ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 3 || !errors.Is(err, ErrUntranslatable) {
+		t.Errorf("Convert() returned error '%v', want 3 untranslatable patterns", err)
+	}
+
+	if _, err := Convert(ps, Dialect("hgignore")); err == nil {
+		t.Errorf("Convert('hgignore') returned no error")
+	}
+}
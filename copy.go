@@ -0,0 +1,132 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// readLinkFS is implemented by file systems which can read the target of
+// symbolic links, like os.DirFS does since Go 1.25.
+type readLinkFS interface {
+	ReadLink(name string) (string, error)
+}
+
+// CopyDir copies the files and directories of src which spec does not ignore
+// into the directory dst, which is created if necessary. Ignored directories
+// are not descended into, like with Walk, which opts configure. A nil spec
+// copies everything.
+//
+// Files and directories keep their permission bits; existing files are
+// overwritten. Symbolic links are copied as links, which requires src to
+// implement "ReadLink(name string) (string, error)"; they are never
+// followed unless requested with WithFollowSymlinks. Other file types, like
+// devices and sockets, are skipped.
+func CopyDir(dst string, src fs.FS, spec *PathSpec, opts ...WalkOption) error {
+	if spec == nil {
+		spec = NewPathSpec()
+	}
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return err
+	}
+	// Directories are made writable while copying and get their
+	// permissions once their contents are complete, deepest first.
+	var dirs []string
+	var perms []fs.FileMode
+	err := spec.Walk(src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, filepath.FromSlash(name))
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return err
+			}
+			dirs = append(dirs, target)
+			perms = append(perms, mode.Perm())
+			return nil
+		case mode&fs.ModeSymlink != 0:
+			return copySymlink(target, src, name)
+		case mode.IsRegular():
+			return copyFile(target, src, name, mode.Perm())
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		return err
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i], perms[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile copies the regular file name of src to target, with the
+// permission bits perm.
+func copyFile(target string, src fs.FS, name string, perm fs.FileMode) (err error) {
+	in, err := src.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	// Chmod is not subject to the umask, unlike OpenFile.
+	return out.Chmod(perm)
+}
+
+// copySymlink creates a symbolic link at target pointing where the link name
+// of src points.
+func copySymlink(target string, src fs.FS, name string) error {
+	rfs, ok := src.(readLinkFS)
+	if !ok {
+		return fmt.Errorf("cannot copy symbolic link %s: file system cannot read links", name)
+	}
+	link, err := rfs.ReadLink(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(link, target)
+}
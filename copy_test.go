@@ -0,0 +1,117 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// linkFS is a MapFS which reads the targets of symbolic links from their
+// data.
+type linkFS struct {
+	fstest.MapFS
+}
+
+func (l linkFS) ReadLink(name string) (string, error) {
+	return string(l.MapFS[name].Data), nil
+}
+
+func TestCopyDir(t *testing.T) {
+	src := linkFS{fstest.MapFS{
+		"main.go":         {Data: []byte("package main"), Mode: 0o644},
+		"run.sh":          {Data: []byte("#!/bin/sh"), Mode: 0o755},
+		"debug.log":       {Data: []byte("log"), Mode: 0o644},
+		"build/out":       {Data: []byte("out"), Mode: 0o644},
+		"docs":            {Mode: fs.ModeDir | 0o755},
+		"docs/index.md":   {Data: []byte("# Docs"), Mode: 0o600},
+		"docs/latest.md":  {Data: []byte("index.md"), Mode: fs.ModeSymlink | 0o777},
+		"readonly":        {Mode: fs.ModeDir | 0o555},
+		"readonly/file":   {Data: []byte("ro"), Mode: 0o444},
+		"node_modules/xy": {Data: []byte("xy"), Mode: 0o644},
+	}}
+	spec, err := FromLines("*.log", "build/", "node_modules/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	dst := filepath.Join(t.TempDir(), "dst")
+	if err := CopyDir(dst, src, spec); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	defer os.Chmod(filepath.Join(dst, "readonly"), 0o755)
+
+	got := make(map[string]fs.FileMode)
+	err = filepath.WalkDir(dst, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == dst {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dst, name)
+		got[filepath.ToSlash(rel)] = info.Mode()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := map[string]fs.FileMode{
+		"main.go":        0o644,
+		"run.sh":         0o755,
+		"docs":           fs.ModeDir | 0o755,
+		"docs/index.md":  0o600,
+		"docs/latest.md": fs.ModeSymlink | 0o777,
+		"readonly":       fs.ModeDir | 0o555,
+		"readonly/file":  0o444,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CopyDir() copied '%v', want '%v'", got, want)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "docs/latest.md")); err != nil || link != "index.md" {
+		t.Errorf("CopyDir() linked docs/latest.md to '%s', want 'index.md'", link)
+	}
+	if data, err := os.ReadFile(filepath.Join(dst, "run.sh")); err != nil || string(data) != "#!/bin/sh" {
+		t.Errorf("CopyDir() wrote run.sh as '%s', want '#!/bin/sh'", data)
+	}
+
+	// Copying again overwrites the files.
+	if err := os.Chmod(filepath.Join(dst, "readonly"), 0o755); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := os.Chmod(filepath.Join(dst, "readonly/file"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := CopyDir(dst, src, spec); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+}
+
+func TestCopyDirSymlinkUnsupported(t *testing.T) {
+	// Only the methods of fs.FS are visible.
+	src := struct{ fs.FS }{fstest.MapFS{
+		"link": {Data: []byte("target"), Mode: fs.ModeSymlink | 0o777},
+	}}
+	if err := CopyDir(t.TempDir(), src, nil); err == nil {
+		t.Errorf("CopyDir() returned no error for a file system which cannot read links")
+	}
+}
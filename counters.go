@@ -0,0 +1,96 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// PatternHits is the number of times a pattern decided about a path.
+type PatternHits struct {
+	Pattern *Pattern
+	Hits    uint64
+}
+
+// patternCounters counts the decisions of every pattern. It is safe for
+// concurrent use, also while patterns are added or removed.
+type patternCounters struct {
+	hits sync.Map // *Pattern to *uint64
+}
+
+// hit counts a decision of p.
+func (c *patternCounters) hit(p *Pattern) {
+	n, ok := c.hits.Load(p)
+	if !ok {
+		n, _ = c.hits.LoadOrStore(p, new(uint64))
+	}
+	atomic.AddUint64(n.(*uint64), 1)
+}
+
+// get returns the number of decisions of p.
+func (c *patternCounters) get(p *Pattern) uint64 {
+	n, ok := c.hits.Load(p)
+	if !ok {
+		return 0
+	}
+	return atomic.LoadUint64(n.(*uint64))
+}
+
+// WithPatternStats counts how many times each pattern of the compiled
+// PathSpec decides about a path, see PatternStats.
+func WithPatternStats() Option {
+	return func(o *options) {
+		o.patternStats = true
+	}
+}
+
+// EnablePatternStats starts counting how many times each pattern decides
+// about a path, see PatternStats. Counting already enabled is not reset.
+// EnablePatternStats must not be called concurrently with matching.
+func (ps *PathSpec) EnablePatternStats() {
+	if ps.counters == nil {
+		ps.counters = &patternCounters{}
+	}
+}
+
+// ResetPatternStats sets the counts of all patterns to zero. It must not be
+// called concurrently with matching.
+func (ps *PathSpec) ResetPatternStats() {
+	if ps.counters != nil {
+		ps.counters = &patternCounters{}
+	}
+}
+
+// PatternStats returns how many times each pattern decided about a path
+// since counting was enabled, in the order of the patterns, or nil if
+// counting is not enabled. A pattern decides about a path if it is the last
+// pattern matching it, which includes the parent directories MatchGit
+// checks. Decisions served by the cache of WithCache are not counted, since
+// no pattern is matched for them. Patterns with zero hits are candidates for
+// removal from their ignore file.
+func (ps *PathSpec) PatternStats() []PatternHits {
+	if ps.counters == nil {
+		return nil
+	}
+	stats := make([]PatternHits, len(ps.patterns))
+	for i, p := range ps.patterns {
+		stats[i] = PatternHits{Pattern: p, Hits: ps.counters.get(p)}
+	}
+	return stats
+}
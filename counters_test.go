@@ -0,0 +1,104 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPatternStats(t *testing.T) {
+	ps, err := FromLinesWithOptions([]string{"*.log", "!keep.log", "build/", "*.tmp"}, WithPatternStats())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, name := range []string{"debug.log", "keep.log", "main.go", "build/", "logs/error.log"} {
+				ps.Match(name)
+			}
+		}()
+	}
+	wg.Wait()
+	ps.MatchGit("build/out/keep.log")
+
+	hits := func() []uint64 {
+		var hits []uint64
+		for _, s := range ps.PatternStats() {
+			hits = append(hits, s.Hits)
+		}
+		return hits
+	}
+	if got, want := hits(), []uint64{8, 4, 5, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PatternStats() returned '%v', want '%v'", got, want)
+	}
+
+	ps.ResetPatternStats()
+	ps.Match("debug.log")
+	if got, want := hits(), []uint64{1, 0, 0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PatternStats() after ResetPatternStats() returned '%v', want '%v'", got, want)
+	}
+
+	ps.Remove(0)
+	if got, want := hits(), []uint64{0, 0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PatternStats() after Remove(0) returned '%v', want '%v'", got, want)
+	}
+}
+
+func TestPatternStatsDisabled(t *testing.T) {
+	ps, err := FromLines("*.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps.Match("debug.log")
+	if stats := ps.PatternStats(); stats != nil {
+		t.Errorf("PatternStats() returned '%v', want nil", stats)
+	}
+	ps.EnablePatternStats()
+	ps.Match("debug.log")
+	if stats := ps.PatternStats(); len(stats) != 1 || stats[0].Hits != 1 {
+		t.Errorf("PatternStats() returned '%v', want one hit", stats)
+	}
+}
+
+func TestPatternStatsFilterStream(t *testing.T) {
+	ps, err := FromLinesWithOptions([]string{"*.log", "build/"}, WithPatternStats())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var out bytes.Buffer
+	if err := ps.FilterStream(strings.NewReader("a.log\nb.log\nmain.go\nbuild/x\n"), &out, '\n'); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if got := out.String(); got != "main.go\n" {
+		t.Errorf("FilterStream() wrote '%s', want 'main.go\\n'", got)
+	}
+	ps.MatchBytes([]byte("c.log"), false)
+	want := []uint64{3, 1}
+	for i, h := range ps.PatternStats() {
+		if h.Hits != want[i] {
+			t.Errorf("PatternStats()[%d] has %d hits for '%s', want %d", i, h.Hits, h.Pattern, want[i])
+		}
+	}
+}
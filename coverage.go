@@ -0,0 +1,113 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// Coverage lists the patterns of a PathSpec which have no effect on a file
+// tree, see AnalyzeCoverage. Each list is in the order of the patterns.
+type Coverage struct {
+	// Unmatched holds the patterns which match no file or directory of
+	// the tree, for example because they are stale.
+	Unmatched []*Pattern
+	// Shadowed holds the patterns which match paths, but ignore none of
+	// them which would not be ignored without them: earlier patterns
+	// already ignore the paths, or later patterns always decide instead.
+	Shadowed []*Pattern
+	// IneffectiveNegations holds the negated patterns which match paths,
+	// but re-include none of them, because no earlier pattern ignores
+	// them, later patterns always decide instead, or they lie in ignored
+	// directories, where git never re-includes anything.
+	IneffectiveNegations []*Pattern
+}
+
+// Empty reports whether all patterns have an effect.
+func (c *Coverage) Empty() bool {
+	return len(c.Unmatched) == 0 && len(c.Shadowed) == 0 && len(c.IneffectiveNegations) == 0
+}
+
+// AnalyzeCoverage walks all files and directories of fsys and reports the
+// patterns of spec which have no effect on them, as a basis for tidying up
+// ignore files. A pattern has an effect if removing it changes how MatchGit
+// decides about at least one path. The analysis only covers the given tree:
+// a pattern reported for it may still be needed for files created later,
+// like build outputs. Disabled patterns are not reported.
+func AnalyzeCoverage(fsys fs.FS, spec *PathSpec) (*Coverage, error) {
+	patterns := spec.patterns
+	matched := make([]bool, len(patterns))
+	effective := make([]bool, len(patterns))
+	// ignoredDir is the directory being walked, with a trailing slash,
+	// which the spec ignores. Nothing beneath it can be decided otherwise.
+	ignoredDir := ""
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		name = dirName(name, d.IsDir())
+		for i, p := range patterns {
+			if !matched[i] && !spec.disabled[p] && p.match(name) {
+				matched[i] = true
+			}
+		}
+		if ignoredDir != "" && strings.HasPrefix(name, ignoredDir) {
+			return nil
+		}
+		ignoredDir = ""
+		i := spec.findLastIndex(name)
+		if i < 0 {
+			return nil
+		}
+		if !effective[i] {
+			// Without the deciding pattern, the last earlier pattern
+			// matching name decides.
+			j := i - 1
+			for ; j >= 0; j-- {
+				if q := patterns[j]; !spec.disabled[q] && q.match(name) {
+					break
+				}
+			}
+			effective[i] = !patterns[i].negate != (patternState(spec.patternAt(j)) == StateIgnored)
+		}
+		if d.IsDir() && !patterns[i].negate {
+			ignoredDir = name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := &Coverage{}
+	for i, p := range patterns {
+		switch {
+		case spec.disabled[p] || effective[i]:
+		case !matched[i]:
+			c.Unmatched = append(c.Unmatched, p)
+		case p.negate:
+			c.IneffectiveNegations = append(c.IneffectiveNegations, p)
+		default:
+			c.Shadowed = append(c.Shadowed, p)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,95 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAnalyzeCoverage(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":         {},
+		"debug.log":       {},
+		"keep.log":        {},
+		"build/out.o":     {},
+		"build/keep.txt":  {},
+		"docs/index.md":   {},
+		"docs/draft.md":   {},
+		"vendor/x/x.go":   {},
+		"vendor/x/x.c":    {},
+		"logs/error.log":  {},
+		"notes/todo.txt":  {},
+		"notes/done.txt":  {},
+		"notes/index.txt": {},
+	}
+	lines := []string{
+		"*.log",           // effective
+		"!keep.log",       // effective
+		"debug.log",       // shadowed by *.log
+		"build/",          // effective
+		"!build/keep.txt", // ineffective, build/ is ignored
+		"*.o",             // shadowed, only in build/
+		"*.tmp",           // unmatched
+		"!main.go",        // ineffective, nothing ignores main.go
+		"docs/draft.md",   // effective
+		"notes/*.txt",     // overridden by the next pattern
+		"notes/",          // effective
+		"!vendor/",        // ineffective
+	}
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	c, err := AnalyzeCoverage(fsys, ps)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	texts := func(patterns []*Pattern) []string {
+		var texts []string
+		for _, p := range patterns {
+			texts = append(texts, p.String())
+		}
+		return texts
+	}
+	if got, want := texts(c.Unmatched), []string{"*.tmp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeCoverage() returned unmatched '%v', want '%v'", got, want)
+	}
+	if got, want := texts(c.Shadowed), []string{"debug.log", "*.o", "notes/*.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeCoverage() returned shadowed '%v', want '%v'", got, want)
+	}
+	if got, want := texts(c.IneffectiveNegations), []string{"!build/keep.txt", "!main.go", "!vendor/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeCoverage() returned ineffective negations '%v', want '%v'", got, want)
+	}
+	if c.Empty() {
+		t.Errorf("Empty() returned 'true', want 'false'")
+	}
+
+	ps.Disable(6)
+	ps.Disable(2)
+	if c, err = AnalyzeCoverage(fsys, ps); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if got, want := texts(c.Shadowed), []string{"*.o", "notes/*.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeCoverage() returned shadowed '%v', want '%v'", got, want)
+	}
+	if len(c.Unmatched) != 0 {
+		t.Errorf("AnalyzeCoverage() returned unmatched '%v', want none", texts(c.Unmatched))
+	}
+}
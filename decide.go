@@ -0,0 +1,67 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+// Decision is the tri-state outcome of matching a path, see MatchState.
+type Decision = MatchState
+
+const (
+	// Unmatched means no pattern matched the path, so a lower precedence
+	// source may decide about it.
+	Unmatched = StateUnmatched
+	// Ignored means the deciding pattern ignored the path.
+	Ignored = StateIgnored
+	// Included means the deciding pattern was a negation which explicitly
+	// re-included the path.
+	Included = StateIncluded
+)
+
+// Decider decides about paths. PathSpec, CompiledSpec, GitIgnoreTree,
+// SafeSpec and SpecStack implement it.
+type Decider interface {
+	Decide(name string) Decision
+}
+
+// Decide returns whether name is ignored, explicitly re-included or not
+// matched at all. Unlike Match, it lets callers layering several sources of
+// patterns fall through to the next source only for unmatched names.
+func (ps *PathSpec) Decide(name string) Decision {
+	return ps.MatchState(name)
+}
+
+// Decide is like PathSpec.Decide.
+func (cs *CompiledSpec) Decide(name string) Decision {
+	return cs.MatchState(name)
+}
+
+// Decide is like PathSpec.Decide.
+func (t *GitIgnoreTree) Decide(name string) Decision {
+	return t.MatchState(name)
+}
+
+// DecideLayers asks the layers in order of decreasing precedence and returns
+// the first decision other than Unmatched, like git consults a directory's
+// .gitignore before the global excludes file.
+func DecideLayers(name string, layers ...Decider) Decision {
+	for _, layer := range layers {
+		if d := layer.Decide(name); d != Unmatched {
+			return d
+		}
+	}
+	return Unmatched
+}
@@ -0,0 +1,70 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	ps, err := FromLines("*.log", "!keep.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := map[string]Decision{
+		"a.log":    Ignored,
+		"keep.log": Included,
+		"a.go":     Unmatched,
+	}
+	for name, want := range tests {
+		if got := ps.Decide(name); got != want {
+			t.Errorf("Decide('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
+
+func TestDecideLayers(t *testing.T) {
+	local, err := FromLines("!keep.tmp", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	global, err := FromLines("*.tmp", "*.swp")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	compiled, err := global.Compile()
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := map[string]Decision{
+		"keep.tmp":     Included,
+		"other.tmp":    Ignored,
+		"a.swp":        Ignored,
+		"build/":       Ignored,
+		"main.go":      Unmatched,
+		"src/a.tmp":    Ignored,
+		"src/keep.tmp": Included,
+	}
+	for name, want := range tests {
+		if got := DecideLayers(name, local, compiled); got != want {
+			t.Errorf("DecideLayers('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
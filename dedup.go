@@ -0,0 +1,174 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeKind classifies a NormalizeChange.
+type ChangeKind int
+
+const (
+	// ChangeDuplicate means a pattern was removed because a later pattern
+	// has the same text.
+	ChangeDuplicate ChangeKind = iota
+	// ChangeShadowed means a pattern was removed because a later pattern,
+	// written differently, matches exactly the same paths, like "**/foo"
+	// followed by "foo". The later pattern always decides first.
+	ChangeShadowed
+	// ChangeCollapsed means consecutive "**" segments of a pattern, which
+	// match the same as a single one, were collapsed.
+	ChangeCollapsed
+	// ChangeSubsumed means a pattern was removed because another pattern
+	// with the same negation matches all of its paths, like "build/" does
+	// for "build/foo.txt" and "*.log" for "debug.log", see Simplify.
+	ChangeSubsumed
+)
+
+// String returns a short name of the kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeDuplicate:
+		return "duplicate"
+	case ChangeShadowed:
+		return "shadowed"
+	case ChangeCollapsed:
+		return "collapsed"
+	case ChangeSubsumed:
+		return "subsumed"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// NormalizeChange describes a change Normalize or Simplify made to a
+// PathSpec.
+type NormalizeChange struct {
+	// Kind classifies the change.
+	Kind ChangeKind
+	// Pattern is the pattern which was removed or rewritten, as it was
+	// before Normalize.
+	Pattern *Pattern
+	// Replacement is the rewritten pattern of a ChangeCollapsed, and the
+	// pattern making Pattern redundant for the other kinds. Only for a
+	// ChangeSubsumed, it may be an earlier pattern.
+	Replacement *Pattern
+}
+
+// String formats the change like "duplicate: *.o (line 3) by *.o (line 9)".
+func (c NormalizeChange) String() string {
+	verb := "by"
+	if c.Kind == ChangeCollapsed {
+		verb = "to"
+	}
+	return fmt.Sprintf("%s: %s %s %s", c.Kind, describePattern(c.Pattern), verb, describePattern(c.Replacement))
+}
+
+// describePattern formats p with its position, if known.
+func describePattern(p *Pattern) string {
+	if p.line == 0 {
+		return p.text
+	}
+	return fmt.Sprintf("%s (line %d)", p.text, p.line)
+}
+
+// Normalize removes redundant patterns from the PathSpec without changing
+// what it matches: patterns followed by a later pattern which matches exactly
+// the same paths are removed, since the later pattern always decides first,
+// and consecutive "**" segments are collapsed into one. It returns the
+// changes in the order of the patterns; a removed pattern is only reported
+// as removed, even if it was collapsed, too. Disabled patterns are kept and
+// do not make other patterns redundant. Normalize must not be called
+// concurrently with matching.
+func (ps *PathSpec) Normalize() []NormalizeChange {
+	patterns := make([]*Pattern, len(ps.patterns))
+	last := make(map[string]int, len(patterns))
+	for i, p := range ps.patterns {
+		patterns[i] = collapseDoubleStar(p)
+		if !ps.disabled[p] {
+			last[equivalenceKey(patterns[i])] = i
+		}
+	}
+
+	var changes []NormalizeChange
+	kept := patterns[:0:0]
+	for i, p := range patterns {
+		orig := ps.patterns[i]
+		if j, ok := last[equivalenceKey(p)]; ok && j != i && !ps.disabled[orig] {
+			later := patterns[j]
+			kind := ChangeShadowed
+			if p.text == later.text {
+				kind = ChangeDuplicate
+			}
+			changes = append(changes, NormalizeChange{Kind: kind, Pattern: orig, Replacement: later})
+			continue
+		}
+		if p != orig {
+			changes = append(changes, NormalizeChange{Kind: ChangeCollapsed, Pattern: orig, Replacement: p})
+			if ps.disabled[orig] {
+				delete(ps.disabled, orig)
+				ps.disabled[p] = true
+			}
+		}
+		kept = append(kept, p)
+	}
+	if len(changes) > 0 {
+		ps.setPatterns(kept)
+	}
+	return changes
+}
+
+// equivalenceKey returns a key which is equal for patterns matching the same
+// paths. Patterns with a regular expression are compared by it, others by
+// their syntax and text.
+func equivalenceKey(p *Pattern) string {
+	if p.matcher == nil && p.hasRegex() {
+		return "regex\x00" + p.regexString()
+	}
+	return fmt.Sprintf("%s\x00%t\x00%s", p.syntax, p.negate, p.text)
+}
+
+// collapseDoubleStar returns a copy of the gitignore pattern p with
+// consecutive "**" segments collapsed, or p itself if there are none. The
+// regular expression already is the same, see NormalizePattern.
+func collapseDoubleStar(p *Pattern) *Pattern {
+	if p.syntax != "gitwildmatch" || !strings.Contains(p.text, "**/**") {
+		return p
+	}
+	text := p.text
+	negate := ""
+	if p.negate {
+		negate, text = "!", text[1:]
+	}
+	segs := strings.Split(text, "/")
+	collapsed := segs[:1]
+	for _, seg := range segs[1:] {
+		if seg == "**" && collapsed[len(collapsed)-1] == "**" {
+			continue
+		}
+		collapsed = append(collapsed, seg)
+	}
+	if len(collapsed) == len(segs) {
+		return p
+	}
+	q := *p
+	q.text = negate + strings.Join(collapsed, "/")
+	return &q
+}
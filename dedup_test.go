@@ -0,0 +1,85 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathSpecNormalize(t *testing.T) {
+	ps, err := FromLines(
+		"*.o",       // 1: duplicate of 4
+		"a/**/**/b", // 2: collapsed
+		"!keep.o",   // 3
+		"*.o",       // 4
+		"**/tmp",    // 5: shadowed by 6
+		"tmp",       // 6
+		"!**/**/x",  // 7: collapsed, duplicate of 8 after collapsing
+		"!**/x",     // 8
+		"/build/",   // 9
+		"!/build/",  // 10: same paths as 9, so 9 never decides
+	)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	names := []string{"a.o", "keep.o", "a/b", "a/c/b", "tmp", "d/tmp", "x", "d/x", "build/", "build/main.o"}
+	before := make([]MatchState, len(names))
+	for i, name := range names {
+		before[i] = ps.MatchState(name)
+	}
+
+	changes := ps.Normalize()
+	type change struct {
+		kind ChangeKind
+		line int
+		text string
+	}
+	var got []change
+	for _, c := range changes {
+		got = append(got, change{c.Kind, c.Pattern.Line(), c.Replacement.String()})
+	}
+	want := []change{
+		{ChangeDuplicate, 1, "*.o"},
+		{ChangeCollapsed, 2, "a/**/b"},
+		{ChangeShadowed, 5, "tmp"},
+		{ChangeDuplicate, 7, "!**/x"},
+		{ChangeShadowed, 9, "!/build/"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize() returned %v, want %v", got, want)
+	}
+
+	var texts []string
+	for _, p := range ps.Patterns() {
+		texts = append(texts, p.String())
+	}
+	wantTexts := []string{"a/**/b", "!keep.o", "*.o", "tmp", "!**/x", "!/build/"}
+	if !reflect.DeepEqual(texts, wantTexts) {
+		t.Errorf("Patterns() after Normalize() returned %v, want %v", texts, wantTexts)
+	}
+	for i, name := range names {
+		if got := ps.MatchState(name); got != before[i] {
+			t.Errorf("MatchState('%s') after Normalize() returned '%v', want '%v'", name, got, before[i])
+		}
+	}
+
+	if changes := ps.Normalize(); len(changes) != 0 {
+		t.Errorf("Normalize() of a normalized spec returned %v", changes)
+	}
+}
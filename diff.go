@@ -0,0 +1,158 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"io/fs"
+	"sort"
+)
+
+// SpecDiff describes how the patterns of two PathSpecs differ, see Diff.
+type SpecDiff struct {
+	// Added are the patterns of the new spec missing from the old one.
+	Added []*Pattern
+	// Removed are the patterns of the old spec missing from the new one.
+	Removed []*Pattern
+	// Reordered are the patterns of the new spec which are also in the
+	// old one, but moved relative to the other patterns both specs have.
+	// Since the last matching pattern decides, moving a pattern may change
+	// what the spec matches.
+	Reordered []*Pattern
+}
+
+// Empty reports whether the specs have the same patterns in the same order.
+func (d SpecDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Reordered) == 0
+}
+
+// Diff compares the patterns of the old spec a with the patterns of the new
+// spec b. Patterns are equal if they have the same syntax and text, so a
+// pattern which only moved to another line is not reported. Repeated
+// patterns are paired up in order. The patterns of all fields are in the
+// order of the spec they are taken from.
+//
+// Diff compares the patterns as written. Use DiffDecisions to find out which
+// paths are decided differently.
+func Diff(a, b *PathSpec) SpecDiff {
+	type key struct{ syntax, text string }
+	keyOf := func(p *Pattern) key { return key{p.syntax, p.text} }
+
+	// Pair the n-th occurrence of a pattern in a with its n-th occurrence
+	// in b.
+	occurrences := make(map[key][]int)
+	for i, p := range a.patterns {
+		occurrences[keyOf(p)] = append(occurrences[keyOf(p)], i)
+	}
+	var d SpecDiff
+	paired := make(map[int]bool)
+	var common []int // positions in a of the common patterns, in the order of b
+	var commonB []*Pattern
+	for _, p := range b.patterns {
+		k := keyOf(p)
+		if len(occurrences[k]) == 0 {
+			d.Added = append(d.Added, p)
+			continue
+		}
+		i := occurrences[k][0]
+		occurrences[k] = occurrences[k][1:]
+		paired[i] = true
+		common = append(common, i)
+		commonB = append(commonB, p)
+	}
+	for i, p := range a.patterns {
+		if !paired[i] {
+			d.Removed = append(d.Removed, p)
+		}
+	}
+
+	// The common patterns which kept their relative order form the longest
+	// increasing subsequence of their positions in a; all others moved.
+	kept := longestIncreasing(common)
+	for j, p := range commonB {
+		if !kept[j] {
+			d.Reordered = append(d.Reordered, p)
+		}
+	}
+	return d
+}
+
+// longestIncreasing returns the indexes of a longest strictly increasing
+// subsequence of s.
+func longestIncreasing(s []int) map[int]bool {
+	// tails[k] is the index of the smallest tail of an increasing
+	// subsequence of length k+1, prev links each index to its predecessor.
+	var tails []int
+	prev := make([]int, len(s))
+	for i, v := range s {
+		k := sort.Search(len(tails), func(k int) bool { return s[tails[k]] >= v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	seq := make(map[int]bool, len(tails))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			seq[i] = true
+		}
+	}
+	return seq
+}
+
+// DecisionChange describes a path two PathSpecs decide differently about.
+type DecisionChange struct {
+	// Path is the slash-separated path, with a trailing slash for
+	// directories.
+	Path string
+	// Before is the decision of the old spec and After the decision of the
+	// new one.
+	Before, After Decision
+}
+
+// DiffDecisions walks fsys and returns the paths the old spec a and the new
+// spec b decide differently about, in lexical order. Like "git status", the
+// decisions take ignored parent directories into account, see MatchStateGit.
+// All of fsys is walked, including ignored directories, so changes below
+// them are found, too.
+func DiffDecisions(a, b *PathSpec, fsys fs.FS) ([]DecisionChange, error) {
+	var changes []DecisionChange
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		name = dirName(name, d.IsDir())
+		before, after := a.MatchStateGit(name), b.MatchStateGit(name)
+		if before != after {
+			changes = append(changes, DecisionChange{Path: name, Before: before, After: after})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
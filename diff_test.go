@@ -0,0 +1,96 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func patternTexts(patterns []*Pattern) []string {
+	var texts []string
+	for _, p := range patterns {
+		texts = append(texts, p.String())
+	}
+	return texts
+}
+
+func TestDiff(t *testing.T) {
+	a, err := FromLines("*.o", "!keep.o", "build/", "*.tmp", "*.log", "*.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	b, err := FromLines("# comment", "*.o", "build/", "*.tmp", "*.log", "dist/", "!keep.o")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	d := Diff(a, b)
+	if got, want := patternTexts(d.Added), []string{"dist/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() added %v, want %v", got, want)
+	}
+	if got, want := patternTexts(d.Removed), []string{"*.log"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() removed %v, want %v", got, want)
+	}
+	if got, want := patternTexts(d.Reordered), []string{"!keep.o"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() reordered %v, want %v", got, want)
+	}
+	if d.Empty() {
+		t.Errorf("Diff().Empty() returned true")
+	}
+	if d := Diff(a, a); !d.Empty() {
+		t.Errorf("Diff() of a spec with itself returned %+v", d)
+	}
+}
+
+func TestDiffDecisions(t *testing.T) {
+	a, err := FromLines("*.o", "!keep.o", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	b, err := FromLines("!keep.o", "*.o", "dist/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	fsys := fstest.MapFS{
+		"main.o":       {},
+		"keep.o":       {},
+		"main.c":       {},
+		"build/app":    {},
+		"dist/app.js":  {},
+		"src/keep.o":   {},
+		"src/other.go": {},
+	}
+
+	changes, err := DiffDecisions(a, b, fsys)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := []DecisionChange{
+		{Path: "build/", Before: Ignored, After: Unmatched},
+		{Path: "build/app", Before: Ignored, After: Unmatched},
+		{Path: "dist/", Before: Unmatched, After: Ignored},
+		{Path: "dist/app.js", Before: Unmatched, After: Ignored},
+		{Path: "keep.o", Before: Included, After: Ignored},
+		{Path: "src/keep.o", Before: Included, After: Ignored},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("DiffDecisions() returned %v, want %v", changes, want)
+	}
+}
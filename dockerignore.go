@@ -0,0 +1,84 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DockerIgnore compiles a PathSpec from .dockerignore lines. The syntax looks
+// like gitignore, but Docker interprets it differently:
+//
+// Lines are trimmed on both sides, so an indented "#" starts a comment as
+// well.
+//
+// Patterns are always relative to the root of the build context. "foo" only
+// matches "foo" in the root, not "a/foo", and a leading slash has no meaning.
+// Use "**/foo" to match "foo" in every directory.
+//
+// Patterns are cleaned like paths, so a trailing slash is dropped and "foo/"
+// matches a regular file "foo" as well. "a/./b" and "a//b" are "a/b".
+//
+// A pattern matching a directory also matches everything beneath it.
+//
+// A backslash escapes the next character, but there is no special escape
+// for a leading "!". Exceptions work like in gitignore: the last matching
+// pattern decides.
+func DockerIgnore(lines ...string) (*PathSpec, error) {
+	return compileLinesWith("", lines, trimmedPatternFromLine, newDockerPattern)
+}
+
+// FromDockerignore compiles a PathSpec from a .dockerignore file, line by
+// line. See DockerIgnore for the differences to gitignore.
+func FromDockerignore(r io.Reader) (*PathSpec, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return compileLinesWith("", lines, trimmedPatternFromLine, newDockerPattern)
+}
+
+// newDockerPattern compiles a single .dockerignore pattern.
+func newDockerPattern(line string) (*Pattern, error) {
+	pattern := line
+	negate := false
+	if strings.HasPrefix(pattern, "!") {
+		pattern = strings.TrimSpace(pattern[1:])
+		negate = true
+	}
+
+	// Docker cleans every pattern and strips the leading slash, so all
+	// patterns are anchored at the root of the build context.
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" || pattern == "." {
+		pattern = "**"
+	}
+
+	// A matching directory also matches all of its descendants.
+	segments := strings.Split(pattern, "/")
+	regex, err := regexp.Compile("^" + translateSegments(segments) + "(?:/.*)?$")
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{syntax: "dockerignore", text: line, negate: negate, regex: regex}, nil
+}
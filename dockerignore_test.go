@@ -0,0 +1,84 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDockerIgnore(t *testing.T) {
+	toInclude := []string{"docs/README.md", "main.c", "vendor/README.md", "src/keep.tmp", "sub/build/x"}
+	toIgnore := []string{"README.md", "main.go", "cmd/tool/main.go", "build", "build/out/bin", "src/a.tmp", "node_modules/x/y.js"}
+	lines := []string{"# comment", "**/*.go", "/README.md", "build/", "src/*.tmp", "!src/keep.tmp", "./node_modules"}
+
+	ps, err := DockerIgnore(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for _, f := range toInclude {
+		if ps.Match(f) {
+			t.Errorf("DockerIgnore('%s').Match(%s) returned 'true', want 'false'", lines, f)
+		}
+	}
+
+	for _, f := range toIgnore {
+		if !ps.Match(f) {
+			t.Errorf("DockerIgnore('%s').Match(%s) returned 'false', want 'true'", lines, f)
+		}
+	}
+}
+
+func TestFromDockerignore(t *testing.T) {
+	content := "# exclude everything but the sources\n*\n!src\nsrc/**/*_test.go\n"
+	toInclude := []string{"src", "src/main.go", "src/pkg/lib.go"}
+	toIgnore := []string{"Dockerfile", "docs/index.md", "src/pkg/lib_test.go"}
+
+	ps, err := FromDockerignore(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for _, f := range toInclude {
+		if ps.Match(f) {
+			t.Errorf("FromDockerignore().Match(%s) returned 'true', want 'false'", f)
+		}
+	}
+
+	for _, f := range toIgnore {
+		if !ps.Match(f) {
+			t.Errorf("FromDockerignore().Match(%s) returned 'false', want 'true'", f)
+		}
+	}
+}
+
+func TestDockerIgnoreWhitespace(t *testing.T) {
+	ps, err := FromDockerignore(strings.NewReader("  foo\n   # comment\n\tbar  \n"))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if n := len(ps.Patterns()); n != 2 {
+		t.Errorf("FromDockerignore() returned %d patterns, want 2", n)
+	}
+	for _, name := range []string{"foo", "bar"} {
+		if !ps.Match(name) {
+			t.Errorf("FromDockerignore().Match(%s) returned 'false', want 'true'", name)
+		}
+	}
+}
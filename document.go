@@ -0,0 +1,152 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"io"
+)
+
+// NodeKind classifies the lines of a Document.
+type NodeKind int
+
+const (
+	// NodeBlank is an empty line, or a line of spaces only.
+	NodeBlank NodeKind = iota
+	// NodeComment is a line starting with "#".
+	NodeComment
+	// NodePattern is a line holding a pattern.
+	NodePattern
+)
+
+// String returns a short name of the kind.
+func (k NodeKind) String() string {
+	switch k {
+	case NodeBlank:
+		return "blank"
+	case NodeComment:
+		return "comment"
+	case NodePattern:
+		return "pattern"
+	default:
+		return fmt.Sprintf("NodeKind(%d)", int(k))
+	}
+}
+
+// Node is a single line of a Document.
+type Node struct {
+	// Kind classifies the line.
+	Kind NodeKind
+	// Line is the line number, starting at 1.
+	Line int
+	// Text is the line as written, without the line terminator.
+	Text string
+	// Pattern is the compiled pattern of a NodePattern line, and nil for
+	// all other kinds.
+	Pattern *Pattern
+}
+
+// Document is a gitignore file parsed into its lines. Unlike a PathSpec,
+// which only keeps the patterns, a Document keeps comments and blank lines in
+// place, so tools formatting or editing ignore files can inspect them and
+// write them back without losing anything.
+type Document struct {
+	// Source is the file the document was read from, if any. The patterns
+	// remember it as their source.
+	Source string
+	// Nodes are the lines of the document in order.
+	Nodes []Node
+}
+
+// DocumentFromLines parses gitignore lines into a Document. If any pattern
+// fails to compile, the returned error is a ParseErrors listing all of them.
+func DocumentFromLines(lines ...string) (*Document, error) {
+	return parseDocument("", lines)
+}
+
+// ReadDocument parses a gitignore file into a Document, line by line. A
+// leading UTF-8 byte order mark is stripped from the first line.
+func ReadDocument(r io.Reader) (*Document, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseDocument("", lines)
+}
+
+// parseDocument parses the lines read from source into a Document.
+func parseDocument(source string, lines []string) (*Document, error) {
+	d := &Document{Source: source, Nodes: make([]Node, 0, len(lines))}
+	var errs ParseErrors
+	for i, line := range lines {
+		node := Node{Kind: lineKind(line), Line: i + 1, Text: line}
+		if node.Kind == NodePattern {
+			pattern, _ := patternFromLine(line)
+			p, err := NewPattern(pattern)
+			if err != nil {
+				errs = append(errs, &ParseError{Source: source, Line: i + 1, Pattern: pattern, Err: err})
+				continue
+			}
+			p.source = source
+			p.line = i + 1
+			node.Pattern = p
+		}
+		d.Nodes = append(d.Nodes, node)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return d, nil
+}
+
+// lineKind classifies a gitignore line the same way patternFromLine does.
+func lineKind(line string) NodeKind {
+	if _, ok := patternFromLine(line); ok {
+		return NodePattern
+	}
+	if len(line) > 0 && line[0] == '#' {
+		return NodeComment
+	}
+	return NodeBlank
+}
+
+// Lines returns the lines of the document as written.
+func (d *Document) Lines() []string {
+	lines := make([]string, len(d.Nodes))
+	for i, n := range d.Nodes {
+		lines[i] = n.Text
+	}
+	return lines
+}
+
+// Patterns returns the compiled patterns of the document in order.
+func (d *Document) Patterns() []*Pattern {
+	var patterns []*Pattern
+	for _, n := range d.Nodes {
+		if n.Kind == NodePattern {
+			patterns = append(patterns, n.Pattern)
+		}
+	}
+	return patterns
+}
+
+// PathSpec returns a PathSpec matching the patterns of the document. It
+// matches the same as the PathSpec FromLines compiles from the same lines.
+func (d *Document) PathSpec() *PathSpec {
+	return NewPathSpec(d.Patterns()...)
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	text := "# build output\n*.o\n\n  # not a comment\n!keep.o\n   \nbuild/\n"
+	d, err := ReadDocument(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	kinds := []NodeKind{NodeComment, NodePattern, NodeBlank, NodePattern, NodePattern, NodeBlank, NodePattern}
+	if len(d.Nodes) != len(kinds) {
+		t.Fatalf("ReadDocument() returned %d nodes, want %d", len(d.Nodes), len(kinds))
+	}
+	for i, n := range d.Nodes {
+		if n.Kind != kinds[i] || n.Line != i+1 {
+			t.Errorf("Node %d is %s on line %d, want %s on line %d", i, n.Kind, n.Line, kinds[i], i+1)
+		}
+		if (n.Pattern != nil) != (n.Kind == NodePattern) {
+			t.Errorf("Node %d of kind %s has pattern %v", i, n.Kind, n.Pattern)
+		}
+	}
+	if p := d.Nodes[4].Pattern; p.String() != "!keep.o" || p.Line() != 5 {
+		t.Errorf("Pattern on line 5 is '%s' from line %d", p, p.Line())
+	}
+
+	if got := strings.Join(d.Lines(), "\n") + "\n"; got != text {
+		t.Errorf("Lines() returned %q, want %q", got, text)
+	}
+
+	ps := d.PathSpec()
+	for name, want := range map[string]bool{"a.o": true, "keep.o": false, "build/": true, "main.c": false} {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
+
+func TestDocumentFromLinesErrors(t *testing.T) {
+	_, err := DocumentFromLines("# comment", "!", "*.o", "!")
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("DocumentFromLines() returned %v, want ParseErrors", err)
+	}
+	var lines []int
+	for _, e := range errs {
+		lines = append(lines, e.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{2, 4}) {
+		t.Errorf("DocumentFromLines() reported lines %v, want [2 4]", lines)
+	}
+}
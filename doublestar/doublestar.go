@@ -0,0 +1,368 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package doublestar provides the functions of github.com/bmatcuk/doublestar
+// backed by the minimatch dialect of pathspec, so projects can switch
+// libraries by changing an import path. MatchList adds gitignore style
+// negation on top.
+//
+// Patterns are matched against whole, slash-separated paths. "*" matches any
+// sequence of characters except slashes, "?" a single character except a
+// slash, and "**" as a whole path segment any number of directories,
+// including none. "[...]" matches a character class, negated with "^" or
+// "!", "{a,b}" matches either alternative, and a backslash escapes the next
+// character. Unlike with minimatch, names starting with a dot are matched by
+// wildcards, and "!" and parentheses have no special meaning.
+package doublestar
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	pathspec "github.com/shibumi/go-pathspec"
+)
+
+// ErrBadPattern indicates a pattern was malformed.
+var ErrBadPattern = path.ErrBadPattern
+
+// ErrPatternNotExist is returned by Glob and GlobWalk with
+// WithFailOnPatternNotExist if the static directory of the pattern does not
+// exist.
+var ErrPatternNotExist = errors.New("pattern does not exist")
+
+// matcher matches a compiled pattern. A pattern ending in "/**" is compiled
+// twice, with and without the suffix, since "a/**" also matches "a" itself.
+type matcher []*pathspec.Pattern
+
+// compile compiles a doublestar pattern. If literalBang is false, a leading
+// "!" negates the pattern like in gitignore files.
+func compile(pattern string, literalBang bool) (matcher, error) {
+	if !ValidatePattern(pattern) {
+		return nil, ErrBadPattern
+	}
+	negate := !literalBang && strings.HasPrefix(pattern, "!")
+	if negate {
+		pattern = pattern[1:]
+	}
+	glob := escapeExtglob(pattern)
+	if negate {
+		glob = "!" + glob
+	}
+	globs := []string{glob}
+	if strings.HasSuffix(glob, "/**") && len(glob) > 3 {
+		globs = append(globs, strings.TrimSuffix(glob, "/**"))
+	}
+	var m matcher
+	for _, glob := range globs {
+		p, err := pathspec.NewMinimatchPattern(glob, pathspec.MinimatchOptions{Dot: true})
+		if err != nil {
+			return nil, ErrBadPattern
+		}
+		m = append(m, p)
+	}
+	return m, nil
+}
+
+// escapeExtglob escapes the characters minimatch treats specially, but
+// doublestar does not: a leading "!" and parentheses outside of character
+// classes. Classes negated with "^" are rewritten to use "!".
+func escapeExtglob(pattern string) string {
+	var b strings.Builder
+	if strings.HasPrefix(pattern, "!") {
+		b.WriteByte('\\')
+	}
+	class := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteByte(c)
+			i++
+			c = pattern[i]
+		case class:
+			class = c != ']'
+		case c == '[':
+			class = true
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				b.WriteString("[!")
+				i++
+				continue
+			}
+		case c == '(' || c == ')':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// match reports whether the pattern matches name.
+func (m matcher) match(name string) bool {
+	for _, p := range m {
+		if p.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether name matches the shell pattern. The pattern and the
+// name are separated by slashes. The only possible error is ErrBadPattern.
+func Match(pattern, name string) (bool, error) {
+	m, err := compile(pattern, true)
+	if err != nil {
+		return false, err
+	}
+	return m.match(name), nil
+}
+
+// MatchUnvalidated is like Match, but returns false for malformed patterns.
+func MatchUnvalidated(pattern, name string) bool {
+	matched, _ := Match(pattern, name)
+	return matched
+}
+
+// PathMatch is like Match, but takes the pattern and the name separated by
+// the path separator of the operating system.
+func PathMatch(pattern, name string) (bool, error) {
+	return Match(filepath.ToSlash(pattern), filepath.ToSlash(name))
+}
+
+// MatchList reports whether name is selected by patterns, evaluated like the
+// lines of a gitignore file: the last matching pattern decides, and a leading
+// "!" negates a pattern, so it deselects names matched by earlier patterns.
+// A leading "!" is taken literally if escaped as "\!".
+func MatchList(patterns []string, name string) (bool, error) {
+	var compiled []*pathspec.Pattern
+	for _, pattern := range patterns {
+		m, err := compile(pattern, false)
+		if err != nil {
+			return false, err
+		}
+		compiled = append(compiled, m...)
+	}
+	return pathspec.NewPathSpec(compiled...).Match(name), nil
+}
+
+// ValidatePattern reports whether the slash-separated pattern is well formed:
+// all character classes and braces are closed, and it does not end with an
+// unescaped backslash.
+func ValidatePattern(pattern string) bool {
+	braces := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i++; i == len(pattern) {
+				return false
+			}
+		case '[':
+			end := classEnd(pattern, i)
+			if end < 0 {
+				return false
+			}
+			i = end
+		case '{':
+			braces++
+		case '}':
+			if braces == 0 {
+				return false
+			}
+			braces--
+		}
+	}
+	return braces == 0
+}
+
+// ValidatePathPattern is like ValidatePattern, but takes a pattern separated
+// by the path separator of the operating system.
+func ValidatePathPattern(pattern string) bool {
+	return ValidatePattern(filepath.ToSlash(pattern))
+}
+
+// classEnd returns the index of the bracket closing the character class
+// starting at index open of pattern, or -1.
+func classEnd(pattern string, open int) int {
+	i := open + 1
+	if i < len(pattern) && (pattern[i] == '^' || pattern[i] == '!') {
+		i++
+	}
+	// A leading "]" is part of the class.
+	if i < len(pattern) && pattern[i] == ']' {
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// SplitPattern splits the slash-separated pattern into the directory holding
+// all its matches, which contains no wildcards, and the rest of the pattern.
+// The directory is "." if the first path segment has wildcards.
+func SplitPattern(p string) (base, pattern string) {
+	meta := strings.IndexAny(p, "*?[{\\")
+	if meta < 0 {
+		meta = len(p)
+	}
+	slash := strings.LastIndexByte(p[:meta], '/')
+	switch {
+	case slash < 0:
+		return ".", p
+	case slash == 0:
+		return "/", p[1:]
+	}
+	return p[:slash], p[slash+1:]
+}
+
+// GlobOption configures Glob, GlobWalk and FilepathGlob.
+type GlobOption func(*globOptions)
+
+// globOptions holds the configuration of a glob.
+type globOptions struct {
+	failOnIOErrors        bool
+	failOnPatternNotExist bool
+	filesOnly             bool
+}
+
+// WithFailOnIOErrors makes globbing fail on the first I/O error, instead of
+// skipping the directories which cannot be read.
+func WithFailOnIOErrors() GlobOption {
+	return func(o *globOptions) {
+		o.failOnIOErrors = true
+	}
+}
+
+// WithFailOnPatternNotExist makes globbing return ErrPatternNotExist if the
+// directory returned by SplitPattern does not exist.
+func WithFailOnPatternNotExist() GlobOption {
+	return func(o *globOptions) {
+		o.failOnPatternNotExist = true
+	}
+}
+
+// WithFilesOnly leaves directories out of the results.
+func WithFilesOnly() GlobOption {
+	return func(o *globOptions) {
+		o.filesOnly = true
+	}
+}
+
+// WithNoFollow is accepted for compatibility. Symbolic links are never
+// followed, since fs.WalkDir does not follow them.
+func WithNoFollow() GlobOption {
+	return func(o *globOptions) {}
+}
+
+// GlobWalkFunc is called by GlobWalk for every match. Returning
+// fs.SkipDir for a directory skips its contents, other errors stop the walk.
+type GlobWalkFunc func(path string, d fs.DirEntry) error
+
+// Glob returns the names of all files and directories of fsys matching the
+// slash-separated pattern, in lexical order, or nil if there are none. The
+// only possible pattern error is ErrBadPattern.
+func Glob(fsys fs.FS, pattern string, opts ...GlobOption) ([]string, error) {
+	var matches []string
+	err := GlobWalk(fsys, pattern, func(name string, d fs.DirEntry) error {
+		matches = append(matches, name)
+		return nil
+	}, opts...)
+	return matches, err
+}
+
+// GlobWalk calls fn for every file and directory of fsys matching the
+// slash-separated pattern, in lexical order. Only the directory returned by
+// SplitPattern is walked, and without "**" and braces, only as deep as the
+// pattern reaches.
+func GlobWalk(fsys fs.FS, pattern string, fn GlobWalkFunc, opts ...GlobOption) error {
+	o := &globOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	m, err := compile(pattern, true)
+	if err != nil {
+		return err
+	}
+	base, _ := SplitPattern(pattern)
+	maxDepth := -1
+	if !strings.Contains(pattern, "**") && !strings.Contains(pattern, "{") {
+		maxDepth = strings.Count(pattern, "/")
+	}
+	return fs.WalkDir(fsys, base, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			switch {
+			case name == base && errors.Is(err, fs.ErrNotExist):
+				if o.failOnPatternNotExist {
+					return ErrPatternNotExist
+				}
+				return nil
+			case o.failOnIOErrors:
+				return err
+			}
+			return nil
+		}
+		if name == "." {
+			return nil
+		}
+		if m.match(name) && !(o.filesOnly && d.IsDir()) {
+			if err := fn(name, d); err != nil {
+				return err
+			}
+		}
+		if d.IsDir() && maxDepth >= 0 && strings.Count(name, "/") >= maxDepth {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
+
+// FilepathGlob is like Glob, but takes a pattern separated by the path
+// separator of the operating system, which may be absolute, and matches it
+// against the file system of the operating system. The results are separated
+// by the path separator, too.
+func FilepathGlob(pattern string, opts ...GlobOption) ([]string, error) {
+	pattern = filepath.ToSlash(filepath.Clean(pattern))
+	base, rest := SplitPattern(pattern)
+	if base == "." {
+		rest = pattern
+	}
+	matches, err := Glob(os.DirFS(filepath.FromSlash(base)), rest, opts...)
+	if err != nil || base == "." {
+		return fromSlash(matches), err
+	}
+	for i, match := range matches {
+		matches[i] = filepath.Join(filepath.FromSlash(base), filepath.FromSlash(match))
+	}
+	return matches, nil
+}
+
+// fromSlash converts the slash-separated names to the path separator of the
+// operating system.
+func fromSlash(names []string) []string {
+	for i, name := range names {
+		names[i] = filepath.FromSlash(name)
+	}
+	return names
+}
@@ -0,0 +1,188 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package doublestar
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/pathspec/main.go", true},
+		{"cmd/**", "cmd", true},
+		{"cmd/**", "cmd/pathspec/main.go", true},
+		{"cmd/**", "command", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"*", ".hidden", true},
+		{"?.txt", "a.txt", true},
+		{"[a-c].txt", "b.txt", true},
+		{"[^a-c].txt", "b.txt", false},
+		{"[!a-c].txt", "d.txt", true},
+		{"{a,b}.txt", "b.txt", true},
+		{"{a,b/c}/x", "b/c/x", true},
+		{"!a", "!a", true},
+		{"!a", "b", false},
+		{"(a|b)", "(a|b)", true},
+		{"@(a)", "a", false},
+		{"\\*", "*", true},
+		{"\\*", "a", false},
+		{"a,b/**", "a,b/c", true},
+	}
+	for _, c := range cases {
+		got, err := Match(c.pattern, c.name)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got != c.want {
+			t.Errorf("Match('%s', '%s') returned '%v', want '%v'", c.pattern, c.name, got, c.want)
+		}
+	}
+}
+
+func TestMatchBadPattern(t *testing.T) {
+	for _, pattern := range []string{"[abc", "{a,b", "a}", "a\\"} {
+		if _, err := Match(pattern, "a"); !errors.Is(err, ErrBadPattern) {
+			t.Errorf("Match('%s') returned error '%v', want '%v'", pattern, err, ErrBadPattern)
+		}
+		if ValidatePattern(pattern) {
+			t.Errorf("ValidatePattern('%s') returned 'true', want 'false'", pattern)
+		}
+		if MatchUnvalidated(pattern, "a") {
+			t.Errorf("MatchUnvalidated('%s') returned 'true', want 'false'", pattern)
+		}
+	}
+	for _, pattern := range []string{"[]]", "[\\]]", "{a,{b,c}}", "\\{"} {
+		if !ValidatePattern(pattern) {
+			t.Errorf("ValidatePattern('%s') returned 'false', want 'true'", pattern)
+		}
+	}
+}
+
+func TestMatchList(t *testing.T) {
+	patterns := []string{"**/*.log", "!**/keep.log", "\\!bang"}
+	cases := []struct {
+		name string
+		want bool
+	}{
+		{"debug.log", true},
+		{"logs/keep.log", false},
+		{"main.go", false},
+		{"!bang", true},
+	}
+	for _, c := range cases {
+		got, err := MatchList(patterns, c.name)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got != c.want {
+			t.Errorf("MatchList('%s') returned '%v', want '%v'", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSplitPattern(t *testing.T) {
+	cases := []struct {
+		pattern, base, rest string
+	}{
+		{"*.go", ".", "*.go"},
+		{"cmd/**/*.go", "cmd", "**/*.go"},
+		{"a/b/c", "a/b", "c"},
+		{"a/b\\*/c", "a", "b\\*/c"},
+		{"/etc/*.conf", "/etc", "*.conf"},
+		{"/*", "/", "*"},
+	}
+	for _, c := range cases {
+		base, rest := SplitPattern(c.pattern)
+		if base != c.base || rest != c.rest {
+			t.Errorf("SplitPattern('%s') returned '%s', '%s', want '%s', '%s'", c.pattern, base, rest, c.base, c.rest)
+		}
+	}
+}
+
+func TestGlob(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":              {},
+		"README.md":            {},
+		"cmd/pathspec/main.go": {},
+		"cmd/pathspec/doc.md":  {},
+		"internal/x/y/z.go":    {},
+	}
+	cases := []struct {
+		pattern string
+		opts    []GlobOption
+		want    []string
+	}{
+		{"*.go", nil, []string{"main.go"}},
+		{"**/*.go", nil, []string{"cmd/pathspec/main.go", "internal/x/y/z.go", "main.go"}},
+		{"cmd/*", nil, []string{"cmd/pathspec"}},
+		{"cmd/**", nil, []string{"cmd", "cmd/pathspec", "cmd/pathspec/doc.md", "cmd/pathspec/main.go"}},
+		{"cmd/**", []GlobOption{WithFilesOnly()}, []string{"cmd/pathspec/doc.md", "cmd/pathspec/main.go"}},
+		{"{cmd,internal}/*/*.go", nil, []string{"cmd/pathspec/main.go"}},
+		{"missing/*", nil, nil},
+	}
+	for _, c := range cases {
+		got, err := Glob(fsys, c.pattern, c.opts...)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Glob('%s') returned '%v', want '%v'", c.pattern, got, c.want)
+		}
+	}
+
+	if _, err := Glob(fsys, "missing/*", WithFailOnPatternNotExist()); !errors.Is(err, ErrPatternNotExist) {
+		t.Errorf("Glob('missing/*') returned error '%v', want '%v'", err, ErrPatternNotExist)
+	}
+	if _, err := Glob(fsys, "[a"); !errors.Is(err, ErrBadPattern) {
+		t.Errorf("Glob('[a') returned error '%v', want '%v'", err, ErrBadPattern)
+	}
+}
+
+func TestGlobWalk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/x.go":   {},
+		"a/b/y.go": {},
+		"c/z.go":   {},
+	}
+	var got []string
+	err := GlobWalk(fsys, "**", func(name string, d fs.DirEntry) error {
+		got = append(got, name)
+		if name == "a/b" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := []string{"a", "a/b", "a/x.go", "c", "c/z.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GlobWalk('**') returned '%v', want '%v'", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericRange matches an EditorConfig numeric range like "{1..10}" at the
+// beginning of a glob.
+var numericRange = regexp.MustCompile(`^\{([+-]?\d+)\.\.([+-]?\d+)\}`)
+
+// NewEditorConfigGlob compiles the glob of an EditorConfig section header,
+// like "*.{js,ts}" in "[*.{js,ts}]". Names are slash-separated paths relative
+// to the directory of the .editorconfig file. The EditorConfig glob syntax
+// differs from gitignore:
+//
+// A glob without a slash matches the base name in every directory. Globs
+// containing a slash are anchored at the directory of the .editorconfig
+// file, and a leading slash is ignored.
+//
+// "*" and "?" do not match slashes, "**" matches any string. "[abc]" and
+// "[!abc]" match a character from, or not from, a set; a bracket expression
+// containing a slash is taken literally. "{a,b}" matches either alternative
+// and "{1..10}" any integer between the bounds. Braces without a comma are
+// taken literally. A backslash escapes the next character.
+func NewEditorConfigGlob(glob string) (*Pattern, error) {
+	t := &editorConfigTranslator{}
+	body := t.translate(strings.TrimPrefix(glob, "/"))
+	expr := "^" + body + "$"
+	if !strings.Contains(glob, "/") {
+		expr = "^(?:.*/)?" + body + "$"
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	m := &editorConfigMatcher{regex: regex, ranges: t.ranges}
+	return &Pattern{syntax: "editorconfig", text: glob, matcher: m}, nil
+}
+
+// editorConfigTranslator translates EditorConfig globs into regular
+// expressions. Numeric ranges become capturing groups, whose bounds are
+// collected in ranges in the order of the groups.
+type editorConfigTranslator struct {
+	ranges [][2]int
+}
+
+// translate returns the unanchored regular expression for glob.
+func (t *editorConfigTranslator) translate(glob string) string {
+	var regex bytes.Buffer
+	for i := 0; i < len(glob); i++ {
+		switch char := glob[i]; char {
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				regex.WriteString(".*")
+				i++
+			} else {
+				regex.WriteString("[^/]*")
+			}
+		case '?':
+			regex.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 || strings.Contains(glob[i:i+1+end], "/") {
+				regex.WriteString(`\[`)
+				continue
+			}
+			regex.WriteString(translateBracketExpression(&i, glob))
+		case '{':
+			if m := numericRange.FindStringSubmatch(glob[i:]); m != nil {
+				lo, errLo := strconv.Atoi(m[1])
+				hi, errHi := strconv.Atoi(m[2])
+				if errLo == nil && errHi == nil {
+					t.ranges = append(t.ranges, [2]int{lo, hi})
+					regex.WriteString(`([+-]?\d+)`)
+					i += len(m[0]) - 1
+					continue
+				}
+			}
+			alternatives, end, ok := splitBraces(glob, i)
+			if !ok {
+				regex.WriteString(`\{`)
+				continue
+			}
+			exprs := make([]string, len(alternatives))
+			for k, alternative := range alternatives {
+				exprs[k] = t.translate(alternative)
+			}
+			regex.WriteString("(?:" + strings.Join(exprs, "|") + ")")
+			i = end
+		default:
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return regex.String()
+}
+
+// editorConfigMatcher matches an EditorConfig glob, checking the integers
+// matched by numeric ranges against their bounds.
+type editorConfigMatcher struct {
+	regex  *regexp.Regexp
+	ranges [][2]int
+}
+
+// Match implements Matcher.
+func (m *editorConfigMatcher) Match(name string) bool {
+	if len(m.ranges) == 0 {
+		return m.regex.MatchString(name)
+	}
+	loc := m.regex.FindStringSubmatchIndex(name)
+	if loc == nil {
+		return false
+	}
+	for k, r := range m.ranges {
+		start, end := loc[2*k+2], loc[2*k+3]
+		if start < 0 {
+			// The range is part of an alternative which did not match.
+			continue
+		}
+		n, err := strconv.Atoi(name[start:end])
+		if err != nil || n < r[0] || n > r[1] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestNewEditorConfigGlob(t *testing.T) {
+	tests := []struct {
+		glob string
+		name string
+		want bool
+	}{
+		{"*", "a/b/main.go", true},
+		{"*.go", "a/b/main.go", true},
+		{"*.{js,ts}", "src/app.ts", true},
+		{"*.{js,ts}", "src/app.tsx", false},
+		{"{package.json,.travis.yml}", "package.json", true},
+		{"lib/**.js", "lib/a/b/c.js", true},
+		{"lib/**.js", "src/lib/c.js", false},
+		{"/lib/*.js", "lib/c.js", true},
+		{"lib/*.js", "lib/a/c.js", false},
+		{"Makefile", "sub/Makefile", true},
+		{"file[0-9].txt", "file7.txt", true},
+		{"file[!0-9].txt", "file7.txt", false},
+		{"file[!0-9].txt", "fileA.txt", true},
+		{"a[/]b", "a[/]b", true},
+		{"file{1..10}.txt", "file7.txt", true},
+		{"file{1..10}.txt", "file10.txt", true},
+		{"file{1..10}.txt", "file11.txt", false},
+		{"file{-3..3}.txt", "file-2.txt", true},
+		{"{single}.txt", "{single}.txt", true},
+		{"{a,{b,c}}.txt", "c.txt", true},
+		{"{x{1..2},y}.txt", "y.txt", true},
+		{"{x{1..2},y}.txt", "x3.txt", false},
+		{`\*.txt`, "*.txt", true},
+		{`\*.txt`, "a.txt", false},
+	}
+	for _, test := range tests {
+		p, err := NewEditorConfigGlob(test.glob)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := p.Match(test.name); got != test.want {
+			t.Errorf("NewEditorConfigGlob('%s').Match('%s') returned '%v', want '%v'", test.glob, test.name, got, test.want)
+		}
+	}
+}
+
+func TestEditorConfigSyntax(t *testing.T) {
+	ps, err := FromLinesWithSyntax("editorconfig", "*.{md,txt}", "!README.md")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !ps.Match("docs/a.txt") || ps.Match("README.md") {
+		t.Errorf("FromLinesWithSyntax('editorconfig') does not match like EditorConfig globs")
+	}
+}
@@ -0,0 +1,154 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// encodingVersion is the version of the binary encoding written by Encode.
+// It must be increased whenever the encoding or the translation of patterns
+// into regular expressions changes, so stale caches are rejected.
+const encodingVersion = 3
+
+// encodedSpec is the binary encoding of a PathSpec.
+type encodedSpec struct {
+	Version  int
+	Patterns []encodedPattern
+}
+
+// encodedPattern is the binary encoding of a Pattern.
+type encodedPattern struct {
+	Syntax   string
+	Text     string
+	Regex    string
+	Negate   bool
+	Source   string
+	Line     int
+	Dir      bool
+	Prefix   string
+	Base     string
+	Literal  string
+	Anchored bool
+	Disabled bool
+}
+
+// Encode writes the compiled form of the PathSpec to w, using encoding/gob.
+// Besides the pattern texts it contains the translated regular expressions,
+// so DecodeSpec does not need to parse the patterns again. It is meant for
+// caching large ignore files, keyed on a hash of their content.
+//
+// Disabled patterns stay disabled, but hooks, caches and pattern statistics
+// are not encoded. Encode fails with ErrUnencodable for what DecodeSpec
+// cannot restore: patterns matched by a Matcher instead of a regular
+// expression, unless they have been compiled with NewPatternWithSyntax,
+// converted paths, like with WithWindowsPaths, and a tracked set.
+func (ps *PathSpec) Encode(w io.Writer) error {
+	if err := ps.checkEncodable(); err != nil {
+		return err
+	}
+	enc := encodedSpec{Version: encodingVersion}
+	for _, p := range ps.patterns {
+		enc.Patterns = append(enc.Patterns, encodedPattern{
+			Syntax:   p.syntax,
+			Text:     p.text,
+			Regex:    p.regexString(),
+			Negate:   p.negate,
+			Source:   p.source,
+			Line:     p.line,
+			Dir:      p.dir,
+			Prefix:   p.prefix,
+			Base:     p.base,
+			Literal:  p.literal,
+			Anchored: p.anchored,
+			Disabled: ps.disabled[p],
+		})
+	}
+	return gob.NewEncoder(w).Encode(enc)
+}
+
+// checkEncodable returns an error wrapping ErrUnencodable if DecodeSpec could
+// not restore the PathSpec exactly.
+func (ps *PathSpec) checkEncodable() error {
+	if ps.pathFunc != nil {
+		return fmt.Errorf("paths are converted: %w", ErrUnencodable)
+	}
+	if ps.tracked != nil {
+		return fmt.Errorf("tracked set: %w", ErrUnencodable)
+	}
+	for _, p := range ps.patterns {
+		if p.matcher != nil && !p.factory {
+			return fmt.Errorf("pattern %q of syntax %q has a Matcher of its own: %w", p.text, p.syntax, ErrUnencodable)
+		}
+	}
+	return nil
+}
+
+// DecodeSpec reads a PathSpec written by Encode from r. It fails if the data
+// has been written by an incompatible version of this package. Patterns
+// compiled with NewPatternWithSyntax are compiled again with the
+// PatternFactory registered as their syntax.
+func DecodeSpec(r io.Reader) (*PathSpec, error) {
+	var enc encodedSpec
+	if err := gob.NewDecoder(r).Decode(&enc); err != nil {
+		return nil, err
+	}
+	if enc.Version != encodingVersion {
+		return nil, fmt.Errorf("unsupported encoding version %d, want %d", enc.Version, encodingVersion)
+	}
+	patterns := make([]*Pattern, 0, len(enc.Patterns))
+	for _, e := range enc.Patterns {
+		var p *Pattern
+		if e.Regex == "" {
+			var err error
+			p, err = NewPatternWithSyntax(e.Syntax, e.Text)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			regex, err := regexp.Compile(e.Regex)
+			if err != nil {
+				return nil, err
+			}
+			p = &Pattern{
+				syntax:   e.Syntax,
+				text:     e.Text,
+				negate:   e.Negate,
+				regex:    regex,
+				dir:      e.Dir,
+				prefix:   e.Prefix,
+				base:     e.Base,
+				literal:  e.Literal,
+				anchored: e.Anchored,
+			}
+		}
+		p.source = e.Source
+		p.line = e.Line
+		patterns = append(patterns, p)
+	}
+	ps := NewPathSpec(patterns...)
+	for i, e := range enc.Patterns {
+		if e.Disabled {
+			ps.Disable(i)
+		}
+	}
+	return ps, nil
+}
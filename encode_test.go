@@ -0,0 +1,160 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestPathSpecEncode(t *testing.T) {
+	RegisterPatternFactory("test-suffix-encode", func(line string) (Matcher, error) {
+		return suffixMatcher(line), nil
+	})
+
+	gitignore, err := compileLines(".gitignore", []string{"*.log", "!keep.log", "/vendor/", "node_modules"}, NewPattern)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	custom, err := NewPatternWithSyntax("test-suffix-encode", "~")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps := NewPathSpec(append(gitignore.Patterns(), custom)...)
+
+	var buf bytes.Buffer
+	if err := ps.Encode(&buf); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	decoded, err := DecodeSpec(&buf)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for i, p := range decoded.Patterns() {
+		want := ps.Patterns()[i]
+		if !p.Equal(want) || p.Source() != want.Source() || p.Line() != want.Line() || p.prefix != want.prefix || p.base != want.base {
+			t.Errorf("DecodeSpec()[%d] returned '%s' (%s:%d), want '%s' (%s:%d)", i, p, p.Source(), p.Line(), want, want.Source(), want.Line())
+		}
+	}
+	for _, name := range []string{"a.log", "keep.log", "vendor/", "vendor/x", "a/node_modules/", "a~", "main.go"} {
+		if got, want := decoded.MatchState(name), ps.MatchState(name); got != want {
+			t.Errorf("DecodeSpec().MatchState(%s) returned '%v', want '%v'", name, got, want)
+		}
+	}
+
+	buf.Reset()
+	if err := gob.NewEncoder(&buf).Encode(encodedSpec{Version: encodingVersion + 1}); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if _, err := DecodeSpec(&buf); err == nil {
+		t.Errorf("DecodeSpec() of an incompatible version returned no error, want one")
+	}
+}
+
+func TestPathSpecEncodeRoundTrip(t *testing.T) {
+	must := func(ps *PathSpec, err error) *PathSpec {
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		return ps
+	}
+	disabled := must(FromLines("*.log", "*.md"))
+	disabled.Disable(1)
+	tests := []struct {
+		name string
+		ps   *PathSpec
+	}{
+		{"FromLines", must(FromLines("*.log", "!keep.log", "/build/", "docs/**/*.md"))},
+		{"WithBraceExpansion", must(FromLinesWithOptions([]string{"*.{log,md}", "!keep.{log,md}"}, WithBraceExpansion()))},
+		{"WithFnmatch", must(FromLinesWithOptions([]string{"*.log", "docs/*"}, WithFnmatch()))},
+		{"WithCaseSensitive", must(FromLinesWithOptions([]string{"*.LOG", "/BUILD/"}, WithCaseSensitive(false)))},
+		{"WithLazyCompile", must(FromLinesWithOptions([]string{"*.log", "!keep.log"}, WithLazyCompile()))},
+		{"WithExtglob", must(FromLinesWithOptions([]string{"*.@(log|md)", "!keep.+(log)"}, WithExtglob()))},
+		{"DockerIgnore", must(DockerIgnore("*.log", "docs/*.md", "!docs/keep.md"))},
+		{"HgIgnore glob", must(HgIgnore("syntax: glob", "*.log", "build"))},
+		{"FromLinesWithSyntax wildmatch", must(FromLinesWithSyntax("wildmatch", "*.log", "!keep.log"))},
+		{"FromLinesWithSyntax minimatch", must(FromLinesWithSyntax("minimatch", "**/*.log", "docs/*.md"))},
+		{"Disable", disabled},
+	}
+	names := []string{"a.log", "A.LOG", "keep.log", "keep.md", "README.md", "build/", "BUILD/x", "docs/a.md", "docs/x/b.md", "docs/keep.md", "main.go"}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.ps.Encode(&buf); err != nil {
+			t.Errorf("%s: Encode() returned an unexpected error: %s", test.name, err)
+			continue
+		}
+		decoded, err := DecodeSpec(&buf)
+		if err != nil {
+			t.Errorf("%s: DecodeSpec() returned an unexpected error: %s", test.name, err)
+			continue
+		}
+		for _, name := range names {
+			if got, want := decoded.MatchState(name), test.ps.MatchState(name); got != want {
+				t.Errorf("%s: DecodeSpec().MatchState(%s) returned '%v', want '%v'", test.name, name, got, want)
+			}
+		}
+		for i := range test.ps.Patterns() {
+			if got, want := decoded.Enabled(i), test.ps.Enabled(i); got != want {
+				t.Errorf("%s: DecodeSpec().Enabled(%d) returned '%v', want '%v'", test.name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestPathSpecEncodeUnencodable(t *testing.T) {
+	must := func(ps *PathSpec, err error) *PathSpec {
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		return ps
+	}
+	pathspec, err := NewPathspecPattern(":(icase)*.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := []struct {
+		name string
+		ps   *PathSpec
+	}{
+		{"HgIgnore regexp", must(HgIgnore(`\.log$`))},
+		{"HelmIgnore", must(HelmIgnore("*.log"))},
+		{"WithExtglob", must(FromLinesWithOptions([]string{"build/*.!(log)"}, WithExtglob()))},
+		{"NewPathspecPattern", NewPathSpec(pathspec)},
+		{"SvnIgnore", SvnIgnore("", "*.log")},
+		{"NewMatcherPattern", NewPathSpec(NewMatcherPattern("suffix", "~", suffixMatcher("~")))},
+		{"WithWildmatch", must(FromLinesWithOptions([]string{"*.LOG"}, WithWildmatch(), WithCaseSensitive(false)))},
+		{"WithMinimatch", must(FromLinesWithOptions([]string{"*.log"}, WithMinimatch(MinimatchOptions{MatchBase: true})))},
+		{"WithWindowsPaths", must(FromLinesWithOptions([]string{"*.log"}, WithWindowsPaths(`C:\src`)))},
+		{"WithUnicodeNormalization", must(FromLinesWithOptions([]string{"*.log"}, WithUnicodeNormalization(norm.NFC)))},
+		{"WithTrackedSet", must(FromLinesWithOptions([]string{"*.log"}, WithTrackedSet(func(string) bool { return false })))},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.ps.Encode(&buf); !errors.Is(err, ErrUnencodable) {
+			t.Errorf("%s: Encode() returned '%v', want ErrUnencodable", test.name, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%s: Encode() wrote %d bytes, want none", test.name, buf.Len())
+		}
+	}
+}
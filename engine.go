@@ -0,0 +1,65 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+)
+
+// PathMatcher matches paths, taking whether a path is a directory as an
+// argument instead of a trailing slash. Pattern, PathSpec, CompiledSpec,
+// GitIgnoreTree, IncludeSpec and SafeSpec implement it, so code accepting a
+// PathMatcher works with any of them, or with an engine of its own.
+type PathMatcher interface {
+	MatchPath(name string, isDir bool) bool
+}
+
+var (
+	_ PathMatcher = (*Pattern)(nil)
+	_ PathMatcher = (*PathSpec)(nil)
+	_ PathMatcher = (*CompiledSpec)(nil)
+	_ PathMatcher = (*GitIgnoreTree)(nil)
+	_ PathMatcher = (*IncludeSpec)(nil)
+	_ PathMatcher = (*SafeSpec)(nil)
+)
+
+// PathMatcherFunc adapts a function to both the Matcher and the PathMatcher
+// interface. It lets matching engines taking whether a path is a directory,
+// like a precompiled automaton or a set of literal paths, be used as the
+// Matcher of a pattern, see NewMatcherPattern.
+type PathMatcherFunc func(name string, isDir bool) bool
+
+// Match calls f with name without a trailing slash, and whether it had one.
+func (f PathMatcherFunc) Match(name string) bool {
+	return f(strings.TrimSuffix(name, "/"), strings.HasSuffix(name, "/"))
+}
+
+// MatchPath calls f(name, isDir).
+func (f PathMatcherFunc) MatchPath(name string, isDir bool) bool {
+	return f(name, isDir)
+}
+
+// NewMatcherPattern returns a pattern which is matched by m instead of a
+// translated regular expression. line is the pattern as written, and a
+// leading "!" negates it, like for every other syntax; syntax names the
+// engine in results and errors. Patterns made with NewMatcherPattern can be
+// mixed with all others in a PathSpec, see NewPathSpec, without registering
+// a syntax with RegisterPatternFactory.
+func NewMatcherPattern(syntax, line string, m Matcher) *Pattern {
+	return &Pattern{syntax: syntax, text: line, negate: strings.HasPrefix(line, "!"), matcher: m}
+}
@@ -0,0 +1,68 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestNewMatcherPattern(t *testing.T) {
+	literals := map[string]bool{"vendor": true, "docs/generated": true}
+	var calls []bool
+	set := PathMatcherFunc(func(name string, isDir bool) bool {
+		calls = append(calls, isDir)
+		return isDir && literals[name]
+	})
+	keep, err := NewPattern("!vendor/keep/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps := NewPathSpec(
+		NewMatcherPattern("literals", "directories", set),
+		keep,
+		NewMatcherPattern("literals", "!docs", PathMatcherFunc(func(name string, isDir bool) bool {
+			return name == "docs/generated"
+		})),
+	)
+
+	var m PathMatcher = ps
+	for _, test := range []struct {
+		name  string
+		isDir bool
+		want  MatchState
+	}{
+		{"vendor", true, StateIgnored},
+		{"vendor", false, StateUnmatched},
+		{"vendor/keep", true, StateIncluded},
+		{"docs/generated", true, StateIncluded},
+		{"src", true, StateUnmatched},
+	} {
+		if got := ps.MatchState(dirName(test.name, test.isDir)); got != test.want {
+			t.Errorf("MatchState('%s', %v) returned '%v', want '%v'", test.name, test.isDir, got, test.want)
+		}
+		if got := m.MatchPath(test.name, test.isDir); got != (test.want == StateIgnored) {
+			t.Errorf("MatchPath('%s', %v) returned '%v'", test.name, test.isDir, got)
+		}
+	}
+	if len(calls) == 0 || !calls[0] {
+		t.Errorf("PathMatcherFunc was called with %v, want a directory first", calls)
+	}
+	if p := ps.Patterns()[2]; !p.Negate() || p.Syntax() != "literals" {
+		t.Errorf("NewMatcherPattern('literals', '!docs') returned syntax '%s', negate %v", p.Syntax(), p.Negate())
+	}
+}
@@ -0,0 +1,130 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyPattern is returned for patterns which are empty after removing
+// the negation and escape prefixes, like "!".
+var ErrEmptyPattern = errors.New("pattern is empty")
+
+// ErrInvalidDoubleAsterisk is returned in strict mode for a "**" which is not
+// a whole path segment, like in "a**b", see WithStrict.
+var ErrInvalidDoubleAsterisk = errors.New(`"**" is not a whole path segment`)
+
+// ErrLimitExceeded is returned for input exceeding the limits set with
+// WithLimits.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ErrSymlinkLoop is passed to the walk function for symbolic links pointing
+// to one of their own ancestor directories, if symbolic links are followed.
+var ErrSymlinkLoop = errors.New("symbolic link loop")
+
+// ErrOutsideRoot is returned by RootedSpec for paths which do not resolve to
+// the root directory or below it.
+var ErrOutsideRoot = errors.New("path is outside of the root")
+
+// ErrConflictingPaths is returned by Infer for an ignored path which cannot
+// be ignored without ignoring a kept path, like a directory holding a kept
+// file.
+var ErrConflictingPaths = errors.New("ignored path conflicts with kept paths")
+
+// ErrUntranslatable is returned by Convert, the exporters like ToTarArgs and
+// Pattern.RegexString for patterns which cannot be expressed in the target
+// dialect.
+var ErrUntranslatable = errors.New("pattern cannot be translated")
+
+// ErrUnencodable is returned by Encode for a PathSpec which DecodeSpec could
+// not restore exactly, like one with patterns matched by a Matcher of their
+// own or converting paths with WithWindowsPaths.
+var ErrUnencodable = errors.New("path spec cannot be encoded")
+
+// ParseError describes a line which could not be compiled.
+type ParseError struct {
+	// Source is the name of the file the line was read from, if known.
+	Source string
+	// Line is the line number, starting at 1.
+	Line int
+	// Pattern is the trimmed line.
+	Pattern string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error formats the error as "<source>:<line>: <err>", omitting an unknown
+// source.
+func (e *ParseError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.Source, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors lists all lines of a gitignore file which could not be
+// compiled. The FromLines, FromReader and FromFile functions return it, so
+// callers see every bad line at once instead of only the first one.
+type ParseErrors []*ParseError
+
+// Error joins the messages of all errors, one per line.
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the individual errors.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Is reports whether any of the errors matches target, so errors.Is works
+// with versions of Go not supporting multiple wrapped errors.
+func (e ParseErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors which matches target, so errors.As works
+// with versions of Go not supporting multiple wrapped errors.
+func (e ParseErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
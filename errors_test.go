@@ -0,0 +1,60 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"regexp/syntax"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	_, err := compileLines(".gitignore", []string{"*.log", "!", "ok", "\\", "[z-a]"}, NewPattern)
+	if err == nil {
+		t.Fatalf("compileLines() returned no error, want one")
+	}
+
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("compileLines() returned '%v', want ParseErrors", err)
+	}
+	wantLines := []int{2, 4, 5}
+	if len(errs) != len(wantLines) {
+		t.Fatalf("compileLines() returned %d errors, want %d: %s", len(errs), len(wantLines), err)
+	}
+	for i, e := range errs {
+		if e.Line != wantLines[i] || e.Source != ".gitignore" {
+			t.Errorf("ParseErrors[%d] is for %s:%d, want .gitignore:%d", i, e.Source, e.Line, wantLines[i])
+		}
+	}
+	if want := ".gitignore:2: invalid pattern \"!\": pattern is empty"; errs[0].Error() != want {
+		t.Errorf("ParseErrors[0].Error() returned %q, want %q", errs[0].Error(), want)
+	}
+
+	if !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("errors.Is(%v, ErrEmptyPattern) returned 'false', want 'true'", err)
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 {
+		t.Errorf("errors.As(%v, *ParseError) did not find the first error", err)
+	}
+	var se *syntax.Error
+	if !errors.As(err, &se) {
+		t.Errorf("errors.As(%v, *syntax.Error) returned 'false', want 'true'", err)
+	}
+}
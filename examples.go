@@ -0,0 +1,146 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"regexp"
+	"strings"
+)
+
+// exampleNames are the names Examples substitutes for wildcards matching
+// whole names, and exampleDirs the directories it substitutes for "**".
+var (
+	exampleNames = []string{"main", "a", "test", "index", "foo", "data", "b", "x"}
+	exampleDirs  = []string{"src", "lib", "a", "docs"}
+)
+
+// exampleChars are the characters Examples picks from to match "?" and
+// bracket expressions.
+const exampleChars = "abcxyz0123456789_-ABCXYZ.+defghijklmnopqrstuvwDEFGHIJKLMNOPQRSTUVW"
+
+// Examples returns up to n distinct sample paths the pattern matches,
+// regardless of negation, synthesized from its glob: wildcards are replaced by
+// sample names, and "**" and the implicit leading "**" of patterns without a
+// slash by no, one or two directories. Directories are denoted by a trailing
+// slash, and a pattern ending with a slash yields both the directory and
+// paths beneath it. Every example is verified to match, so patterns of other
+// syntaxes than gitignore globs may yield fewer examples, or none.
+func (p *Pattern) Examples(n int) []string {
+	segs, _, err := NormalizePattern(p.text)
+	if err != nil || n <= 0 {
+		return nil
+	}
+	var examples []string
+	seen := make(map[string]bool)
+	for k := 0; len(examples) < n && k < 4*n+8; k++ {
+		name := exampleFor(segs, p.dir, k)
+		if name == "" || seen[name] || !p.match(name) {
+			continue
+		}
+		seen[name] = true
+		examples = append(examples, name)
+	}
+	return examples
+}
+
+// exampleFor returns the k-th sample path of the normalized pattern segments
+// segs, or an empty string. dir is true if the pattern ends with a slash, so
+// its last "**" stands for the contents of the directory.
+func exampleFor(segs []string, dir bool, k int) string {
+	var b strings.Builder
+	for i, seg := range segs {
+		last := i == len(segs)-1
+		switch {
+		case seg == "**" && last && dir:
+			// "" for the directory itself, which ends with the
+			// slash written before.
+			b.WriteString([]string{"", "file", "sub/file"}[k%3])
+		case seg == "**" && last:
+			b.WriteString([]string{"file", exampleDirs[k%len(exampleDirs)] + "/file"}[k%2])
+		case seg == "**":
+			for j := 0; j < k%3; j++ {
+				b.WriteString(exampleDirs[(k+j)%len(exampleDirs)])
+				b.WriteByte('/')
+			}
+		default:
+			s, ok := exampleSegment(seg, k+i)
+			if !ok {
+				return ""
+			}
+			b.WriteString(s)
+			if !last {
+				b.WriteByte('/')
+			}
+		}
+	}
+	return b.String()
+}
+
+// exampleSegment returns the k-th sample name the glob segment seg matches,
+// and whether there is one.
+func exampleSegment(seg string, k int) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(seg); i++ {
+		switch c := seg[i]; c {
+		case '\\':
+			if i++; i == len(seg) {
+				return "", false
+			}
+			b.WriteByte(seg[i])
+		case '*':
+			b.WriteString(exampleNames[(k+i)%len(exampleNames)])
+		case '?':
+			b.WriteByte(exampleChars[(k+i)%26])
+		case '[':
+			start := i
+			expr := translateBracketExpression(&i, seg)
+			if i == start {
+				// An unclosed bracket is a literal.
+				b.WriteByte(c)
+				continue
+			}
+			char, ok := exampleChar(expr, k)
+			if !ok {
+				return "", false
+			}
+			b.WriteByte(char)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), b.Len() > 0
+}
+
+// exampleChar returns a character matching the translated bracket expression
+// expr, preferring the k-th one of exampleChars, and whether there is one.
+func exampleChar(expr string, k int) (byte, bool) {
+	regex, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return 0, false
+	}
+	var matches []byte
+	for i := 0; i < len(exampleChars); i++ {
+		if regex.MatchString(exampleChars[i : i+1]) {
+			matches = append(matches, exampleChars[i])
+		}
+	}
+	if len(matches) == 0 {
+		return 0, false
+	}
+	return matches[k%len(matches)], true
+}
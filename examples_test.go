@@ -0,0 +1,75 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatternExamples(t *testing.T) {
+	cases := []struct {
+		pattern string
+		n       int
+		want    []string
+	}{
+		{"/build/", 3, []string{"build/", "build/file", "build/sub/file"}},
+		{"/*.go", 2, []string{"main.go", "a.go"}},
+		{"/[!a-z]?.txt", 2, []string{"00.txt", "11.txt"}},
+		{"/\\#notes", 5, []string{"#notes"}},
+		{"/docs/**/index.md", 2, []string{"docs/index.md", "docs/lib/index.md"}},
+		{"*.log", 0, nil},
+	}
+	for _, c := range cases {
+		p, err := NewPattern(c.pattern)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := p.Examples(c.n); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Examples('%s', %d) returned '%v', want '%v'", c.pattern, c.n, got, c.want)
+		}
+	}
+}
+
+func TestPatternExamplesMatch(t *testing.T) {
+	for _, pattern := range []string{"*.go", "!*.log", "build/", "docs/**/index.md", "[a-c]?.txt", "**", "a/**/b/*.c", "abc["} {
+		p, err := NewPattern(pattern)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		examples := p.Examples(5)
+		if len(examples) < 3 {
+			t.Errorf("Examples('%s') returned '%v', want at least 3 examples", pattern, examples)
+		}
+		seen := make(map[string]bool)
+		for _, example := range examples {
+			if !p.Match(example) || seen[example] {
+				t.Errorf("Examples('%s') returned '%s', which does not match or is repeated", pattern, example)
+			}
+			seen[example] = true
+		}
+	}
+
+	p, err := NewRegexPattern(`^[0-9]+$`)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if examples := p.Examples(3); len(examples) != 0 {
+		t.Errorf("Examples('%s') returned '%v', want none", p, examples)
+	}
+}
@@ -0,0 +1,192 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Outcome describes what evaluating a single pattern did to the decision
+// about a path.
+type Outcome int
+
+const (
+	// OutcomeSkipped means the pattern cannot match the path, because its
+	// literal first or last path segment differs, so it was not evaluated.
+	OutcomeSkipped Outcome = iota
+	// OutcomeNoMatch means the pattern was evaluated, but did not match.
+	OutcomeNoMatch
+	// OutcomeConfirmed means the pattern matched, but the decision stayed
+	// the same, like a second pattern ignoring an ignored path.
+	OutcomeConfirmed
+	// OutcomeDecided means the pattern matched a path no earlier pattern
+	// had matched.
+	OutcomeDecided
+	// OutcomeOverrode means the pattern matched and reversed the decision
+	// of an earlier pattern, like a negation re-including an ignored path.
+	OutcomeOverrode
+	// OutcomeDisabled means the pattern was not evaluated, because it is
+	// disabled, see PathSpec.Disable.
+	OutcomeDisabled
+)
+
+// String returns a human readable name of the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeNoMatch:
+		return "no match"
+	case OutcomeConfirmed:
+		return "confirmed"
+	case OutcomeDecided:
+		return "decided"
+	case OutcomeOverrode:
+		return "overrode"
+	case OutcomeDisabled:
+		return "disabled"
+	default:
+		return "skipped"
+	}
+}
+
+// ExplainStep describes the evaluation of a single pattern by Explain.
+type ExplainStep struct {
+	// Pattern is the evaluated pattern.
+	Pattern *Pattern
+	// Outcome describes the effect of the pattern.
+	Outcome Outcome
+	// State is the decision about the path after the pattern.
+	State MatchState
+}
+
+// Explanation describes how a PathSpec decided about a path.
+type Explanation struct {
+	// Name is the slash-separated path.
+	Name string
+	// Steps lists the evaluation of every pattern, in order.
+	Steps []ExplainStep
+	// State is the final decision, as returned by MatchState.
+	State MatchState
+	// Decider is the last matching pattern, which decided about the path,
+	// or nil if no pattern matched or the path is tracked.
+	Decider *Pattern
+	// Tracked is true if the path is tracked, see SetTracked, so State is
+	// StateIncluded regardless of the patterns.
+	Tracked bool
+}
+
+// Explain answers why name is ignored or not: it lists for every pattern
+// whether it was skipped, did not match, or changed the decision, together
+// with the final decision. Explain is built on Trace, so the two always
+// agree; like Trace, it is meant for debugging.
+func (ps *PathSpec) Explain(name string) *Explanation {
+	name = ps.slashPath(name)
+	trace := ps.trace(name)
+	e := &Explanation{Name: name, Steps: make([]ExplainStep, len(ps.patterns))}
+	var candidates []int
+	if ps.index != nil {
+		candidates = ps.index.candidates(name, nil)
+	}
+	for i, p := range ps.patterns {
+		candidate := len(candidates) > 0 && candidates[0] == i
+		if candidate {
+			candidates = candidates[1:]
+		}
+		if ps.disabled[p] {
+			e.Steps[i] = ExplainStep{Pattern: p, Outcome: OutcomeDisabled, State: e.State}
+			continue
+		}
+		entry := trace[0]
+		trace = trace[1:]
+		step := ExplainStep{Pattern: p, Outcome: OutcomeNoMatch, State: entry.State}
+		switch {
+		case entry.Matched && e.State == StateUnmatched:
+			step.Outcome = OutcomeDecided
+		case entry.Matched && e.State == entry.State:
+			step.Outcome = OutcomeConfirmed
+		case entry.Matched:
+			step.Outcome = OutcomeOverrode
+		case ps.index != nil && !candidate:
+			step.Outcome = OutcomeSkipped
+		}
+		if entry.Winner {
+			e.Decider = p
+		}
+		e.State = entry.State
+		e.Steps[i] = step
+	}
+	if isTracked(ps.tracked, name) {
+		e.Tracked = true
+		e.State = StateIncluded
+	}
+	return e
+}
+
+// MatchingPatterns returns every enabled pattern matching name, in order,
+// including the patterns overridden by later ones. The last pattern decides
+// about name, as returned by MatchP. Directories are denoted by a trailing
+// slash, e.g. "build/".
+func (ps *PathSpec) MatchingPatterns(name string) []*Pattern {
+	name = ps.slashPath(name)
+	var matching []*Pattern
+	if ps.index != nil {
+		var buf [64]int
+		for _, i := range ps.index.candidates(name, buf[:0]) {
+			if p := ps.patterns[i]; p.match(name) {
+				matching = append(matching, p)
+			}
+		}
+		return matching
+	}
+	for _, p := range ps.patterns {
+		if !ps.disabled[p] && p.match(name) {
+			matching = append(matching, p)
+		}
+	}
+	return matching
+}
+
+// String formats the explanation with one line per evaluated pattern,
+// followed by the final decision.
+func (e *Explanation) String() string {
+	var b strings.Builder
+	for _, step := range e.Steps {
+		if step.Outcome == OutcomeSkipped {
+			continue
+		}
+		fmt.Fprintf(&b, "%s\t%s\t%s\n", patternLocation(step.Pattern), step.Pattern, step.Outcome)
+	}
+	switch {
+	case e.Tracked:
+		fmt.Fprintf(&b, "%s: %s because it is tracked\n", e.Name, e.State)
+	case e.Decider == nil:
+		fmt.Fprintf(&b, "%s: %s\n", e.Name, e.State)
+	default:
+		fmt.Fprintf(&b, "%s: %s by %s (%s)\n", e.Name, e.State, e.Decider, patternLocation(e.Decider))
+	}
+	return b.String()
+}
+
+// patternLocation formats the source and line of p like "file:line", or
+// "line N" if the source is unknown.
+func patternLocation(p *Pattern) string {
+	if p.source == "" {
+		return fmt.Sprintf("line %d", p.line)
+	}
+	return fmt.Sprintf("%s:%d", p.source, p.line)
+}
@@ -0,0 +1,116 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestPathSpecExplain(t *testing.T) {
+	lines := []string{"*.txt", "/vendor/", "!keep.txt", "docs/", "docs/*.txt", "*.txt"}
+	want := []Outcome{OutcomeDecided, OutcomeSkipped, OutcomeOverrode, OutcomeOverrode, OutcomeNoMatch, OutcomeConfirmed}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	e := ps.Explain("docs/sub/keep.txt")
+	if len(e.Steps) != len(want) {
+		t.Fatalf("Explain('%s', docs/sub/keep.txt) returned %d steps, want %d", lines, len(e.Steps), len(want))
+	}
+	for i, step := range e.Steps {
+		if step.Outcome != want[i] {
+			t.Errorf("Explain('%s', docs/sub/keep.txt).Steps[%d] has outcome '%v', want '%v'", lines, i, step.Outcome, want[i])
+		}
+	}
+	if e.State != StateIgnored || e.Decider != ps.Patterns()[5] {
+		t.Errorf("Explain('%s', docs/sub/keep.txt) decided '%v' by '%v', want 'ignored' by '*.txt'", lines, e.State, e.Decider)
+	}
+
+	wantString := "line 1\t*.txt\tdecided\n" +
+		"line 3\t!keep.txt\toverrode\n" +
+		"line 4\tdocs/\toverrode\n" +
+		"line 5\tdocs/*.txt\tno match\n" +
+		"line 6\t*.txt\tconfirmed\n" +
+		"docs/sub/keep.txt: ignored by *.txt (line 6)\n"
+	if got := e.String(); got != wantString {
+		t.Errorf("Explanation.String() returned %q, want %q", got, wantString)
+	}
+
+	if e := ps.Explain("main.go"); e.Decider != nil || e.State != StateUnmatched {
+		t.Errorf("Explain('%s', main.go) returned '%v', want 'unmatched'", lines, e.State)
+	}
+}
+
+func TestPathSpecMatchingPatterns(t *testing.T) {
+	lines := []string{"*.txt", "/vendor/", "!keep.txt", "docs/", "docs/*.txt", "*.txt"}
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	patterns := ps.Patterns()
+
+	tests := []struct {
+		name string
+		want []*Pattern
+	}{
+		{"docs/sub/keep.txt", []*Pattern{patterns[0], patterns[2], patterns[3], patterns[5]}},
+		{"vendor/a.txt", []*Pattern{patterns[0], patterns[1], patterns[5]}},
+		{"main.go", nil},
+	}
+	for _, test := range tests {
+		got := ps.MatchingPatterns(test.name)
+		if len(got) != len(test.want) {
+			t.Errorf("MatchingPatterns('%s') returned '%v', want '%v'", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("MatchingPatterns('%s') returned '%v', want '%v'", test.name, got, test.want)
+				break
+			}
+		}
+	}
+
+	ps.Disable(5)
+	if got := ps.MatchingPatterns("a.txt"); len(got) != 1 || got[0] != patterns[0] {
+		t.Errorf("MatchingPatterns('a.txt') returned '%v', want '[*.txt]'", got)
+	}
+}
+
+func TestPathSpecExplainAgreesWithTrace(t *testing.T) {
+	ps, err := FromLines("*.txt", "build/", "!keep.txt", "docs/", "docs/*.txt")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps.Disable(3)
+	for _, name := range []string{"docs/sub/keep.txt", "a.txt", "build/", "main.go"} {
+		e := ps.Explain(name)
+		trace := ps.Trace(name)
+		var decider *Pattern
+		for _, entry := range trace {
+			if entry.Winner {
+				decider = entry.Pattern
+			}
+		}
+		if e.Decider != decider || e.State != trace[len(trace)-1].State {
+			t.Errorf("Explain('%s') decided '%v' by '%v', Trace decided '%v' by '%v'", name, e.State, e.Decider, trace[len(trace)-1].State, decider)
+		}
+	}
+}
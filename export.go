@@ -0,0 +1,248 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// exportGlob is a shell glob a gitignore pattern is exported as.
+type exportGlob struct {
+	glob string
+	// crossing is true if the wildcards of glob must match slashes, to
+	// stand in for an inner "**".
+	crossing bool
+}
+
+// exportGlobs translates the gitignore pattern p into shell globs matching
+// the same paths, and reports whether they are anchored at the root and
+// whether they only match directories. Trailing "**" segments become "*",
+// which matches the contents of a directory, provided the caller does not
+// descend into matched directories. An inner "**" becomes two globs: one
+// leaving it out, and one replacing it by a "*" matching slashes. ok is false
+// for patterns of other syntaxes.
+func exportGlobs(p *Pattern) (globs []exportGlob, anchored, dir, ok bool) {
+	if p.syntax != "gitwildmatch" || p.matcher != nil {
+		return nil, false, false, false
+	}
+	text := p.text
+	if p.negate {
+		text = text[1:]
+	}
+	segs, _, err := NormalizePattern(text)
+	if err != nil {
+		return nil, false, false, false
+	}
+	anchored = segs[0] != "**"
+	if !anchored && len(segs) > 1 {
+		segs = segs[1:]
+	}
+	if p.dir {
+		segs = segs[:len(segs)-1]
+		dir = true
+	} else if last := len(segs) - 1; segs[last] == "**" {
+		segs = append(segs[:last:last], "*")
+	}
+	if len(segs) == 0 {
+		// "/" itself matches everything beneath the root.
+		return []exportGlob{{glob: "*"}}, true, false, true
+	}
+	globs = []exportGlob{{}}
+	for i, seg := range segs {
+		var next []exportGlob
+		for _, g := range globs {
+			s := seg
+			if s == "**" {
+				next = append(next, g)
+				s, g.crossing = "*", true
+			}
+			if i > 0 && g.glob != "" {
+				g.glob += "/"
+			}
+			g.glob += s
+			next = append(next, g)
+		}
+		globs = next
+	}
+	return globs, anchored, dir, true
+}
+
+// ToTarArgs translates the gitignore patterns of the PathSpec into GNU tar
+// arguments excluding the paths MatchGit ignores, like
+// "--exclude=*.log". root is the prefix of the member names in the archive,
+// the way the files were named on the command line, like "." for
+// "tar -cf out.tar ." or "project" for "tar -cf out.tar project". Anchored
+// patterns are prefixed with it; it may be empty if the members are named
+// relative to the root already.
+//
+// The arguments start with "--no-wildcards-match-slash", since "*" matches
+// slashes in tar by default, and switch "--anchored" and
+// "--wildcards-match-slash" on and off as needed, which affect the
+// --exclude arguments following them. Tar cannot exclude directories only,
+// so "build/" also excludes a regular file called build, and wildcards in a
+// pattern with an inner "**" may match across directories.
+//
+// Tar cannot re-include excluded paths. Negated patterns, patterns of other
+// syntaxes and disabled patterns are left out; for the first two, ToTarArgs
+// returns the arguments together with a ParseErrors listing them, each
+// wrapping ErrUntranslatable. The arguments are not quoted for a shell.
+func ToTarArgs(ps *PathSpec, root string) ([]string, error) {
+	prefix := ""
+	if root != "" {
+		prefix = strings.TrimSuffix(root, "/") + "/"
+	}
+	args := []string{"--no-wildcards-match-slash"}
+	anchoredOn, crossingOn := false, false
+	var errs ParseErrors
+	for _, p := range ps.active() {
+		globs, anchored, _, ok := exportGlobs(p)
+		if !ok || p.negate {
+			reason := "tar cannot re-include excluded paths"
+			if !ok {
+				reason = p.syntax + " pattern"
+			}
+			errs = append(errs, &ParseError{Source: p.source, Line: p.line, Pattern: p.text, Err: fmt.Errorf("%s: %w", reason, ErrUntranslatable)})
+			continue
+		}
+		for _, g := range globs {
+			if anchored != anchoredOn {
+				args = append(args, map[bool]string{true: "--anchored", false: "--no-anchored"}[anchored])
+				anchoredOn = anchored
+			}
+			if g.crossing != crossingOn {
+				args = append(args, map[bool]string{true: "--wildcards-match-slash", false: "--no-wildcards-match-slash"}[g.crossing])
+				crossingOn = g.crossing
+			}
+			glob := g.glob
+			if anchored {
+				glob = prefix + glob
+			}
+			args = append(args, "--exclude="+glob)
+		}
+	}
+	if len(errs) > 0 {
+		return args, errs
+	}
+	return args, nil
+}
+
+// ToFindArgs translates the gitignore patterns of the PathSpec into a find
+// expression pruning the paths MatchGit ignores, to be passed after the
+// starting point root, like ". -mindepth 1 ( -name *.log -o ... ) -prune".
+// Append "-o -print", or another action, to act on the remaining paths:
+//
+//	args, err := pathspec.ToFindArgs(ps, ".")
+//	cmd := exec.Command("find", append(append([]string{"."}, args...), "-o", "-print")...)
+//
+// The expression starts with "-mindepth 1", which keeps the starting point
+// itself from being pruned, and does not print it. Negated patterns are
+// translated as well: a path is pruned if a pattern matches it and no later
+// negated pattern does. Directory patterns like "build/" only match
+// directories, using "-type d". Since the wildcards of -path match slashes,
+// wildcards in a pattern with a slash may match across directories, unlike
+// in gitignore.
+//
+// Patterns of other syntaxes and disabled patterns are left out; for the
+// first, ToFindArgs returns the expression together with a ParseErrors
+// listing them, each wrapping ErrUntranslatable. The arguments are not quoted
+// for a shell.
+func ToFindArgs(ps *PathSpec, root string) ([]string, error) {
+	root = strings.TrimSuffix(root, "/")
+	if root == "" {
+		root = "."
+	}
+	var conds [][]string
+	var negated []bool
+	var errs ParseErrors
+	for _, p := range ps.active() {
+		globs, anchored, dir, ok := exportGlobs(p)
+		if !ok {
+			errs = append(errs, &ParseError{Source: p.source, Line: p.line, Pattern: p.text, Err: fmt.Errorf("%s pattern: %w", p.syntax, ErrUntranslatable)})
+			continue
+		}
+		var alts [][]string
+		for _, g := range globs {
+			var test []string
+			switch {
+			case anchored:
+				test = []string{"-path", root + "/" + g.glob}
+			case strings.Contains(g.glob, "/"):
+				test = []string{"-path", "*/" + g.glob}
+			default:
+				test = []string{"-name", g.glob}
+			}
+			alts = append(alts, test)
+		}
+		cond := findAny(alts)
+		if dir {
+			cond = append([]string{"-type", "d"}, cond...)
+		}
+		conds = append(conds, cond)
+		negated = append(negated, p.negate)
+	}
+
+	var pruned [][]string
+	for i, cond := range conds {
+		if negated[i] {
+			continue
+		}
+		var later [][]string
+		for j := i + 1; j < len(conds); j++ {
+			if negated[j] {
+				later = append(later, conds[j])
+			}
+		}
+		if len(later) > 0 {
+			cond = append(append(findGroup(cond), "!"), findGroup(findAny(later))...)
+		}
+		pruned = append(pruned, cond)
+	}
+
+	args := []string{"-mindepth", "1"}
+	if len(pruned) == 0 {
+		args = append(args, "-false")
+	} else {
+		args = append(append(args, findGroup(findAny(pruned))...), "-prune")
+	}
+	if len(errs) > 0 {
+		return args, errs
+	}
+	return args, nil
+}
+
+// findAny joins find expressions with "-o".
+func findAny(exprs [][]string) []string {
+	var args []string
+	for i, expr := range exprs {
+		if i > 0 {
+			args = append(args, "-o")
+		}
+		args = append(args, findGroup(expr)...)
+	}
+	return args
+}
+
+// findGroup puts a find expression in parentheses, unless it is a single
+// test, like "-name *.log".
+func findGroup(expr []string) []string {
+	if len(expr) == 2 && strings.HasPrefix(expr[0], "-") {
+		return expr
+	}
+	return append(append([]string{"("}, expr...), ")")
+}
@@ -0,0 +1,82 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestToTarArgs(t *testing.T) {
+	ps, err := FromLines("*.log", "/build/", "docs/**/*.tmp", "cache/**", "!keep.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	args, err := ToTarArgs(ps, ".")
+	want := []string{
+		"--no-wildcards-match-slash",
+		"--exclude=*.log",
+		"--anchored",
+		"--exclude=./build",
+		"--no-anchored",
+		"--exclude=docs/*.tmp",
+		"--wildcards-match-slash",
+		"--exclude=docs/*/*.tmp",
+		"--no-wildcards-match-slash",
+		"--exclude=cache/*",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("ToTarArgs() returned '%v', want '%v'", args, want)
+	}
+	var errs ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Pattern != "!keep.log" || !errors.Is(err, ErrUntranslatable) {
+		t.Errorf("ToTarArgs() returned error '%v', want one untranslatable pattern", err)
+	}
+}
+
+func TestToFindArgs(t *testing.T) {
+	ps, err := FromLines("*.log", "/build/", "docs/**/*.tmp", "!keep.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	args, err := ToFindArgs(ps, ".")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := []string{
+		"-mindepth", "1",
+		"(",
+		"(", "-name", "*.log", "!", "-name", "keep.log", ")",
+		"-o", "(", "(", "-type", "d", "-path", "./build", ")", "!", "-name", "keep.log", ")",
+		"-o", "(", "(", "-path", "*/docs/*.tmp", "-o", "-path", "*/docs/*/*.tmp", ")", "!", "-name", "keep.log", ")",
+		")",
+		"-prune",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("ToFindArgs() returned '%v', want '%v'", args, want)
+	}
+
+	args, err = ToFindArgs(&PathSpec{}, "")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if want := []string{"-mindepth", "1", "-false"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("ToFindArgs() returned '%v', want '%v'", args, want)
+	}
+}
@@ -0,0 +1,342 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// errNestedExtglobNegation is returned for "!(...)" groups inside other
+// extglob groups, which cannot be translated.
+var errNestedExtglobNegation = errors.New("nested !(...) groups are not supported")
+
+// WithExtglob enables the extended globs of bash's extglob option within
+// path segments. "@(a|b)" matches one of the alternatives, "?(a|b)" zero or
+// one, "*(a|b)" zero or more and "+(a|b)" one or more occurrences, and
+// "!(a|b)" anything except the alternatives. The alternatives are globs
+// themselves and must not contain slashes.
+//
+// A pattern starting with "!" is a negated gitignore pattern, so a pattern
+// starting with an "!(...)" group must be escaped as "\!(...)".
+func WithExtglob() Option {
+	return func(o *options) {
+		o.extglob = true
+	}
+}
+
+// newExtglobPattern compiles a gitignore pattern with extended globs.
+// Patterns without "!(...)" groups are translated into a single regular
+// expression, others are matched segment by segment.
+func newExtglobPattern(line string) (*Pattern, error) {
+	segs, negate, err := NormalizePattern(line)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pattern{syntax: "extglob", text: line, negate: negate, dir: strings.HasSuffix(line, "/")}
+	hasNegation := false
+	for _, seg := range segs {
+		groups, err := extglobNegations(seg)
+		if err != nil {
+			return nil, err
+		}
+		hasNegation = hasNegation || len(groups) > 0
+	}
+
+	if !hasNegation {
+		var expr string
+		if p.dir && len(segs) > 1 {
+			expr = "^" + translateSegmentsWith(segs[:len(segs)-1], translateExtglob) + "/.*$"
+		} else {
+			expr = "^" + translateSegmentsWith(segs, translateExtglob) + "/?$"
+		}
+		if p.regex, err = regexp.Compile(expr); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+
+	m := &extglobMatcher{dir: p.dir && len(segs) > 1}
+	if m.dir {
+		segs = segs[:len(segs)-1]
+	}
+	for _, seg := range segs {
+		if seg == "**" {
+			m.segs = append(m.segs, nil)
+			continue
+		}
+		sm, err := newExtglobSegment(seg)
+		if err != nil {
+			return nil, err
+		}
+		m.segs = append(m.segs, sm)
+	}
+	p.matcher = m
+	return p, nil
+}
+
+// translateExtglob translates a glob with extended globs, but without
+// "!(...)" groups, into a regular expression.
+func translateExtglob(glob string) string {
+	var regex bytes.Buffer
+	for i := 0; i < len(glob); i++ {
+		char := glob[i]
+		if strings.IndexByte("@?+*", char) >= 0 && i+1 < len(glob) && glob[i+1] == '(' {
+			if end := extglobGroupEnd(glob, i+1); end > 0 {
+				alternatives := splitExtglobGroup(glob[i+2 : end])
+				for k, alternative := range alternatives {
+					alternatives[k] = translateExtglob(alternative)
+				}
+				regex.WriteString("(?:" + strings.Join(alternatives, "|") + ")")
+				if char != '@' {
+					regex.WriteByte(char)
+				}
+				i = end
+				continue
+			}
+		}
+		switch char {
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case '*':
+			regex.WriteString("[^/]*")
+		case '?':
+			regex.WriteString("[^/]")
+		case '[':
+			regex.WriteString(translateBracketExpression(&i, glob))
+		default:
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return regex.String()
+}
+
+// extglobGroupEnd returns the index of the parenthesis closing the one at
+// index open, or -1. Escaped characters and bracket expressions are skipped.
+func extglobGroupEnd(glob string, open int) int {
+	depth := 0
+	for i := open; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			i++
+		case '[':
+			translateBracketExpression(&i, glob)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitExtglobGroup splits the contents of an extglob group at its top-level
+// "|" separators.
+func splitExtglobGroup(group string) []string {
+	var alternatives []string
+	depth, start := 0, 0
+	for i := 0; i < len(group); i++ {
+		switch group[i] {
+		case '\\':
+			i++
+		case '[':
+			translateBracketExpression(&i, group)
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				alternatives = append(alternatives, group[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(alternatives, group[start:])
+}
+
+// extglobNegations returns the start and end indices of the top-level
+// "!(...)" groups of the segment seg. It fails for "!(...)" groups nested in
+// other groups.
+func extglobNegations(seg string) ([][2]int, error) {
+	var groups [][2]int
+	for i := 0; i < len(seg); i++ {
+		switch seg[i] {
+		case '\\':
+			i++
+		case '[':
+			translateBracketExpression(&i, seg)
+		case '@', '?', '+', '*', '!':
+			if i+1 >= len(seg) || seg[i+1] != '(' {
+				continue
+			}
+			end := extglobGroupEnd(seg, i+1)
+			if end < 0 {
+				continue
+			}
+			if inner, err := extglobNegations(seg[i+2 : end]); err != nil || len(inner) > 0 {
+				return nil, errNestedExtglobNegation
+			}
+			if seg[i] == '!' {
+				groups = append(groups, [2]int{i, end})
+			}
+			i = end
+		}
+	}
+	return groups, nil
+}
+
+// extglobSegment matches a single path segment containing "!(...)" groups.
+// The segment is split into globs and negations, alternately, and every way
+// to split a name among them is tried.
+type extglobSegment struct {
+	globs     []*regexp.Regexp
+	negations []*regexp.Regexp
+}
+
+// newExtglobSegment compiles the path segment seg.
+func newExtglobSegment(seg string) (*extglobSegment, error) {
+	groups, err := extglobNegations(seg)
+	if err != nil {
+		return nil, err
+	}
+	s := &extglobSegment{}
+	start := 0
+	for _, g := range groups {
+		glob, err := regexp.Compile("^" + translateExtglob(seg[start:g[0]]) + "$")
+		if err != nil {
+			return nil, err
+		}
+		alternatives := splitExtglobGroup(seg[g[0]+2 : g[1]])
+		for k, alternative := range alternatives {
+			alternatives[k] = translateExtglob(alternative)
+		}
+		negation, err := regexp.Compile("^(?:" + strings.Join(alternatives, "|") + ")$")
+		if err != nil {
+			return nil, err
+		}
+		s.globs = append(s.globs, glob)
+		s.negations = append(s.negations, negation)
+		start = g[1] + 1
+	}
+	glob, err := regexp.Compile("^" + translateExtglob(seg[start:]) + "$")
+	if err != nil {
+		return nil, err
+	}
+	s.globs = append(s.globs, glob)
+	return s, nil
+}
+
+// match reports whether the segment matches name, which contains no slash.
+func (s *extglobSegment) match(name string) bool {
+	failed := make(map[[2]int]bool)
+	var matchFrom func(k, pos int) bool
+	matchFrom = func(k, pos int) bool {
+		key := [2]int{k, pos}
+		if failed[key] {
+			return false
+		}
+		// Part k is a glob if it is even, a negation otherwise.
+		last := k == 2*len(s.negations)
+		for end := pos; end <= len(name); end++ {
+			if last && end < len(name) {
+				continue
+			}
+			part := name[pos:end]
+			var ok bool
+			if k%2 == 0 {
+				ok = s.globs[k/2].MatchString(part)
+			} else {
+				ok = !s.negations[k/2].MatchString(part)
+			}
+			if ok && (last || matchFrom(k+1, end)) {
+				return true
+			}
+		}
+		failed[key] = true
+		return false
+	}
+	return matchFrom(0, 0)
+}
+
+// extglobMatcher matches a pattern with "!(...)" groups segment by segment.
+// A nil segment is a "**" segment.
+type extglobMatcher struct {
+	segs []*extglobSegment
+	dir  bool
+}
+
+// Match implements Matcher with the semantics of the regular expressions
+// built by parsePattern.
+func (m *extglobMatcher) Match(name string) bool {
+	isDir := strings.HasSuffix(name, "/")
+	parts := strings.Split(strings.TrimSuffix(name, "/"), "/")
+	if !m.dir {
+		return m.matchParts(parts)
+	}
+	// Directory patterns match the directory and everything beneath it.
+	for k := 1; k <= len(parts); k++ {
+		if (k < len(parts) || isDir) && m.matchParts(parts[:k]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchParts reports whether the segments match all path segments parts.
+func (m *extglobMatcher) matchParts(parts []string) bool {
+	failed := make(map[[2]int]bool)
+	var matchFrom func(i, j int) bool
+	matchFrom = func(i, j int) bool {
+		if i == len(m.segs) {
+			return j == len(parts)
+		}
+		key := [2]int{i, j}
+		if failed[key] {
+			return false
+		}
+		if seg := m.segs[i]; seg != nil {
+			if j < len(parts) && seg.match(parts[j]) && matchFrom(i+1, j+1) {
+				return true
+			}
+		} else {
+			// A trailing "**" matches at least one segment, others
+			// match zero or more.
+			min := 0
+			if i > 0 && i == len(m.segs)-1 {
+				min = 1
+			}
+			for k := j + min; k <= len(parts); k++ {
+				if matchFrom(i+1, k) {
+					return true
+				}
+			}
+		}
+		failed[key] = true
+		return false
+	}
+	return matchFrom(0, 0)
+}
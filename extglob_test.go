@@ -0,0 +1,92 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithExtglob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.@(jpg|png)", "img/a.png", true},
+		{"*.@(jpg|png)", "img/a.gif", false},
+		{"file?(.bak)", "file", true},
+		{"file?(.bak)", "file.bak", true},
+		{"file?(.bak)", "file.bak.bak", false},
+		{"log+([0-9]).txt", "log123.txt", true},
+		{"log+([0-9]).txt", "log.txt", false},
+		{"a*(b|c)d", "abcbd", true},
+		{"a*(b|c)d", "ad", true},
+		{"/src/@(lib|cmd)/", "src/lib/x.go", true},
+		{"/src/@(lib|cmd)/", "src/pkg/x.go", false},
+		{"@(a|b?(c))", "bc", true},
+		{"x.+(@(a|b))", "x.abba", true},
+		{`\!(*.go)`, "main.go", false},
+		{`\!(*.go)`, "README.md", true},
+		{`\!(*.go)`, "cmd/main.go", false},
+		{"/src/!(vendor)/*.go", "src/app/main.go", true},
+		{"/src/!(vendor)/*.go", "src/vendor/main.go", false},
+		{"*.!(jpg|png)", "a.gif", true},
+		{"*.!(jpg|png)", "a.png", false},
+		{`\!(ab)*`, "ab", true},
+		{"/build/**/!(keep)/", "build/a/tmp/x", true},
+		{"/build/**/!(keep)/", "build/keep/", false},
+		{"/build/**/!(keep)/", "build/keep", false},
+		{"a[!(]b", "axb", true},
+		{"a?(b", "axb", false},
+		{"a?(b", "ax(b", true},
+	}
+	for _, test := range tests {
+		ps, err := FromLinesWithOptions([]string{test.pattern}, WithExtglob())
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := ps.Match(test.name); got != test.want {
+			t.Errorf("Match('%s') with pattern '%s' returned '%v', want '%v'", test.name, test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestWithExtglobErrors(t *testing.T) {
+	_, err := FromLinesWithOptions([]string{"@(a|!(b))"}, WithExtglob())
+	if !errors.Is(err, errNestedExtglobNegation) {
+		t.Errorf("FromLinesWithOptions('@(a|!(b))') returned '%v', want '%v'", err, errNestedExtglobNegation)
+	}
+}
+
+func TestUnclosedBracket(t *testing.T) {
+	ps, err := FromLines("a[b", "c[")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := map[string]bool{
+		"a[b": true,
+		"ab":  false,
+		"c[":  true,
+	}
+	for name, want := range tests {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
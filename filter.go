@@ -0,0 +1,115 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// FilterPaths returns the names which are not ignored by the PathSpec, in
+// their original order. Directories are denoted by a trailing slash, e.g.
+// "build/".
+func (ps *PathSpec) FilterPaths(names []string) []string {
+	included, _ := ps.FilterContext(context.Background(), names)
+	return included
+}
+
+// FilterIgnored returns the names which are ignored by the PathSpec, in their
+// original order. It is the counterpart of FilterPaths.
+func (ps *PathSpec) FilterIgnored(names []string) []string {
+	var ignored []string
+	for _, name := range names {
+		if ps.Match(name) {
+			ignored = append(ignored, name)
+		}
+	}
+	return ignored
+}
+
+// FilterContext is like FilterPaths, but stops and returns ctx.Err() as soon
+// as ctx is cancelled or its deadline expires.
+func (ps *PathSpec) FilterContext(ctx context.Context, names []string) ([]string, error) {
+	var included []string
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !ps.Match(name) {
+			included = append(included, name)
+		}
+	}
+	return included, nil
+}
+
+// FilterStream copies the paths read from r which are not ignored by the
+// PathSpec to w, in their original order. Paths are separated by sep, usually
+// '\n' or 0 for the output of commands like "git ls-files -z", and are
+// written followed by sep. Like bufio.ScanLines, a '\r' before a '\n'
+// separator is dropped, and so are empty paths. Directories are denoted
+// by a trailing slash, e.g. "build/". Paths are matched one at a time as they
+// are read, so the input is never held in memory as a whole.
+func (ps *PathSpec) FilterStream(r io.Reader, w io.Writer, sep byte) error {
+	return ps.filterStream(r, w, sep, false)
+}
+
+// FilterIgnoredStream is like FilterStream, but copies the paths which are
+// ignored by the PathSpec. It is the counterpart of FilterStream.
+func (ps *PathSpec) FilterIgnoredStream(r io.Reader, w io.Writer, sep byte) error {
+	return ps.filterStream(r, w, sep, true)
+}
+
+// filterStream copies the paths read from r whose ignored state is ignored
+// to w.
+func (ps *PathSpec) filterStream(r io.Reader, w io.Writer, sep byte, ignored bool) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	// long collects paths which do not fit into the buffer of br.
+	var long []byte
+	for {
+		name, err := br.ReadSlice(sep)
+		if err == bufio.ErrBufferFull {
+			long = append(long, name...)
+			continue
+		}
+		if len(long) > 0 {
+			long = append(long, name...)
+			name = long
+		}
+		if len(name) > 0 && name[len(name)-1] == sep {
+			name = name[:len(name)-1]
+		}
+		if sep == '\n' && len(name) > 0 && name[len(name)-1] == '\r' {
+			name = name[:len(name)-1]
+		}
+		if len(name) > 0 && ps.MatchBytes(name, false) == ignored {
+			bw.Write(name)
+			if werr := bw.WriteByte(sep); werr != nil {
+				return werr
+			}
+		}
+		long = long[:0]
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
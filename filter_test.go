@@ -0,0 +1,108 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPathSpecFilterContext(t *testing.T) {
+	lines := []string{"*.log", "build/"}
+	names := []string{"main.go", "debug.log", "build/", "build/out", "src/", "src/app.go"}
+	want := []string{"main.go", "src/", "src/app.go"}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	included, err := ps.FilterContext(context.Background(), names)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if strings.Join(included, ",") != strings.Join(want, ",") {
+		t.Errorf("FilterContext('%s', '%s') returned '%s', want '%s'", lines, names, included, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ps.FilterContext(ctx, names); !errors.Is(err, context.Canceled) {
+		t.Errorf("FilterContext() with a cancelled context returned '%v', want '%v'", err, context.Canceled)
+	}
+}
+
+func TestPathSpecFilterPaths(t *testing.T) {
+	lines := []string{"*.log", "!keep.log", "build/"}
+	names := []string{"main.go", "debug.log", "keep.log", "build/", "build/out", "src/app.go"}
+	wantIncluded := []string{"main.go", "keep.log", "src/app.go"}
+	wantIgnored := []string{"debug.log", "build/", "build/out"}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	if included := ps.FilterPaths(names); strings.Join(included, ",") != strings.Join(wantIncluded, ",") {
+		t.Errorf("FilterPaths('%s', '%s') returned '%s', want '%s'", lines, names, included, wantIncluded)
+	}
+	if ignored := ps.FilterIgnored(names); strings.Join(ignored, ",") != strings.Join(wantIgnored, ",") {
+		t.Errorf("FilterIgnored('%s', '%s') returned '%s', want '%s'", lines, names, ignored, wantIgnored)
+	}
+}
+
+func TestPathSpecFilterStream(t *testing.T) {
+	ps, err := FromLines("*.log", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	long := strings.Repeat("a/", 3000) + "main.go"
+
+	tests := []struct {
+		input   string
+		sep     byte
+		ignored bool
+		want    string
+	}{
+		{"main.go\ndebug.log\nbuild/out\nsrc/app.go\n", '\n', false, "main.go\nsrc/app.go\n"},
+		{"main.go\ndebug.log\nbuild/out\nsrc/app.go\n", '\n', true, "debug.log\nbuild/out\n"},
+		{"main.go\x00a\nb.log\x00build/\x00src/app.go", 0, false, "main.go\x00src/app.go\x00"},
+		{"main.go\x00a\nb.log\x00build/\x00src/app.go", 0, true, "a\nb.log\x00build/\x00"},
+		{"\n\nmain.go\n\n", '\n', false, "main.go\n"},
+		{"main.go\r\nx.log\r\n", '\n', false, "main.go\n"},
+		{long + "\nx.log\n" + long + ".log\n", '\n', false, long + "\n"},
+		{"", '\n', false, ""},
+	}
+	for _, test := range tests {
+		var b strings.Builder
+		var err error
+		if test.ignored {
+			err = ps.FilterIgnoredStream(strings.NewReader(test.input), &b, test.sep)
+		} else {
+			err = ps.FilterStream(strings.NewReader(test.input), &b, test.sep)
+		}
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := b.String(); got != test.want {
+			t.Errorf("FilterStream(%q, %q, ignored=%v) wrote %q, want %q", test.input, test.sep, test.ignored, got, test.want)
+		}
+	}
+}
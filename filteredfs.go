@@ -0,0 +1,135 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+)
+
+// filteredFS is a view of a file system hiding the paths a PathSpec ignores.
+type filteredFS struct {
+	fsys fs.FS
+	spec *PathSpec
+}
+
+// NewFilteredFS returns a view of fsys in which the files and directories
+// ignored by spec do not exist: opening or statting them fails with an error
+// wrapping fs.ErrNotExist, and they are left out of directory listings. Like
+// in git, everything beneath an ignored directory is hidden as well, see
+// MatchGit. The root directory is never hidden.
+//
+// The returned file system implements fs.ReadDirFS, fs.ReadFileFS and
+// fs.StatFS, so it can be passed to http.FS, fs.WalkDir, fs.Glob and other
+// consumers of fs.FS.
+func NewFilteredFS(fsys fs.FS, spec *PathSpec) fs.FS {
+	return &filteredFS{fsys: fsys, spec: spec}
+}
+
+// Open opens the named file, unless it is hidden.
+func (f *filteredFS) Open(name string) (fs.File, error) {
+	info, err := f.check("open", name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return file, nil
+	}
+	return &filteredDir{File: file, fsys: f, name: name}, nil
+}
+
+// Stat returns the file information of the named file, unless it is hidden.
+func (f *filteredFS) Stat(name string) (fs.FileInfo, error) {
+	return f.check("stat", name)
+}
+
+// ReadFile returns the content of the named file, unless it is hidden.
+func (f *filteredFS) ReadFile(name string) ([]byte, error) {
+	if _, err := f.check("open", name); err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(f.fsys, name)
+}
+
+// ReadDir returns the entries of the named directory which are not hidden,
+// sorted by name, unless the directory is hidden itself.
+func (f *filteredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if _, err := f.check("readdir", name); err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(f.fsys, name)
+	return f.filter(name, entries), err
+}
+
+// check returns the file information of name, or an error if name is not a
+// valid path, does not exist or is hidden.
+func (f *filteredFS) check(op, name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := fs.Stat(f.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if name != "." && f.spec.MatchGit(dirName(name, info.IsDir())) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return info, nil
+}
+
+// filter removes the entries of the visible directory dir which are hidden
+// from entries, in place.
+func (f *filteredFS) filter(dir string, entries []fs.DirEntry) []fs.DirEntry {
+	visible := entries[:0]
+	for _, e := range entries {
+		if !f.spec.MatchPath(path.Join(dir, e.Name()), e.IsDir()) {
+			visible = append(visible, e)
+		}
+	}
+	return visible
+}
+
+// filteredDir is an open directory of a filteredFS.
+type filteredDir struct {
+	fs.File
+	fsys *filteredFS
+	name string
+}
+
+// ReadDir reads the entries of the directory like fs.ReadDirFile does,
+// skipping hidden ones.
+func (d *filteredDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rd, ok := d.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: errors.New("not implemented")}
+	}
+	for {
+		entries, err := rd.ReadDir(n)
+		entries = d.fsys.filter(d.name, entries)
+		// With n > 0, an empty result must come with an error, so read on
+		// if all entries read were hidden.
+		if n <= 0 || len(entries) > 0 || err != nil {
+			return entries, err
+		}
+	}
+}
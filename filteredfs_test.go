@@ -0,0 +1,77 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFilteredFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":                           {Data: []byte("SECRET=1")},
+		"main.go":                        {Data: []byte("package main")},
+		"debug.log":                      {},
+		"docs/index.md":                  {},
+		"docs/build":                     {},
+		"build/out/main":                 {},
+		"node_modules/left-pad/index.js": {},
+		"src/app.go":                     {},
+		"src/keep.log":                   {},
+	}
+	ps, err := FromLines(".env", "*.log", "!src/keep.log", "build/", "node_modules/", "!node_modules/left-pad/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	filtered := NewFilteredFS(fsys, ps)
+
+	if err := fstest.TestFS(filtered, "main.go", "docs/index.md", "docs/build", "src/app.go", "src/keep.log"); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for _, name := range []string{".env", "debug.log", "build", "build/out/main", "node_modules/left-pad/index.js"} {
+		if _, err := fs.Stat(filtered, name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Stat('%s') returned error '%v', want '%v'", name, err, fs.ErrNotExist)
+		}
+		if _, err := filtered.Open(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Open('%s') returned error '%v', want '%v'", name, err, fs.ErrNotExist)
+		}
+		if _, err := fs.ReadFile(filtered, name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("ReadFile('%s') returned error '%v', want '%v'", name, err, fs.ErrNotExist)
+		}
+	}
+
+	var visited []string
+	err = fs.WalkDir(filtered, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := []string{".", "docs", "docs/build", "docs/index.md", "main.go", "src", "src/app.go", "src/keep.log"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("WalkDir() visited '%v', want '%v'", visited, want)
+	}
+}
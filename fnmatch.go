@@ -0,0 +1,53 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WithFnmatch compiles the lines as plain fnmatch patterns instead of
+// gitignore patterns, see NewFnmatchPattern.
+func WithFnmatch() Option {
+	return func(o *options) {
+		o.fnmatch = true
+	}
+}
+
+// NewFnmatchPattern compiles a classic fnmatch(3) pattern without the
+// FNM_PATHNAME flag: "*" and "?" match any character including a slash, and
+// the pattern must match the whole name. There are no gitignore rules for
+// slashes, so "*.go" matches "a/b.go" and "build/" only matches "build/".
+// This suits matching flat names or strings which are not paths. A leading
+// "!" negates the pattern and a backslash escapes the next character.
+func NewFnmatchPattern(line string) (*Pattern, error) {
+	pattern := line
+	negate := strings.HasPrefix(pattern, "!")
+	if negate {
+		pattern = pattern[1:]
+	}
+	if pattern == "" {
+		return nil, ErrEmptyPattern
+	}
+	regex, err := regexp.Compile("^" + translateFnmatch(pattern, false) + "$")
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{syntax: "fnmatch", text: line, negate: negate, regex: regex}, nil
+}
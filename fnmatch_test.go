@@ -0,0 +1,72 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestNewFnmatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/pathspec/main.go", true},
+		{"a?c", "a/c", true},
+		{"src/*", "src/a/b", true},
+		{"src/*", "lib/src/a", false},
+		{"[!a]bc", "xbc", true},
+		{"[!a]bc", "abc", false},
+		{`\*`, "*", true},
+		{`\*`, "a", false},
+		{"https://*.example.com/*", "https://api.example.com/v1/users", true},
+	}
+	for _, test := range tests {
+		p, err := NewFnmatchPattern(test.pattern)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := p.Match(test.name); got != test.want {
+			t.Errorf("NewFnmatchPattern('%s').Match('%s') returned '%v', want '%v'", test.pattern, test.name, got, test.want)
+		}
+	}
+
+	if _, err := NewFnmatchPattern("!"); err == nil {
+		t.Errorf("NewFnmatchPattern('!') returned no error")
+	}
+}
+
+func TestWithFnmatch(t *testing.T) {
+	ps, err := FromLinesWithOptions([]string{"*.log", "!keep*"}, WithFnmatch())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := map[string]bool{
+		"a/b/c.log":      true,
+		"keep/debug.log": false,
+		"a/keep.log":     true,
+		"main.go":        false,
+	}
+	for name, want := range tests {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
@@ -70,6 +70,8 @@ package pathspec
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"path/filepath"
 	"regexp"
@@ -77,15 +79,23 @@ import (
 )
 
 type gitIgnorePattern struct {
-	Regex   string
-	Include bool
+	Regex    string
+	Include  bool
+	Segments []string
 }
 
 // GitIgnore uses a string slice of patterns for matching on a filepath string.
 // On match it returns true, otherwise false. On error it passes the error through.
 func GitIgnore(patterns []string, name string) (ignore bool, err error) {
 	for _, pattern := range patterns {
-		p := parsePattern(pattern)
+		p, err := parsePattern(pattern)
+		if errors.Is(err, ErrEmptyPattern) {
+			// Lines like "!" or "\" match nothing, and git accepts
+			// them.
+			continue
+		} else if err != nil {
+			return ignore, err
+		}
 		// Convert Windows paths to Unix paths
 		name = filepath.ToSlash(name)
 		match, err := regexp.MatchString(p.Regex, name)
@@ -108,14 +118,27 @@ func GitIgnore(patterns []string, name string) (ignore bool, err error) {
 //
 // ReadGitIgnore returns a boolean value if we match or not and an error.
 func ReadGitIgnore(content io.Reader, name string) (ignore bool, err error) {
-	scanner := bufio.NewScanner(content)
+	br := bufio.NewReader(content)
 
-	for scanner.Scan() {
-		pattern := strings.TrimSpace(scanner.Text())
-		if len(pattern) == 0 || pattern[0] == '#' {
+	for {
+		line, err := readLine(br, 0)
+		if err == io.EOF {
+			return ignore, nil
+		} else if err != nil {
+			return ignore, err
+		}
+		pattern, ok := patternFromLine(strings.TrimSuffix(line, "\r"))
+		if !ok {
+			continue
+		}
+		p, err := parsePattern(pattern)
+		if errors.Is(err, ErrEmptyPattern) {
+			// Lines like "!" or "\" match nothing, and git accepts
+			// them.
 			continue
+		} else if err != nil {
+			return ignore, err
 		}
-		p := parsePattern(pattern)
 		// Convert Windows paths to Unix paths
 		name = filepath.ToSlash(name)
 		match, err := regexp.MatchString(p.Regex, name)
@@ -124,24 +147,40 @@ func ReadGitIgnore(content io.Reader, name string) (ignore bool, err error) {
 		}
 		if match {
 			if p.Include {
-				return false, scanner.Err()
+				return false, nil
 			}
 			ignore = true
 		}
 	}
-	return ignore, scanner.Err()
 }
 
-func parsePattern(pattern string) *gitIgnorePattern {
-	p := &gitIgnorePattern{}
+// CompileGitIgnore reads a gitignore file and compiles it into a PathSpec.
+// It is the counterpart of ReadGitIgnore for callers checking many names
+// against the same content: compile once and reuse the PathSpec via Match
+// instead of re-parsing the reader for every name.
+//
+// The two differ for negated patterns followed by patterns matching the same
+// name: ReadGitIgnore and GitIgnore stop at the first matching negated
+// pattern, so "!foo" followed by "foo" does not ignore "foo", whereas the
+// PathSpec lets the last matching pattern decide, like git, and ignores it.
+func CompileGitIgnore(content io.Reader) (*PathSpec, error) {
+	return FromReader(content)
+}
+
+// NormalizePattern splits a gitignore pattern into its normalized path
+// segments, the form parsePattern translates into a regular expression. A
+// pattern without a leading slash gets a leading "**" segment, a trailing
+// slash becomes a trailing "**" segment and consecutive "**" segments are
+// collapsed into one. The returned negate flag is true for patterns starting
+// with "!".
+func NormalizePattern(pattern string) (segments []string, negate bool, err error) {
+	orig := pattern
 
 	// An optional prefix "!" which negates the pattern; any matching file
 	// excluded by a previous pattern will become included again.
 	if strings.HasPrefix(pattern, "!") {
 		pattern = pattern[1:]
-		p.Include = true
-	} else {
-		p.Include = false
+		negate = true
 	}
 
 	// Remove leading back-slash escape for escaped hash ('#') or
@@ -165,6 +204,9 @@ func parsePattern(pattern string) *gitIgnorePattern {
 	} else if patternSegs[0] != "**" {
 		patternSegs = append([]string{"**"}, patternSegs...)
 	}
+	if len(patternSegs) == 0 {
+		return nil, negate, fmt.Errorf("invalid pattern %q: %w", orig, ErrEmptyPattern)
+	}
 
 	// A pattern ending with a slash ('/') will match all descendant
 	// paths of if it is a directory but not if it is a regular file.
@@ -174,9 +216,55 @@ func parsePattern(pattern string) *gitIgnorePattern {
 		patternSegs[len(patternSegs)-1] = "**"
 	}
 
+	// Consecutive double-asterisks ('**/**') match the same paths as
+	// a single one, so collapse them.
+	segments = patternSegs[:1]
+	for _, seg := range patternSegs[1:] {
+		if seg == "**" && segments[len(segments)-1] == "**" {
+			continue
+		}
+		segments = append(segments, seg)
+	}
+	return segments, negate, nil
+}
+
+func parsePattern(pattern string) (*gitIgnorePattern, error) {
+	patternSegs, negate, err := NormalizePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	// A name with a trailing slash ('/') denotes a directory. Patterns
+	// ending with a slash match the directory itself and all of its
+	// descendants, other patterns match both files and directories.
+	var regex string
+	if strings.HasSuffix(pattern, "/") && len(patternSegs) > 1 {
+		regex = "^" + translateSegments(patternSegs[:len(patternSegs)-1]) + "/.*$"
+	} else {
+		regex = "^" + translateSegments(patternSegs) + "/?$"
+	}
+	return &gitIgnorePattern{Regex: regex, Include: negate, Segments: patternSegs}, nil
+}
+
+// translateSegments translates normalized pattern segments into an
+// unanchored regular expression.
+func translateSegments(patternSegs []string) string {
+	return translateSegmentsWith(patternSegs, translateGlob)
+}
+
+// translateSegmentsWith is like translateSegments, but translates segments
+// other than "*" and "**" with translate.
+func translateSegmentsWith(patternSegs []string, translate func(string) string) string {
 	// Build regular expression from pattern.
+	//
+	// Patterns with several double-asterisks ('**') result in several
+	// ".+" groups, e.g. "a/**/b/**/c" becomes
+	// "^(?:.+/)?a(?:/.+)?/b(?:/.+)?/c$". With a backtracking engine this
+	// could explode on long non-matching paths, but Go's regexp package
+	// implements RE2 which guarantees matching in time linear to the
+	// length of the input. We do not emit backreferences or lookarounds,
+	// which RE2 does not support anyway, so this guarantee holds for all
+	// generated expressions.
 	var expr bytes.Buffer
-	expr.WriteString("^")
 	needSlash := false
 
 	for i, seg := range patternSegs {
@@ -214,18 +302,27 @@ func parsePattern(pattern string) *gitIgnorePattern {
 			if needSlash {
 				expr.WriteString("/")
 			}
-			expr.WriteString(translateGlob(seg))
+			expr.WriteString(translate(seg))
 			needSlash = true
 		}
 	}
-	expr.WriteString("$")
-	p.Regex = expr.String()
-	return p
+	return expr.String()
 }
 
 // NOTE: This is derived from `fnmatch.translate()` and is similar to
 // the POSIX function `fnmatch()` with the `FNM_PATHNAME` flag set.
 func translateGlob(glob string) string {
+	return translateFnmatch(glob, true)
+}
+
+// translateFnmatch translates glob like translateGlob. If pathname is false,
+// the wildcards "*" and "?" match slashes as well, like `fnmatch()` without
+// the `FNM_PATHNAME` flag.
+func translateFnmatch(glob string, pathname bool) string {
+	wildcard := "[^/]"
+	if !pathname {
+		wildcard = "."
+	}
 	var regex bytes.Buffer
 	escape := false
 
@@ -235,28 +332,36 @@ func translateGlob(glob string) string {
 		switch {
 		case escape:
 			escape = false
-			regex.WriteString(regexp.QuoteMeta(string(char)))
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
 		case char == '\\':
 			// Escape character, escape next character.
 			escape = true
 		case char == '*':
 			// Multi-character wildcard. Match any string (except slashes),
 			// including an empty string.
-			regex.WriteString("[^/]*")
+			regex.WriteString(wildcard + "*")
 		case char == '?':
 			// Single-character wildcard. Match any single character (except
 			// a slash).
-			regex.WriteString("[^/]")
+			regex.WriteString(wildcard)
 		case char == '[':
 			regex.WriteString(translateBracketExpression(&i, glob))
 		default:
 			// Regular character, escape it for regex.
-			regex.WriteString(regexp.QuoteMeta(string(char)))
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
 		}
 	}
+	if escape {
+		// A trailing backslash escapes nothing, which makes the pattern
+		// invalid. Like git, match nothing at all.
+		regex.WriteString(matchNothing)
+	}
 	return regex.String()
 }
 
+// matchNothing is a regular expression which does not match any string.
+const matchNothing = `[^\x00-\x{10FFFF}]`
+
 // Bracket expression wildcard. Except for the beginning
 // exclamation mark, the whole bracket expression can be used
 // directly as regex but we have to find where the expression
@@ -284,16 +389,18 @@ func translateBracketExpression(i *int, glob string) string {
 	}
 
 	if j < len(glob) {
+		negate := ""
 		if glob[*i] == '!' {
-			regex = regex + "^"
+			negate = "^"
 			*i++
 		}
-		regex = regexp.QuoteMeta(glob[*i:j])
+		regex = negate + regexp.QuoteMeta(glob[*i:j])
 		*i = j
 	} else {
 		// Failed to find closing bracket, treat opening bracket as a
 		// bracket literal instead of as an expression.
-		regex = regexp.QuoteMeta(string(glob[*i]))
+		*i--
+		return regexp.QuoteMeta("[")
 	}
 	return "[" + regex + "]"
 }
@@ -74,3 +74,114 @@ func TestReadGitIgnore(t *testing.T) {
 		}
 	}
 }
+
+func TestGitIgnoreEmptyPatterns(t *testing.T) {
+	lines := []string{"!", "\\", "*.log"}
+	match, err := GitIgnore(lines, "a.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !match {
+		t.Errorf("GitIgnore(%q, a.log) returned '%v', want 'true'", lines, match)
+	}
+	match, err = ReadGitIgnore(strings.NewReader(strings.Join(lines, "\n")), "a.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !match {
+		t.Errorf("ReadGitIgnore(%q, a.log) returned '%v', want 'true'", lines, match)
+	}
+}
+
+func TestReadGitIgnoreLongLine(t *testing.T) {
+	content := strings.Repeat("a", 100000) + "\r\nfoo\r\n"
+	match, err := ReadGitIgnore(strings.NewReader(content), "foo")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !match {
+		t.Errorf("ReadGitIgnore(long line, foo) returned '%v', want 'true'", match)
+	}
+}
+
+func TestCompileGitIgnore(t *testing.T) {
+	names := []string{"!.#test", "~foo", "foo/foo.txt", "bar/foobar.txt", "foo/bar.txt", "/bar/foo", ".#test", "foo/#test#", "foo/bar/.foo.txt.swp", "foo/foobar/foobar.txt", "foo.txt", "test/foo.test", "test/foo/bar.test", "foo/bar", "foo/1/2/bar"}
+	content := []byte("# comment\n.#*\n\\#*#\n.*.sw[a-z]\n**/foobar/foobar.txt\n/foo.txt\ntest/\nfoo/**/bar\n/b[^a]r/foo")
+
+	ps, err := CompileGitIgnore(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for _, f := range names {
+		want, err := ReadGitIgnore(bytes.NewReader(content), f)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := ps.Match(f); got != want {
+			t.Errorf("CompileGitIgnore('%s').Match(%s) returned '%v', want '%v'", strings.Replace(string(content), "\n", ", ", -1), f, got, want)
+		}
+	}
+
+	// ReadGitIgnore stops at the first matching negation, the PathSpec
+	// lets the last matching pattern decide.
+	content = []byte("!foo\nfoo\n")
+	ps, err = CompileGitIgnore(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	legacy, err := ReadGitIgnore(bytes.NewReader(content), "foo")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if legacy || !ps.Match("foo") {
+		t.Errorf("ReadGitIgnore('!foo, foo', foo) returned '%v' and CompileGitIgnore().Match(foo) '%v', want 'false' and 'true'", legacy, ps.Match("foo"))
+	}
+}
+
+func TestBracketNegation(t *testing.T) {
+	tests := map[string]bool{
+		"b.txt": true,
+		"a.txt": false,
+		"!.txt": false,
+	}
+	for name, want := range tests {
+		match, err := GitIgnore([]string{"[!a!].txt"}, name)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if match != want {
+			t.Errorf("GitIgnore('[!a!].txt', %s) returned '%v', want '%v'", name, match, want)
+		}
+	}
+}
+
+func TestNormalizePattern(t *testing.T) {
+	tests := map[string][]string{
+		"foo":       {"**", "foo"},
+		"/foo":      {"foo"},
+		"foo/":      {"**", "foo", "**"},
+		"**/":       {"**"},
+		"a/**/**/b": {"**", "a", "**", "b"},
+		"!/foo/bar": {"foo", "bar"},
+	}
+
+	for pattern, want := range tests {
+		segments, negate, err := NormalizePattern(pattern)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if strings.Join(segments, ",") != strings.Join(want, ",") {
+			t.Errorf("NormalizePattern(%s) returned '%q', want '%q'", pattern, segments, want)
+		}
+		if wantNegate := strings.HasPrefix(pattern, "!"); negate != wantNegate {
+			t.Errorf("NormalizePattern(%s) returned negate '%v', want '%v'", pattern, negate, wantNegate)
+		}
+	}
+
+	for _, pattern := range []string{"!", "\\"} {
+		if _, _, err := NormalizePattern(pattern); err == nil {
+			t.Errorf("NormalizePattern(%s) returned no error, want one", pattern)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GitIgnoreFile is the name of the files GitIgnoreTree reads patterns from.
+const GitIgnoreFile = ".gitignore"
+
+// GitIgnoreTree matches paths against the .gitignore files of a whole file
+// tree. The patterns of each .gitignore file are relative to the directory
+// containing it, and like in git, patterns of a deeper .gitignore file take
+// precedence over the patterns of the .gitignore files above it. Paths inside
+// an ignored directory are always ignored, because git never looks into
+// ignored directories.
+type GitIgnoreTree struct {
+	specs map[string]*PathSpec
+	// excludes are consulted after all .gitignore files, in order.
+	excludes []*PathSpec
+}
+
+// NewGitIgnoreTree discovers and compiles the .gitignore files of fsys.
+// Ignored directories and the .git directory are not searched for .gitignore
+// files, matching git's behavior.
+func NewGitIgnoreTree(fsys fs.FS) (*GitIgnoreTree, error) {
+	return newGitIgnoreTree(fsys, nil)
+}
+
+// FromFS is like NewGitIgnoreTree, but discovers the ignore files with the
+// given names instead of .gitignore, like ".npmignore" or ".prettierignore".
+// The files are compiled and applied with gitignore semantics, whatever their
+// name. If a directory contains several of them, they are combined in the
+// order of names, so patterns of later names take precedence. Without names,
+// FromFS reads .gitignore files.
+func FromFS(fsys fs.FS, names ...string) (*GitIgnoreTree, error) {
+	return newGitIgnoreTree(fsys, names)
+}
+
+// newGitIgnoreTree discovers and compiles the ignore files called names,
+// .gitignore if there are none, and consults excludes, in order, after them.
+func newGitIgnoreTree(fsys fs.FS, names []string, excludes ...*PathSpec) (*GitIgnoreTree, error) {
+	if len(names) == 0 {
+		names = []string{GitIgnoreFile}
+	}
+	t := &GitIgnoreTree{specs: make(map[string]*PathSpec), excludes: excludes}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if name != "." && (d.Name() == ".git" || t.MatchPath(name, true)) {
+			return fs.SkipDir
+		}
+		return t.readDir(fsys, name, names)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// readDir compiles the ignore files called names in the directory dir of
+// fsys, if there are any, combined in the order of names.
+func (t *GitIgnoreTree) readDir(fsys fs.FS, dir string, names []string) error {
+	var specs []*PathSpec
+	for _, file := range names {
+		ps, err := readIgnoreFile(fsys, path.Join(dir, file))
+		if err != nil {
+			return err
+		}
+		if ps != nil {
+			specs = append(specs, ps)
+		}
+	}
+	switch len(specs) {
+	case 0:
+	case 1:
+		t.specs[dir] = specs[0]
+	default:
+		t.specs[dir] = Merge(specs...)
+	}
+	return nil
+}
+
+// readIgnoreFile compiles the ignore file source of fsys. It returns nil if
+// the file does not exist.
+func readIgnoreFile(fsys fs.FS, source string) (*PathSpec, error) {
+	f, err := fsys.Open(source)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines, err := readLines(f)
+	if err != nil {
+		return nil, err
+	}
+	return compileLines(source, lines, NewPattern)
+}
+
+// Match reports whether name is ignored. name is relative to the root of the
+// tree. Directories are denoted by a trailing slash, e.g. "build/".
+func (t *GitIgnoreTree) Match(name string) bool {
+	return t.MatchState(name) == StateIgnored
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (t *GitIgnoreTree) MatchPath(name string, isDir bool) bool {
+	return t.Match(dirName(name, isDir))
+}
+
+// MatchState is like Match, but distinguishes names no pattern matched from
+// names a negated pattern explicitly re-included.
+func (t *GitIgnoreTree) MatchState(name string) MatchState {
+	_, p, _ := t.decide(name)
+	return patternState(p)
+}
+
+// MatchP returns details about the pattern deciding about name, or nil if no
+// pattern matched. If name lies inside an ignored directory, the result
+// describes the pattern ignoring that directory.
+func (t *GitIgnoreTree) MatchP(name string) *MatchResult {
+	name = filepath.ToSlash(name)
+	matched, p, i := t.decide(name)
+	if p == nil {
+		return nil
+	}
+	return newMatchResult(p, i, name, matched)
+}
+
+// decide returns the pattern deciding about name, its position in its
+// PathSpec, and the slash-separated path it matched, which is either name or
+// one of its parent directories.
+func (t *GitIgnoreTree) decide(name string) (string, *Pattern, int) {
+	name = filepath.ToSlash(name)
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.TrimSuffix(name, "/")
+
+	// Git does not descend into ignored directories, so nothing below
+	// them can be re-included.
+	for i := strings.IndexByte(name, '/'); i >= 0; i = nextSlash(name, i) {
+		if p, j, _ := t.match(name[:i], true); p != nil && !p.negate {
+			return name[:i+1], p, j
+		}
+	}
+	name = dirName(name, isDir)
+	p, i, rel := t.match(strings.TrimSuffix(name, "/"), isDir)
+	if p == nil {
+		return name, nil, -1
+	}
+	return name[:len(name)-len(rel)] + p.matchedPath(rel), p, i
+}
+
+// match matches name against the .gitignore files of its ancestor
+// directories, deepest first, and then against the excludes. It returns the
+// first matching pattern, its position in its PathSpec, and the path it
+// matched, relative to the directory of the matching file.
+func (t *GitIgnoreTree) match(name string, isDir bool) (*Pattern, int, string) {
+	dir := path.Dir(name)
+	for {
+		if ps, ok := t.specs[dir]; ok {
+			rel := name
+			if dir != "." {
+				rel = name[len(dir)+1:]
+			}
+			if i := ps.lastMatchIndex(dirName(rel, isDir)); i >= 0 {
+				return ps.patterns[i], i, dirName(rel, isDir)
+			}
+		}
+		if dir == "." {
+			break
+		}
+		dir = path.Dir(dir)
+	}
+	for _, ps := range t.excludes {
+		if i := ps.lastMatchIndex(dirName(name, isDir)); i >= 0 {
+			return ps.patterns[i], i, dirName(name, isDir)
+		}
+	}
+	return nil, -1, ""
+}
+
+// nextSlash returns the index of the next slash in name after index i, or -1.
+func nextSlash(name string, i int) int {
+	j := strings.IndexByte(name[i+1:], '/')
+	if j < 0 {
+		return -1
+	}
+	return i + 1 + j
+}
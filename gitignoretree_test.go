@@ -0,0 +1,110 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestGitIgnoreTree(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":             {Data: []byte("*.log\n/build/\ntmp/\n")},
+		"src/.gitignore":         {Data: []byte("!keep.log\n/gen\n")},
+		"src/lib/.gitignore":     {Data: []byte("*.go\n!*_test.go\n")},
+		"tmp/.gitignore":         {Data: []byte("!*\n")},
+		"tmp/important.txt":      {},
+		"src/keep.log":           {},
+		"src/debug.log":          {},
+		"src/gen/code.go":        {},
+		"src/lib/lib.go":         {},
+		"src/lib/lib_test.go":    {},
+		"src/lib/gen/code.go":    {},
+		"build/out":              {},
+		"src/build/out":          {},
+		".git/info/exclude":      {},
+		".git/nested/.gitignore": {Data: []byte("*\n")},
+	}
+	toInclude := []string{"src/keep.log", "src/lib/lib_test.go", "src/lib/gen/", "src/build/out", "src/", "README.md"}
+	toIgnore := []string{"debug.log", "src/debug.log", "src/gen/", "src/gen/code.go", "src/lib/lib.go", "src/lib/gen/code.go", "build/out", "tmp/important.txt"}
+
+	tree, err := NewGitIgnoreTree(fsys)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if _, ok := tree.specs["tmp"]; ok {
+		t.Errorf("NewGitIgnoreTree() read tmp/.gitignore inside an ignored directory")
+	}
+	if _, ok := tree.specs[".git/nested"]; ok {
+		t.Errorf("NewGitIgnoreTree() read .git/nested/.gitignore inside the .git directory")
+	}
+
+	for _, f := range toInclude {
+		if tree.Match(f) {
+			t.Errorf("GitIgnoreTree.Match(%s) returned 'true', want 'false'", f)
+		}
+	}
+
+	for _, f := range toIgnore {
+		if !tree.Match(f) {
+			t.Errorf("GitIgnoreTree.Match(%s) returned 'false', want 'true'", f)
+		}
+	}
+
+	if got := tree.MatchState("src/keep.log"); got != StateIncluded {
+		t.Errorf("GitIgnoreTree.MatchState(src/keep.log) returned '%v', want '%v'", got, StateIncluded)
+	}
+}
+
+func TestFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		".prettierignore":        {Data: []byte("*.log\n")},
+		".npmignore":             {Data: []byte("!keep.log\n")},
+		".gitignore":             {Data: []byte("*\n")},
+		"web/.npmignore":         {Data: []byte("dist/\n")},
+		"web/dist/app.js":        {},
+		"web/src/app.js":         {},
+		"logs/keep.log":          {},
+		"logs/debug.log":         {},
+		"vendor/.prettierignore": {Data: []byte("*.md\n")},
+		"vendor/lib/README.md":   {},
+		"vendor/lib/lib.go":      {},
+		"other/.gitignore":       {Data: []byte("*.go\n")},
+		"other/main.go":          {},
+	}
+	tree, err := FromFS(fsys, ".prettierignore", ".npmignore")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for name, want := range map[string]MatchState{
+		"logs/debug.log":       StateIgnored,
+		"logs/keep.log":        StateIncluded,
+		"web/dist/app.js":      StateIgnored,
+		"web/src/app.js":       StateUnmatched,
+		"vendor/lib/README.md": StateIgnored,
+		"vendor/lib/lib.go":    StateUnmatched,
+		"other/main.go":        StateUnmatched,
+	} {
+		if got := tree.MatchState(name); got != want {
+			t.Errorf("GitIgnoreTree.MatchState(%s) returned '%v', want '%v'", name, got, want)
+		}
+	}
+	if p := tree.MatchP("web/dist/app.js"); p == nil || p.Source != "web/.npmignore" {
+		t.Errorf("GitIgnoreTree.MatchP(web/dist/app.js) returned %+v, want a pattern from web/.npmignore", p)
+	}
+}
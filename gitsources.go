@@ -0,0 +1,186 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FromGitSources combines all sources of ignore patterns git consults for the
+// work tree at repoRoot, with git's precedence, highest first:
+//
+// The .gitignore files of the work tree, where deeper files take precedence.
+//
+// The $GIT_DIR/info/exclude file of the repository.
+//
+// The file configured as core.excludesFile in the repository, global or XDG
+// git configuration, which defaults to $XDG_CONFIG_HOME/git/ignore, or
+// ~/.config/git/ignore if XDG_CONFIG_HOME is not set.
+//
+// Missing files are skipped. The git directory is found at repoRoot/.git,
+// which may also be a "gitdir:" file as used by worktrees and submodules.
+// Like git, linked worktrees share the info/exclude file and configuration
+// of the repository they belong to, found with the "commondir" file of
+// their git directory.
+func FromGitSources(repoRoot string) (*GitIgnoreTree, error) {
+	gitDir, err := findGitDir(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var excludes []*PathSpec
+	for _, name := range []string{filepath.Join(gitDir, "info", "exclude"), excludesFile(gitDir)} {
+		if name == "" {
+			continue
+		}
+		ps, err := FromFile(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		excludes = append(excludes, ps)
+	}
+	return newGitIgnoreTree(os.DirFS(repoRoot), nil, excludes...)
+}
+
+// findGitDir returns the git directory of the work tree at repoRoot, or, for
+// a linked worktree, the common git directory of its repository.
+func findGitDir(repoRoot string) (string, error) {
+	gitDir := filepath.Join(repoRoot, ".git")
+	fi, err := os.Stat(gitDir)
+	if err != nil {
+		return gitDir, nil
+	}
+	if fi.IsDir() {
+		return commonDir(gitDir)
+	}
+	data, err := os.ReadFile(gitDir)
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return gitDir, nil
+	}
+	dir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoRoot, dir)
+	}
+	return commonDir(dir)
+}
+
+// commonDir returns the directory named by the "commondir" file of gitDir,
+// relative to gitDir unless it is absolute, or gitDir itself if there is no
+// such file.
+func commonDir(gitDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return gitDir, nil
+	} else if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return dir, nil
+}
+
+// excludesFile returns the path of the global excludes file: the value of
+// core.excludesFile of the repository, global or XDG configuration, in this
+// order of precedence, or git's default.
+func excludesFile(gitDir string) string {
+	home, _ := os.UserHomeDir()
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+
+	configs := []string{filepath.Join(gitDir, "config")}
+	if home != "" {
+		configs = append(configs, filepath.Join(home, ".gitconfig"))
+	}
+	if xdg != "" {
+		configs = append(configs, filepath.Join(xdg, "git", "config"))
+	}
+	for _, config := range configs {
+		if value, ok := gitConfigValue(config, "core", "excludesfile"); ok {
+			if strings.HasPrefix(value, "~/") && home != "" {
+				value = filepath.Join(home, value[2:])
+			}
+			return value
+		}
+	}
+	if xdg == "" {
+		return ""
+	}
+	return filepath.Join(xdg, "git", "ignore")
+}
+
+// gitConfigValue returns the last value of section.key in the git config
+// file name. It understands the basic syntax of git config files only, which
+// is sufficient for core.excludesFile: no includes, subsections or escapes
+// besides double quotes around the value.
+func gitConfigValue(name, section, key string) (string, bool) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	var value string
+	var found bool
+	current := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				continue
+			}
+			current = strings.ToLower(strings.TrimSpace(line[1:end]))
+			line = strings.TrimSpace(line[end+1:])
+			if line == "" {
+				continue
+			}
+		}
+		if current != section {
+			continue
+		}
+		i := strings.IndexByte(line, '=')
+		if i < 0 || strings.ToLower(strings.TrimSpace(line[:i])) != key {
+			continue
+		}
+		v := strings.TrimSpace(line[i+1:])
+		if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+			v = v[1 : len(v)-1]
+		}
+		value, found = v, true
+	}
+	return value, found
+}
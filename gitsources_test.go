@@ -0,0 +1,128 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates the files with the given contents below dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+	}
+}
+
+func TestFromGitSources(t *testing.T) {
+	home := t.TempDir()
+	repo := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	writeFiles(t, home, map[string]string{
+		".gitconfig":         "[user]\n\tname = test\n[core]\n\texcludesFile = ~/global-ignore\n",
+		"global-ignore":      "*.swp\n*.orig\n.idea/\n",
+		".config/git/ignore": "*.xdg\n",
+	})
+	writeFiles(t, repo, map[string]string{
+		".git/info/exclude": "local/\n!keep.orig\n",
+		".gitignore":        "*.log\n!.idea/\n",
+		"src/.gitignore":    "!*.swp\n",
+	})
+
+	tree, err := FromGitSources(repo)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := map[string]MatchState{
+		"a.log":     StateIgnored,
+		"a.swp":     StateIgnored,
+		"src/a.swp": StateIncluded,
+		"a.orig":    StateIgnored,
+		"keep.orig": StateIncluded,
+		"local/x":   StateIgnored,
+		".idea/":    StateIncluded,
+		"a.xdg":     StateUnmatched,
+		"src/a.go":  StateUnmatched,
+	}
+	for name, want := range tests {
+		if got := tree.MatchState(name); got != want {
+			t.Errorf("FromGitSources().MatchState(%s) returned '%v', want '%v'", name, got, want)
+		}
+	}
+
+	// Without core.excludesFile, the XDG default is used.
+	if err := os.Remove(filepath.Join(home, ".gitconfig")); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tree, err = FromGitSources(repo)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !tree.Match("a.xdg") || tree.Match("a.swp") {
+		t.Errorf("FromGitSources() does not use the XDG default excludes file")
+	}
+}
+
+func TestFromGitSourcesLinkedWorktree(t *testing.T) {
+	home := t.TempDir()
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	writeFiles(t, home, map[string]string{
+		"global-ignore": "*.swp\n",
+	})
+	writeFiles(t, repo, map[string]string{
+		".git/info/exclude":                "local/\n",
+		".git/config":                      "[core]\n\texcludesFile = ~/global-ignore\n",
+		".git/worktrees/feature/commondir": "../..\n",
+		".git/worktrees/feature/HEAD":      "ref: refs/heads/feature\n",
+		// git does not read the info/exclude file of a linked worktree.
+		".git/worktrees/feature/info/exclude": "*.go\n",
+	})
+	writeFiles(t, worktree, map[string]string{
+		".git":       "gitdir: " + filepath.Join(repo, ".git", "worktrees", "feature") + "\n",
+		".gitignore": "*.log\n",
+	})
+
+	tree, err := FromGitSources(worktree)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := map[string]MatchState{
+		"a.log":    StateIgnored,
+		"local/x":  StateIgnored,
+		"a.swp":    StateIgnored,
+		"src/a.go": StateUnmatched,
+	}
+	for name, want := range tests {
+		if got := tree.MatchState(name); got != want {
+			t.Errorf("FromGitSources().MatchState(%s) returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
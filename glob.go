@@ -0,0 +1,55 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// Glob returns the names of all files and directories of fsys matching the
+// gitignore pattern, in lexical order, or nil if there are none. It is a
+// substitute for fs.Glob using the gitignore dialect: "**" matches any number
+// of directories, a pattern without a slash matches at any depth, and a
+// pattern with a trailing slash only matches directories. Unlike in a
+// gitignore file, the contents of a matched directory are not matched, and a
+// negated pattern matches the same names as without its "!".
+//
+// Directories beneath which the pattern cannot match are not read. Like
+// fs.Glob, Glob ignores I/O errors, so the only possible error is an invalid
+// pattern.
+func Glob(fsys fs.FS, pattern string) ([]string, error) {
+	p, err := NewPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == "." {
+			return nil
+		}
+		if p.MatchDetail(dirName(name, d.IsDir())) == DetailSelf {
+			matches = append(matches, name)
+		}
+		if d.IsDir() && !mayMatchBeneath(p, strings.Split(name, "/")) {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	return matches, nil
+}
@@ -0,0 +1,94 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGlob(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":                   {},
+		"README.md":                 {},
+		"build/out.o":               {},
+		"cmd/pathspec/main.go":      {},
+		"cmd/pathspec/build/cmd.o":  {},
+		"docs/index.md":             {},
+		"docs/guide/index.md":       {},
+		"vendor/github.com/x/x.go":  {},
+		"vendor/github.com/x/build": {},
+	}
+	cases := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.go", []string{"cmd/pathspec/main.go", "main.go", "vendor/github.com/x/x.go"}},
+		{"/*.go", []string{"main.go"}},
+		{"docs/**/index.md", []string{"docs/guide/index.md", "docs/index.md"}},
+		{"build/", []string{"build", "cmd/pathspec/build"}},
+		{"build", []string{"build", "cmd/pathspec/build", "vendor/github.com/x/build"}},
+		{"cmd/**", []string{"cmd/pathspec", "cmd/pathspec/build", "cmd/pathspec/build/cmd.o", "cmd/pathspec/main.go"}},
+		{"*.c", nil},
+	}
+	for _, c := range cases {
+		got, err := Glob(fsys, c.pattern)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Glob('%s') returned '%v', want '%v'", c.pattern, got, c.want)
+		}
+	}
+
+	if _, err := Glob(fsys, ""); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("Glob('') returned error '%v', want '%v'", err, ErrEmptyPattern)
+	}
+}
+
+// openFS records the directories opened.
+type openFS struct {
+	fs.FS
+	opened []string
+}
+
+func (o *openFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	o.opened = append(o.opened, name)
+	return fs.ReadDir(o.FS, name)
+}
+
+func TestGlobPrunes(t *testing.T) {
+	fsys := &openFS{FS: fstest.MapFS{
+		"docs/index.md":           {},
+		"docs/guide/index.md":     {},
+		"node_modules/x/index.md": {},
+	}}
+	got, err := Glob(fsys, "/docs/*.md")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if want := []string{"docs/index.md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Glob('/docs/*.md') returned '%v', want '%v'", got, want)
+	}
+	if want := []string{".", "docs"}; !reflect.DeepEqual(fsys.opened, want) {
+		t.Errorf("Glob('/docs/*.md') read '%v', want '%v'", fsys.opened, want)
+	}
+}
@@ -0,0 +1,70 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package gogit adapts PathSpecs to the Matcher interface of go-git's
+// plumbing/format/gitignore package, so go-git users can match ignored files
+// with this package instead:
+//
+//	type Matcher interface {
+//		Match(path []string, isDir bool) bool
+//	}
+//
+// The package does not depend on go-git; its Matcher satisfies the interface
+// structurally. Unlike go-git's own matcher, it follows git in ignoring
+// everything beneath an ignored directory, even if a later pattern would
+// re-include it.
+package gogit
+
+import (
+	"strings"
+
+	pathspec "github.com/shibumi/go-pathspec"
+)
+
+// Matcher matches paths split into segments, like go-git's
+// gitignore.Matcher.
+type Matcher struct {
+	match func(name string) bool
+}
+
+// NewMatcher returns a Matcher reporting the paths spec ignores, see
+// pathspec.PathSpec.MatchGit. Paths are relative to the directory of the
+// patterns, usually the root of the worktree.
+func NewMatcher(spec *pathspec.PathSpec) *Matcher {
+	return &Matcher{match: spec.MatchGit}
+}
+
+// NewTreeMatcher returns a Matcher reporting the paths the .gitignore files
+// of tree ignore, like go-git's matcher built from the patterns of
+// gitignore.ReadPatterns. Paths are relative to the root of the tree.
+func NewTreeMatcher(tree *pathspec.GitIgnoreTree) *Matcher {
+	return &Matcher{match: tree.Match}
+}
+
+// Match reports whether the path given by its segments, like
+// []string{"src", "main.o"}, is ignored. isDir tells whether the path is a
+// directory.
+func (m *Matcher) Match(path []string, isDir bool) bool {
+	if len(path) == 0 {
+		return false
+	}
+	name := strings.Join(path, "/")
+	if isDir {
+		name += "/"
+	}
+	return m.match(name)
+}
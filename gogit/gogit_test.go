@@ -0,0 +1,71 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gogit
+
+import (
+	"testing"
+	"testing/fstest"
+
+	pathspec "github.com/shibumi/go-pathspec"
+)
+
+// matcher is the Matcher interface of go-git's gitignore package.
+type matcher interface {
+	Match(path []string, isDir bool) bool
+}
+
+var _ matcher = (*Matcher)(nil)
+
+func TestMatcher(t *testing.T) {
+	spec, err := pathspec.FromLines("*.log", "!keep.log", "build/", "!build/keep.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n!keep.log\nbuild/\n")},
+		"src/.gitignore": {Data: []byte("*.c\n")},
+	}
+	tree, err := pathspec.NewGitIgnoreTree(fsys)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		path  []string
+		isDir bool
+		want  bool
+	}{
+		{nil, false, false},
+		{[]string{"a.log"}, false, true},
+		{[]string{"logs", "keep.log"}, false, false},
+		{[]string{"build"}, true, true},
+		{[]string{"build"}, false, false},
+		{[]string{"build", "keep.log"}, false, true},
+		{[]string{"src", "main.go"}, false, false},
+	}
+	for _, m := range []*Matcher{NewMatcher(spec), NewTreeMatcher(tree)} {
+		for _, test := range tests {
+			if got := m.Match(test.path, test.isDir); got != test.want {
+				t.Errorf("Match(%q, %t) returned '%t', want '%t'", test.path, test.isDir, got, test.want)
+			}
+		}
+	}
+	if got := NewTreeMatcher(tree).Match([]string{"src", "main.c"}, false); !got {
+		t.Errorf("Match([src main.c], false) returned '%t', want 'true'", got)
+	}
+}
@@ -0,0 +1,105 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+)
+
+// HashDir returns the hex encoded digest, computed with a hash returned by h,
+// of the paths and contents of the files of fsys which spec does not ignore.
+// Ignored directories are not descended into, like with Walk. A nil spec
+// hashes all files.
+//
+// The digest only depends on the paths and contents, not on permissions or
+// modification times, so it can serve as the key of a build cache or to
+// detect changes. Directories only contribute by the paths of their files,
+// so empty directories do not change the digest, like in git. Symbolic links
+// are hashed by their target, which requires fsys to implement
+// "ReadLink(name string) (string, error)". Other file types, like devices
+// and sockets, are skipped.
+func HashDir(fsys fs.FS, spec *PathSpec, h func() hash.Hash) (string, error) {
+	if spec == nil {
+		spec = NewPathSpec()
+	}
+	sum := h()
+	err := spec.Walk(fsys, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch mode := d.Type(); {
+		case mode.IsRegular():
+			return hashFile(sum, fsys, name)
+		case mode&fs.ModeSymlink != 0:
+			rfs, ok := fsys.(readLinkFS)
+			if !ok {
+				return fmt.Errorf("cannot hash symbolic link %s: file system cannot read links", name)
+			}
+			link, err := rfs.ReadLink(name)
+			if err != nil {
+				return err
+			}
+			hashEntry(sum, 'l', name, uint64(len(link)))
+			_, err = io.WriteString(sum, link)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+// hashFile writes the path and content of the regular file name of fsys to
+// sum.
+func hashFile(sum hash.Hash, fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hashEntry(sum, 'f', name, uint64(info.Size()))
+	n, err := io.Copy(sum, f)
+	if err == nil && n != info.Size() {
+		err = fmt.Errorf("cannot hash %s: size changed while reading", name)
+	}
+	return err
+}
+
+// hashEntry writes the header of an entry to sum: its kind, its path
+// terminated by a NUL byte, which cannot occur in paths, and the length of
+// the data following it. The header keeps the encoding of different trees
+// distinct.
+func hashEntry(sum hash.Hash, kind byte, name string, size uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], size)
+	sum.Write([]byte{kind})
+	io.WriteString(sum, name)
+	sum.Write([]byte{0})
+	sum.Write(buf[:])
+}
@@ -0,0 +1,77 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"crypto/sha256"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHashDir(t *testing.T) {
+	tree := func() fstest.MapFS {
+		return fstest.MapFS{
+			"main.go":       {Data: []byte("package main"), Mode: 0o644},
+			"debug.log":     {Data: []byte("log")},
+			"build/out":     {Data: []byte("out")},
+			"docs/index.md": {Data: []byte("# Docs")},
+		}
+	}
+	spec, err := FromLines("*.log", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	base, err := HashDir(tree(), spec, sha256.New)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if len(base) != 2*sha256.Size {
+		t.Errorf("HashDir() returned '%s', want a hex encoded SHA-256 digest", base)
+	}
+
+	cases := []struct {
+		desc   string
+		change func(fstest.MapFS)
+		same   bool
+	}{
+		{"unchanged", func(fstest.MapFS) {}, true},
+		{"ignored file changed", func(m fstest.MapFS) { m["debug.log"].Data = []byte("more") }, true},
+		{"ignored file added", func(m fstest.MapFS) { m["build/new"] = &fstest.MapFile{} }, true},
+		{"permissions changed", func(m fstest.MapFS) { m["main.go"].Mode = 0o755 }, true},
+		{"empty directory added", func(m fstest.MapFS) { m["empty"] = &fstest.MapFile{Mode: fs.ModeDir} }, true},
+		{"content changed", func(m fstest.MapFS) { m["main.go"].Data = []byte("package foo") }, false},
+		{"file renamed", func(m fstest.MapFS) { m["main2.go"] = m["main.go"]; delete(m, "main.go") }, false},
+		{"file added", func(m fstest.MapFS) { m["docs/new.md"] = &fstest.MapFile{} }, false},
+		{"content moved between files", func(m fstest.MapFS) {
+			m["main.go"].Data = []byte("package")
+			m["docs/index.md"].Data = []byte(" main# Docs")
+		}, false},
+	}
+	for _, c := range cases {
+		fsys := tree()
+		c.change(fsys)
+		got, err := HashDir(fsys, spec, sha256.New)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if (got == base) != c.same {
+			t.Errorf("HashDir() after %s returned '%s', base '%s'", c.desc, got, base)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// HgIgnore compiles a PathSpec from Mercurial .hgignore lines. Unlike
+// gitignore, patterns are regular expressions by default:
+//
+// A "syntax: glob" line switches the following lines to glob syntax, and
+// "syntax: regexp" switches them back. "syntax: rootglob" selects globs
+// anchored at the root of the repository. A single line can override the
+// syntax with a "glob:", "re:", "regexp:" or "rootglob:" prefix.
+//
+// Regular expressions match anywhere in the path, unless anchored with "^".
+// They are Go regular expressions, so Python only features like lookarounds
+// are reported as errors.
+//
+// Globs are not anchored: "*.o" matches in every directory. "*" and "?" do
+// not match slashes, "**" does, and "{a,b}" matches either alternative. A
+// glob matching a directory also matches everything beneath it.
+//
+// "#" starts a comment anywhere in a line, unless escaped as "\#". There are
+// no negated patterns. The "include:" and "subinclude:" directives are not
+// supported.
+func HgIgnore(lines ...string) (*PathSpec, error) {
+	syntax := "regexp"
+	var patterns []*Pattern
+	var errs ParseErrors
+	for i, line := range lines {
+		if i == 0 {
+			line = strings.TrimPrefix(line, byteOrderMark)
+		}
+		line = stripHgComment(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "syntax:") {
+			s, err := hgSyntax(strings.TrimSpace(line[len("syntax:"):]))
+			if err != nil {
+				errs = append(errs, &ParseError{Line: i + 1, Pattern: line, Err: err})
+				continue
+			}
+			syntax = s
+			continue
+		}
+		p, err := newHgPattern(syntax, line)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: i + 1, Pattern: line, Err: err})
+			continue
+		}
+		p.line = i + 1
+		patterns = append(patterns, p)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return NewPathSpec(patterns...), nil
+}
+
+// FromHgignore compiles a PathSpec from a Mercurial .hgignore file, line by
+// line. See HgIgnore for the syntax.
+func FromHgignore(r io.Reader) (*PathSpec, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return HgIgnore(lines...)
+}
+
+// hgSyntax returns the canonical name of the .hgignore syntax name.
+func hgSyntax(name string) (string, error) {
+	switch name {
+	case "re", "regexp":
+		return "regexp", nil
+	case "glob", "rootglob":
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown hgignore syntax %q", name)
+}
+
+// stripHgComment removes a comment starting with an unescaped "#" from line,
+// unescapes "\#" and trims trailing white space.
+func stripHgComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if line[i] == '#' {
+			line = line[:i]
+			break
+		}
+	}
+	return strings.TrimRight(strings.ReplaceAll(line, `\#`, "#"), " \t\r")
+}
+
+// newHgPattern compiles a single .hgignore pattern of the given default
+// syntax, which a prefix like "glob:" may override.
+func newHgPattern(syntax, line string) (*Pattern, error) {
+	pattern := line
+	if i := strings.IndexByte(pattern, ':'); i > 0 {
+		switch prefix := pattern[:i]; prefix {
+		case "re", "regexp", "glob", "rootglob":
+			syntax, _ = hgSyntax(prefix)
+			pattern = pattern[i+1:]
+		case "include", "subinclude":
+			return nil, fmt.Errorf("unsupported hgignore directive %q", prefix)
+		}
+	}
+
+	var expr string
+	switch syntax {
+	case "regexp":
+		// Regular expressions are searched for, not matched, and see
+		// directories without their trailing slash.
+		expr = pattern
+		if !strings.HasPrefix(expr, "^") {
+			expr = "^.*(?:" + expr + ")"
+		}
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		return &Pattern{syntax: "hgignore", text: line, matcher: hgRegexp{regex}}, nil
+	case "glob":
+		expr = "^(?:.*/)?" + translateHgGlob(pattern) + "(?:/|$)"
+	case "rootglob":
+		expr = "^" + translateHgGlob(pattern) + "(?:/|$)"
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{syntax: "hgignore", text: line, regex: regex}, nil
+}
+
+// hgRegexp matches an .hgignore regular expression against paths without the
+// trailing slash denoting directories, like Mercurial does.
+type hgRegexp struct {
+	regex *regexp.Regexp
+}
+
+// Match implements Matcher.
+func (m hgRegexp) Match(name string) bool {
+	return m.regex.MatchString(strings.TrimSuffix(name, "/"))
+}
+
+// translateHgGlob translates a Mercurial glob into a regular expression.
+func translateHgGlob(glob string) string {
+	var regex bytes.Buffer
+	group := 0
+	for i := 0; i < len(glob); i++ {
+		switch char := glob[i]; {
+		case char == '\\' && i+1 < len(glob):
+			i++
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case char == '*' && strings.HasPrefix(glob[i:], "**/"):
+			regex.WriteString("(?:.*/)?")
+			i += 2
+		case char == '*' && strings.HasPrefix(glob[i:], "**"):
+			regex.WriteString(".*")
+			i++
+		case char == '*':
+			regex.WriteString("[^/]*")
+		case char == '?':
+			regex.WriteString("[^/]")
+		case char == '[':
+			regex.WriteString(translateBracketExpression(&i, glob))
+		case char == '{':
+			group++
+			regex.WriteString("(?:")
+		case char == '}' && group > 0:
+			group--
+			regex.WriteString(")")
+		case char == ',' && group > 0:
+			regex.WriteString("|")
+		default:
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	for ; group > 0; group-- {
+		regex.WriteString(")")
+	}
+	return regex.String()
+}
@@ -0,0 +1,87 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHgIgnore(t *testing.T) {
+	content := `# build output
+\.orig$
+^out(/|$)
+syntax: glob
+*.pyc
+**/cache/*.bin
+docs/{api,guide}/*.html
+rootglob:tmp
+re:^logs/.*\.log$
+syntax: rootglob
+dist  # release artifacts
+issue\#1.txt
+`
+	ps, err := FromHgignore(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"a/b.orig", true},
+		{"a/b.orig.txt", false},
+		{"out", true},
+		{"out/", true},
+		{"out/x", true},
+		{"src/out", false},
+		{"x.pyc", true},
+		{"a/b/x.pyc", true},
+		{"a/x.pyc/y", true},
+		{"cache/a.bin", true},
+		{"a/b/cache/a.bin", true},
+		{"docs/api/x.html", true},
+		{"docs/guide/x.html", true},
+		{"docs/other/x.html", false},
+		{"tmp/x", true},
+		{"a/tmp", false},
+		{"logs/a.log", true},
+		{"src/logs/a.log", false},
+		{"dist/app", true},
+		{"src/dist", false},
+		{"issue#1.txt", true},
+	}
+	for _, test := range tests {
+		if got := ps.Match(test.name); got != test.want {
+			t.Errorf("HgIgnore.Match('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+}
+
+func TestHgIgnoreErrors(t *testing.T) {
+	_, err := HgIgnore("syntax: nope", "(?<=a)b", "include:other", "ok")
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("HgIgnore() returned '%v', want ParseErrors", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("HgIgnore() returned '%v', want 3 errors", err)
+	}
+}
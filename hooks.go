@@ -0,0 +1,55 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+// Hooks are callbacks a PathSpec calls while matching, to gather statistics
+// or debug matching in production without changing the callers. Nil
+// callbacks are skipped. The callbacks must be safe for concurrent use if
+// the PathSpec is used concurrently, and should be fast, because they run
+// on every match.
+type Hooks struct {
+	// OnMatch is called with the pattern deciding about the
+	// slash-separated path name, whenever a pattern matches. Patterns
+	// overridden by later patterns are not reported, because matching
+	// stops at the last matching pattern.
+	OnMatch func(p *Pattern, name string)
+	// OnDecision is called with the decision about the slash-separated
+	// path name by MatchState and the functions built on it, like Match,
+	// MatchGit and MatchAll.
+	OnDecision func(name string, state MatchState)
+}
+
+// WithHooks installs hooks on the compiled PathSpec, see SetHooks.
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = &hooks
+	}
+}
+
+// SetHooks installs hooks, replacing previously installed ones. A nil hooks
+// removes them. SetHooks must not be called concurrently with matching.
+func (ps *PathSpec) SetHooks(hooks *Hooks) {
+	ps.hooks = hooks
+}
+
+// decided calls the OnDecision hook, if any.
+func (ps *PathSpec) decided(name string, state MatchState) {
+	if ps.hooks != nil && ps.hooks.OnDecision != nil {
+		ps.hooks.OnDecision(name, state)
+	}
+}
@@ -0,0 +1,59 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	var matches, decisions []string
+	hooks := Hooks{
+		OnMatch: func(p *Pattern, name string) {
+			matches = append(matches, p.String()+" "+name)
+		},
+		OnDecision: func(name string, state MatchState) {
+			decisions = append(decisions, name+" "+state.String())
+		},
+	}
+	ps, err := FromLinesWithOptions([]string{"*.log", "!keep.log"}, WithHooks(hooks))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	ps.Match("a.log")
+	ps.Match("keep.log")
+	ps.Match("main.go")
+	ps.MatchAll([]string{"b.log"}, nil)
+
+	wantMatches := []string{"*.log a.log", "!keep.log keep.log", "*.log b.log"}
+	if strings.Join(matches, ",") != strings.Join(wantMatches, ",") {
+		t.Errorf("OnMatch was called with '%v', want '%v'", matches, wantMatches)
+	}
+	wantDecisions := []string{"a.log ignored", "keep.log included", "main.go unmatched", "b.log ignored"}
+	if strings.Join(decisions, ",") != strings.Join(wantDecisions, ",") {
+		t.Errorf("OnDecision was called with '%v', want '%v'", decisions, wantDecisions)
+	}
+
+	ps.SetHooks(nil)
+	ps.Match("c.log")
+	if len(decisions) != len(wantDecisions) {
+		t.Errorf("OnDecision was called after SetHooks(nil)")
+	}
+}
@@ -0,0 +1,119 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package httpfs serves files over HTTP while hiding the paths a PathSpec
+// ignores, so development servers do not serve ".env", "node_modules" or
+// other excluded content by accident.
+package httpfs
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	pathspec "github.com/shibumi/go-pathspec"
+)
+
+// fileSystem is an http.FileSystem hiding the paths a PathSpec ignores.
+type fileSystem struct {
+	fsys http.FileSystem
+	spec *pathspec.PathSpec
+}
+
+// New returns an http.FileSystem serving the files of fsys which spec does
+// not ignore. Opening an ignored path fails with an error wrapping
+// fs.ErrNotExist, which http.FileServer answers with "404 Not Found", and
+// ignored paths are left out of directory listings. Like in git, everything
+// beneath an ignored directory is hidden as well, see PathSpec.MatchGit.
+// Request paths are matched relative to the root of fsys.
+func New(fsys http.FileSystem, spec *pathspec.PathSpec) http.FileSystem {
+	return &fileSystem{fsys: fsys, spec: spec}
+}
+
+// FromFS is like New, but serves the files of fsys, see
+// pathspec.NewFilteredFS.
+func FromFS(fsys fs.FS, spec *pathspec.PathSpec) http.FileSystem {
+	return http.FS(pathspec.NewFilteredFS(fsys, spec))
+}
+
+// Open opens the file called name, unless it is ignored.
+func (h *fileSystem) Open(name string) (http.File, error) {
+	rel := strings.TrimPrefix(path.Clean("/"+name), "/")
+	f, err := h.fsys.Open(name)
+	if err != nil {
+		// Do not reveal that an ignored path exists, e.g. by a permission
+		// error.
+		if rel != "" && (h.spec.MatchGit(rel) || h.spec.MatchGit(rel+"/")) {
+			return nil, notExist(name)
+		}
+		return nil, err
+	}
+	if rel == "" {
+		return &file{File: f, fsys: h, dir: rel}, nil
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if h.spec.MatchGit(dirName(rel, info.IsDir())) {
+		f.Close()
+		return nil, notExist(name)
+	}
+	return &file{File: f, fsys: h, dir: rel}, nil
+}
+
+// notExist returns the error opening the ignored path name fails with.
+func notExist(name string) error {
+	return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// dirName appends a trailing slash to name if it is a directory.
+func dirName(name string, isDir bool) string {
+	if isDir {
+		return name + "/"
+	}
+	return name
+}
+
+// file is an open file of a fileSystem. dir is its slash-separated path
+// relative to the root, which is empty for the root itself.
+type file struct {
+	http.File
+	fsys *fileSystem
+	dir  string
+}
+
+// Readdir reads the entries of the directory like http.File does, skipping
+// ignored ones.
+func (f *file) Readdir(count int) ([]fs.FileInfo, error) {
+	for {
+		infos, err := f.File.Readdir(count)
+		visible := infos[:0]
+		for _, info := range infos {
+			if !f.fsys.spec.MatchPath(path.Join(f.dir, info.Name()), info.IsDir()) {
+				visible = append(visible, info)
+			}
+		}
+		// With count > 0, an empty result must come with an error, so read
+		// on if all entries read were ignored.
+		if count <= 0 || len(visible) > 0 || err != nil {
+			return visible, err
+		}
+	}
+}
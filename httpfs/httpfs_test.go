@@ -0,0 +1,122 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package httpfs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	pathspec "github.com/shibumi/go-pathspec"
+)
+
+// files are served by the tests.
+var files = map[string]string{
+	".env":                           "SECRET=1",
+	"index.html":                     "<h1>hello</h1>",
+	"app.js":                         "main()",
+	"debug.log":                      "",
+	"node_modules/left-pad/index.js": "leftPad()",
+	"static/.env":                    "SECRET=2",
+	"static/style.css":               "body {}",
+}
+
+// checkServer requests paths from a file server for fsys.
+func checkServer(t *testing.T, fsys http.FileSystem) {
+	t.Helper()
+	srv := httptest.NewServer(http.FileServer(fsys))
+	defer srv.Close()
+
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{"/app.js", http.StatusOK},
+		{"/static/style.css", http.StatusOK},
+		{"/.env", http.StatusNotFound},
+		{"/static/.env", http.StatusNotFound},
+		{"/debug.log", http.StatusNotFound},
+		{"/node_modules/", http.StatusNotFound},
+		{"/node_modules/left-pad/index.js", http.StatusNotFound},
+		{"/static/../.env", http.StatusNotFound},
+		{"/missing", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		resp, err := http.Get(srv.URL + c.path)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("GET '%s' returned '%d', want '%d'", c.path, resp.StatusCode, c.status)
+		}
+	}
+
+	f, err := fsys.Open("/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	defer f.Close()
+	infos, err := f.Readdir(-1)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	if got, want := strings.Join(names, ","), "app.js,index.html,static"; got != want {
+		t.Errorf("Readdir('/') returned '%s', want '%s'", got, want)
+	}
+}
+
+func TestNew(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range files {
+		name = filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+	}
+	spec, err := pathspec.FromLines(".env", "*.log", "node_modules/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	checkServer(t, New(http.Dir(root), spec))
+}
+
+func TestFromFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	spec, err := pathspec.FromLines(".env", "*.log", "node_modules/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	checkServer(t, FromFS(fsys, spec))
+}
@@ -0,0 +1,72 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+// IncludeSpec is a PathSpec with inverted semantics: its patterns select the
+// paths to keep, and negated patterns exclude paths again. Paths no pattern
+// matches are not included. This is the model of include lists like the
+// "files" list of a package manifest.
+type IncludeSpec struct {
+	spec *PathSpec
+}
+
+// NewIncludeSpec compiles an IncludeSpec from gitignore lines. Blank lines
+// and comments are skipped.
+func NewIncludeSpec(lines ...string) (*IncludeSpec, error) {
+	ps, err := FromLines(lines...)
+	if err != nil {
+		return nil, err
+	}
+	return ps.Invert(), nil
+}
+
+// Invert returns an IncludeSpec with the patterns of the PathSpec, so a path
+// it would ignore is included instead.
+func (ps *PathSpec) Invert() *IncludeSpec {
+	return &IncludeSpec{spec: ps}
+}
+
+// Spec returns the underlying PathSpec with regular ignore semantics.
+func (is *IncludeSpec) Spec() *PathSpec {
+	return is.spec
+}
+
+// Match reports whether name is included: the last pattern matching it is
+// not a negation. Directories are denoted by a trailing slash.
+func (is *IncludeSpec) Match(name string) bool {
+	return is.Decide(name) == Included
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (is *IncludeSpec) MatchPath(name string, isDir bool) bool {
+	return is.Match(dirName(name, isDir))
+}
+
+// Decide returns Included if the last pattern matching name is not a
+// negation, Ignored if it is one and Unmatched if no pattern matches.
+func (is *IncludeSpec) Decide(name string) Decision {
+	switch is.spec.MatchState(name) {
+	case StateIgnored:
+		return Included
+	case StateIncluded:
+		return Ignored
+	default:
+		return Unmatched
+	}
+}
@@ -0,0 +1,56 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestIncludeSpec(t *testing.T) {
+	is, err := NewIncludeSpec("/src/", "*.md", "!src/**/*_test.go", "!CHANGELOG.md")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		want     bool
+		decision Decision
+	}{
+		{"src/main.go", true, Included},
+		{"src/main_test.go", false, Ignored},
+		{"README.md", true, Included},
+		{"CHANGELOG.md", false, Ignored},
+		{"go.mod", false, Unmatched},
+	}
+	for _, test := range tests {
+		if got := is.Match(test.name); got != test.want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+		if got := is.Decide(test.name); got != test.decision {
+			t.Errorf("Decide('%s') returned '%v', want '%v'", test.name, got, test.decision)
+		}
+	}
+
+	if !is.MatchPath("src", true) {
+		t.Errorf("MatchPath('src', true) returned 'false', want 'true'")
+	}
+	if !is.Spec().Match("src/main.go") {
+		t.Errorf("Spec().Match('src/main.go') returned 'false', want 'true'")
+	}
+}
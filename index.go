@@ -0,0 +1,157 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"sort"
+	"strings"
+)
+
+// patternIndex buckets the patterns of a PathSpec by literal path segments,
+// so only patterns which can possibly match a path need to be evaluated:
+//
+// A pattern without wildcards, like "/docs/index.md" or "node_modules", is
+// looked up by the whole path if it is anchored, or by every suffix of the
+// path starting at a path segment otherwise.
+//
+// An anchored pattern with a literal first segment, like "/vendor/*.go",
+// can only match paths starting with that segment.
+//
+// An unanchored pattern, which does not end with a slash and has a literal
+// last segment, like "*.d/conf" or "docs/**/index.md", can only match paths
+// ending with that segment.
+//
+// All other patterns are evaluated for every path.
+type patternIndex struct {
+	always []int
+	prefix map[string][]int
+	base   map[string][]int
+	exact  map[string][]int
+	suffix map[string][]int
+}
+
+// newPatternIndex indexes patterns by their position.
+func newPatternIndex(patterns []*Pattern) *patternIndex {
+	ix := &patternIndex{
+		prefix: make(map[string][]int),
+		base:   make(map[string][]int),
+		exact:  make(map[string][]int),
+		suffix: make(map[string][]int),
+	}
+	for i, p := range patterns {
+		ix.update(p, func(list []int) []int { return append(list, i) })
+	}
+	return ix
+}
+
+// insert adds the pattern p at position i to the index.
+func (ix *patternIndex) insert(i int, p *Pattern) {
+	ix.update(p, func(list []int) []int {
+		k := sort.SearchInts(list, i)
+		if k < len(list) && list[k] == i {
+			return list
+		}
+		list = append(list, 0)
+		copy(list[k+1:], list[k:])
+		list[k] = i
+		return list
+	})
+}
+
+// remove removes the pattern p at position i from the index.
+func (ix *patternIndex) remove(i int, p *Pattern) {
+	ix.update(p, func(list []int) []int {
+		k := sort.SearchInts(list, i)
+		if k == len(list) || list[k] != i {
+			return list
+		}
+		return append(list[:k], list[k+1:]...)
+	})
+}
+
+// update replaces the list of positions of the bucket p belongs to by the
+// result of fn. Empty lists are removed from the maps.
+func (ix *patternIndex) update(p *Pattern, fn func([]int) []int) {
+	set := func(m map[string][]int, key string) {
+		if list := fn(m[key]); len(list) > 0 {
+			m[key] = list
+		} else {
+			delete(m, key)
+		}
+	}
+	switch {
+	case p.literal != "" && p.anchored:
+		set(ix.exact, p.literal)
+	case p.literal != "":
+		set(ix.suffix, p.literal)
+	case p.prefix != "":
+		set(ix.prefix, p.prefix)
+	case p.base != "":
+		set(ix.base, p.base)
+	default:
+		ix.always = fn(ix.always)
+	}
+}
+
+// candidates appends the positions of the patterns which can possibly match
+// the slash-separated path name to buf, in ascending order.
+func (ix *patternIndex) candidates(name string, buf []int) []int {
+	trimmed := strings.TrimSuffix(name, "/")
+	first := trimmed
+	if i := strings.IndexByte(trimmed, '/'); i >= 0 {
+		first = trimmed[:i]
+	}
+	last := trimmed[strings.LastIndexByte(trimmed, '/')+1:]
+
+	var stack [8][]int
+	lists := append(stack[:0], ix.always, ix.prefix[first], ix.base[last])
+	if len(ix.exact) > 0 {
+		lists = append(lists, ix.exact[trimmed])
+	}
+	if len(ix.suffix) > 0 {
+		lists = append(lists, ix.suffix[trimmed])
+		for i := strings.IndexByte(trimmed, '/'); i >= 0; i = nextSlash(trimmed, i) {
+			lists = append(lists, ix.suffix[trimmed[i+1:]])
+		}
+	}
+	return mergeSorted(buf, lists)
+}
+
+// mergeSorted appends the union of the ascending lists to buf, in ascending
+// order. The lists must be disjoint.
+func mergeSorted(buf []int, lists [][]int) []int {
+	for {
+		min := -1
+		for k, list := range lists {
+			if len(list) > 0 && (min < 0 || list[0] < lists[min][0]) {
+				min = k
+			}
+		}
+		if min < 0 {
+			return buf
+		}
+		buf = append(buf, lists[min][0])
+		lists[min] = lists[min][1:]
+	}
+}
+
+// isLiteral reports whether the pattern segment contains no wildcards or
+// escapes, so it only matches itself.
+func isLiteral(seg string) bool {
+	return seg != "" && !strings.ContainsAny(seg, "*?[\\")
+}
@@ -0,0 +1,111 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPatternIndex(t *testing.T) {
+	lines := []string{"node_modules", "/vendor/", "/build/*.o", "docs/**/index.md", "*.log", "!/vendor/keep", "!important.log", "foo/", "\\#literal", "/a[bc]/d", "**/tmp"}
+	names := []string{
+		"node_modules", "node_modules/", "src/node_modules/", "node_modules/x.js",
+		"vendor/", "vendor/a.go", "vendor/keep", "src/vendor/a.go",
+		"build/a.o", "build/sub/a.o", "src/build/a.o",
+		"docs/index.md", "docs/a/b/index.md", "src/docs/index.md",
+		"a.log", "important.log", "src/important.log",
+		"foo/", "foo", "a/foo/b", "#literal", "ab/d", "ac/d/", "tmp", "a/tmp/",
+	}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	linear := &PathSpec{patterns: ps.patterns}
+
+	for _, name := range names {
+		if got, want := ps.MatchState(name), linear.MatchState(name); got != want {
+			t.Errorf("MatchState('%s', %s) with index returned '%v', want '%v'", lines, name, got, want)
+		}
+	}
+}
+
+func TestLiteralPatterns(t *testing.T) {
+	lines := []string{"node_modules", "/vendor/a.go", "docs/index.md", "/a", "!keep", "\\#x", "a/**/b", "foo/"}
+	literal := map[string]bool{"node_modules": true, "/vendor/a.go": true, "docs/index.md": true, "/a": true, "!keep": true, "\\#x": true}
+	names := []string{
+		"node_modules", "node_modules/", "a/node_modules", "/node_modules", "xnode_modules", "node_modules/x",
+		"vendor/a.go", "src/vendor/a.go", "docs/index.md", "x/docs/index.md", "xdocs/index.md",
+		"a", "a/", "b/a", "keep", "sub/keep", "#x", "a/b", "foo/",
+	}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for _, p := range ps.Patterns() {
+		if got := p.literal != ""; got != literal[p.String()] {
+			t.Errorf("NewPattern('%s') is literal '%v', want '%v'", p, got, literal[p.String()])
+		}
+		for _, name := range names {
+			if got, want := p.match(name), p.regex.MatchString(name); got != want {
+				t.Errorf("Pattern('%s').match('%s') returned '%v', want '%v'", p, name, got, want)
+			}
+		}
+	}
+
+	linear := &PathSpec{patterns: ps.patterns}
+	for _, name := range names {
+		if got, want := ps.MatchState(name), linear.MatchState(name); got != want {
+			t.Errorf("MatchState('%s', %s) with index returned '%v', want '%v'", lines, name, got, want)
+		}
+	}
+}
+
+func BenchmarkPathSpecMatchLiteralPatterns(b *testing.B) {
+	var lines []string
+	for i := 0; i < 1000; i++ {
+		lines = append(lines, fmt.Sprintf("/src/generated/file%d.go", i), fmt.Sprintf("cache%d", i))
+	}
+	ps, err := FromLines(lines...)
+	if err != nil {
+		b.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ps.Match("src/generated/file500.go")
+	}
+}
+
+func BenchmarkPathSpecMatchManyPatterns(b *testing.B) {
+	var lines []string
+	for i := 0; i < 1000; i++ {
+		lines = append(lines, fmt.Sprintf("/dir%d/", i), fmt.Sprintf("file%d.txt", i))
+	}
+	ps, err := FromLines(lines...)
+	if err != nil {
+		b.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ps.Match("src/pkg/main.go")
+	}
+}
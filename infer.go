@@ -0,0 +1,192 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// inferCandidate is a pattern Infer may propose, and the positions of the
+// ignored paths it matches, or whose parent directories it matches.
+type inferCandidate struct {
+	pattern *Pattern
+	// rank orders candidates covering the same number of paths: directory
+	// patterns first, since walks can prune them, then extensions, then
+	// literal paths.
+	rank   int
+	covers []int
+}
+
+// Infer proposes a small set of gitignore patterns which ignore all of the
+// ignored paths, but none of the kept ones, for migrating explicit lists of
+// paths to ignore files. Directories are denoted by a trailing slash. The
+// patterns are chosen greedily from the directories holding ignored paths,
+// like "node_modules/" or "/build/", their extensions, like "*.log", and the
+// paths themselves, preferring patterns which cover the most paths. The
+// result holds no negations, and decides like git does, see MatchGit: a kept
+// path must not lie in an ignored directory.
+//
+// Paths which cannot be ignored without ignoring a kept path, like a
+// directory holding a kept file, make Infer fail with an error wrapping
+// ErrConflictingPaths.
+func Infer(ignored, kept []string) (*PathSpec, error) {
+	// keptForms holds the kept paths and all of their parent directories,
+	// which no proposed pattern may match.
+	var keptForms []string
+	for _, name := range kept {
+		keptForms = append(keptForms, pathForms(inferPath(name))...)
+	}
+	forms := make([][]string, len(ignored))
+	candidates := make(map[string]*inferCandidate)
+	for i, name := range ignored {
+		name = inferPath(name)
+		forms[i] = pathForms(name)
+		for text, rank := range inferCandidates(name) {
+			if _, ok := candidates[text]; ok {
+				continue
+			}
+			if p, err := NewPattern(text); err == nil {
+				candidates[text] = &inferCandidate{pattern: p, rank: rank}
+			}
+		}
+	}
+
+	var valid []*inferCandidate
+	for _, c := range candidates {
+		if matchesAny(c.pattern, keptForms) {
+			continue
+		}
+		for i := range ignored {
+			if matchesAny(c.pattern, forms[i]) {
+				c.covers = append(c.covers, i)
+			}
+		}
+		valid = append(valid, c)
+	}
+	covered := make([]bool, len(ignored))
+
+	var patterns []*Pattern
+	for {
+		var best *inferCandidate
+		bestCount := 0
+		for _, c := range valid {
+			count := 0
+			for _, i := range c.covers {
+				if !covered[i] {
+					count++
+				}
+			}
+			if count == 0 {
+				continue
+			}
+			if best == nil || count > bestCount ||
+				count == bestCount && (c.rank < best.rank ||
+					c.rank == best.rank && c.pattern.text < best.pattern.text) {
+				best, bestCount = c, count
+			}
+		}
+		if best == nil {
+			break
+		}
+		patterns = append(patterns, best.pattern)
+		for _, i := range best.covers {
+			covered[i] = true
+		}
+	}
+	for i, ok := range covered {
+		if !ok {
+			return nil, fmt.Errorf("cannot ignore %s: %w", ignored[i], ErrConflictingPaths)
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		return patterns[i].text < patterns[j].text
+	})
+	return NewPathSpec(patterns...), nil
+}
+
+// inferPath returns name as a slash-separated path relative to the root,
+// keeping a trailing slash.
+func inferPath(name string) string {
+	name = filepath.ToSlash(name)
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	return dirName(name, isDir)
+}
+
+// pathForms returns the slash-separated path name and its parent
+// directories, with trailing slashes.
+func pathForms(name string) []string {
+	forms := []string{name}
+	trimmed := strings.TrimSuffix(name, "/")
+	for i := strings.IndexByte(trimmed, '/'); i >= 0; i = nextSlash(trimmed, i) {
+		forms = append(forms, name[:i+1])
+	}
+	return forms
+}
+
+// inferCandidates returns the texts of the patterns Infer considers for the
+// ignored path name, and their ranks.
+func inferCandidates(name string) map[string]int {
+	candidates := make(map[string]int)
+	trimmed := strings.TrimSuffix(name, "/")
+	if trimmed == "" {
+		return candidates
+	}
+	segs := strings.Split(trimmed, "/")
+	dirs := len(segs) - 1
+	if strings.HasSuffix(name, "/") {
+		dirs++
+	}
+	for k := 1; k <= dirs; k++ {
+		candidates[escapeName(segs[k-1])+"/"] = 0
+		candidates["/"+escapeConeDir(strings.Join(segs[:k], "/"))+"/"] = 1
+	}
+	base := segs[len(segs)-1]
+	if ext := path.Ext(base); !strings.HasSuffix(name, "/") && ext != "" && ext != base {
+		candidates["*"+escapeConeDir(ext)] = 2
+	}
+	candidates["/"+escapeConeDir(trimmed)] = 3
+	return candidates
+}
+
+// escapeName escapes the path segment name for use as an unanchored
+// pattern: wildcards, a leading "#" or "!" and a trailing space.
+func escapeName(name string) string {
+	name = escapeConeDir(name)
+	if strings.HasPrefix(name, "#") || strings.HasPrefix(name, "!") {
+		name = "\\" + name
+	}
+	if strings.HasSuffix(name, " ") {
+		name = name[:len(name)-1] + "\\ "
+	}
+	return name
+}
+
+// matchesAny reports whether p matches any of names.
+func matchesAny(p *Pattern, names []string) bool {
+	for _, name := range names {
+		if p.match(name) {
+			return true
+		}
+	}
+	return false
+}
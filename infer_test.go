@@ -0,0 +1,97 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	cases := []struct {
+		ignored, kept []string
+		want          []string
+	}{
+		{
+			ignored: []string{"debug.log", "logs/error.log", "src/trace.log"},
+			kept:    []string{"main.go", "src/app.go"},
+			want:    []string{"*.log"},
+		},
+		{
+			ignored: []string{"node_modules/a/index.js", "web/node_modules/b/index.js", "build/out", "build/tmp/x"},
+			kept:    []string{"index.js", "web/index.js", "docs/build.md"},
+			want:    []string{"build/", "node_modules/"},
+		},
+		{
+			ignored: []string{"build/out", "build/tmp/x"},
+			kept:    []string{"src/build/keep.go"},
+			want:    []string{"/build/"},
+		},
+		{
+			ignored: []string{"a.log", "b.log"},
+			kept:    []string{"keep.log"},
+			want:    []string{"/a.log", "/b.log"},
+		},
+		{
+			ignored: []string{"#notes", "data[1].csv"},
+			kept:    []string{"data.csv"},
+			want:    []string{"/#notes", "/data\\[1].csv"},
+		},
+		{
+			ignored: []string{"dist/"},
+			kept:    nil,
+			want:    []string{"dist/"},
+		},
+		{ignored: nil, kept: []string{"main.go"}, want: nil},
+	}
+	for _, c := range cases {
+		ps, err := Infer(c.ignored, c.kept)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		var got []string
+		for _, p := range ps.Patterns() {
+			got = append(got, p.String())
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Infer('%v', '%v') returned '%v', want '%v'", c.ignored, c.kept, got, c.want)
+		}
+		for _, name := range c.ignored {
+			if !ps.MatchGit(name) {
+				t.Errorf("Infer('%v', '%v') does not ignore '%s'", c.ignored, c.kept, name)
+			}
+		}
+		for _, name := range c.kept {
+			if ps.MatchGit(name) {
+				t.Errorf("Infer('%v', '%v') ignores '%s'", c.ignored, c.kept, name)
+			}
+		}
+	}
+}
+
+func TestInferConflict(t *testing.T) {
+	for _, c := range []struct{ ignored, kept []string }{
+		{[]string{"a.txt"}, []string{"a.txt"}},
+		{[]string{"build/"}, []string{"build/keep"}},
+	} {
+		if _, err := Infer(c.ignored, c.kept); !errors.Is(err, ErrConflictingPaths) {
+			t.Errorf("Infer('%v', '%v') returned error '%v', want '%v'", c.ignored, c.kept, err, ErrConflictingPaths)
+		}
+	}
+}
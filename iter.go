@@ -0,0 +1,70 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.23
+
+package pathspec
+
+import (
+	"io/fs"
+	"iter"
+)
+
+// Included returns an iterator over the paths of the files and directories
+// of fsys which are not ignored by the PathSpec, in lexical order. Ignored
+// directories are pruned. Errors reading fsys end the iteration; use Walk to
+// handle them.
+func (ps *PathSpec) Included(fsys fs.FS) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		ps.Walk(fsys, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if name == "." {
+				return nil
+			}
+			if !yield(name) {
+				return fs.SkipAll
+			}
+			return nil
+		})
+	}
+}
+
+// Ignored returns an iterator over the paths of the files and directories of
+// fsys which are ignored by the PathSpec, in lexical order. The contents of
+// ignored directories are not reported separately, much like
+// "git status --ignored" does. Errors reading fsys end the iteration.
+func (ps *PathSpec) Ignored(fsys fs.FS) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if name == "." || !ps.MatchEntry(name, d) {
+				return nil
+			}
+			if !yield(name) {
+				return fs.SkipAll
+			}
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		})
+	}
+}
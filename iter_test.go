@@ -0,0 +1,66 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.23
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPathSpecIterators(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":        {},
+		"debug.log":      {},
+		"build/out/main": {},
+		"src/app.go":     {},
+		"src/app.log":    {},
+		"src/build/x.go": {},
+	}
+	lines := []string{"*.log", "/build/"}
+	wantIncluded := []string{"main.go", "src", "src/app.go", "src/build", "src/build/x.go"}
+	wantIgnored := []string{"build", "debug.log", "src/app.log"}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	var included []string
+	for name := range ps.Included(fsys) {
+		included = append(included, name)
+	}
+	if strings.Join(included, ",") != strings.Join(wantIncluded, ",") {
+		t.Errorf("Included('%s') returned '%s', want '%s'", lines, included, wantIncluded)
+	}
+
+	var ignored []string
+	for name := range ps.Ignored(fsys) {
+		ignored = append(ignored, name)
+	}
+	if strings.Join(ignored, ",") != strings.Join(wantIgnored, ",") {
+		t.Errorf("Ignored('%s') returned '%s', want '%s'", lines, ignored, wantIgnored)
+	}
+
+	// Stopping early must not panic.
+	for range ps.Included(fsys) {
+		break
+	}
+}
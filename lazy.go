@@ -0,0 +1,64 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"sync"
+)
+
+// WithLazyCompile defers compiling the regular expressions of gitignore
+// patterns until they are first matched, which cuts the time to read ignore
+// files for tools reading many of them but matching few paths. The patterns
+// are still parsed right away, so invalid patterns fail to compile as usual.
+// Patterns of other syntaxes are compiled right away.
+func WithLazyCompile() Option {
+	return func(o *options) {
+		o.lazyCompile = true
+	}
+}
+
+// lazyRegex is a regular expression compiled on first use. It is safe for
+// concurrent use.
+type lazyRegex struct {
+	expr  string
+	once  sync.Once
+	regex *regexp.Regexp
+}
+
+// newLazyRegex returns a lazyRegex for expr. Parsing is much cheaper than
+// compiling and reports the same errors, so compiling later cannot fail.
+func newLazyRegex(expr string) (*lazyRegex, error) {
+	if _, err := syntax.Parse(expr, syntax.Perl); err != nil {
+		return nil, err
+	}
+	return &lazyRegex{expr: expr}, nil
+}
+
+// compiledRegex returns the regular expression of the pattern, compiling it
+// if it was deferred by WithLazyCompile, or nil if it has none.
+func (p *Pattern) compiledRegex() *regexp.Regexp {
+	if p.lazy == nil {
+		return p.regex
+	}
+	p.lazy.once.Do(func() {
+		p.lazy.regex = regexp.MustCompile(p.lazy.expr)
+	})
+	return p.lazy.regex
+}
@@ -0,0 +1,87 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithLazyCompile(t *testing.T) {
+	lines := []string{"*.log", "!keep.log", "/build/", "docs/**/*.tmp"}
+	eager, err := FromLinesWithOptions(lines)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	lazy, err := FromLinesWithOptions(lines, WithLazyCompile(), WithCaseSensitive(false))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for _, p := range lazy.Patterns() {
+		if p.lazy == nil || p.regex != nil {
+			t.Fatalf("Pattern '%s' was compiled eagerly", p)
+		}
+	}
+
+	names := []string{"a.log", "KEEP.LOG", "build/x", "src/build/x", "docs/a/b/x.TMP", "main.go"}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, name := range names {
+				lazy.Match(name)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, name := range names {
+		if got, want := lazy.Match(name), eager.Match(strings.ToLower(name)); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+	if got, want := lazy.Patterns()[0].Regex().String(), "(?i)"+eager.Patterns()[0].Regex().String(); got != want {
+		t.Errorf("Regex() returned '%s', want '%s'", got, want)
+	}
+
+	if _, err := FromLinesWithOptions([]string{"[z-a]"}, WithLazyCompile()); err == nil {
+		t.Errorf("FromLinesWithOptions('[z-a]') returned no error")
+	}
+}
+
+func BenchmarkFromLinesLazyCompile(b *testing.B) {
+	lines := make([]string, 1000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("dir%d/**/*.ext%d", i, i)
+	}
+	for _, lazy := range []bool{false, true} {
+		var opts []Option
+		if lazy {
+			opts = append(opts, WithLazyCompile())
+		}
+		b.Run(fmt.Sprintf("lazy=%t", lazy), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := FromLinesWithOptions(lines, opts...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
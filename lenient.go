@@ -0,0 +1,68 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io"
+)
+
+// FromLinesLenient is like FromLinesWithOptions, but like git, it skips
+// patterns which fail to compile instead of failing, so one bad line does not
+// abort scanning a whole repository. The skipped lines are reported as
+// warnings of kind WarnInvalidPattern, ordered by line. The error is only
+// non-nil if the input exceeds any of the limits of WithLimits, which still
+// fail, with ParseErrors wrapping ErrLimitExceeded.
+func FromLinesLenient(lines []string, opts ...Option) (*PathSpec, []Warning, error) {
+	ps, errs, err := newOptions(opts).fromLines(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range errs {
+		if errors.Is(e, ErrLimitExceeded) {
+			return nil, nil, ParseErrors{e}
+		}
+	}
+	return ps, invalidPatternWarnings(lines, errs), nil
+}
+
+// FromReaderLenient is like FromLinesLenient, but reads the lines from r like
+// FromReaderWithOptions does. The error is non-nil if reading fails, too.
+func FromReaderLenient(r io.Reader, opts ...Option) (*PathSpec, []Warning, error) {
+	o := newOptions(opts)
+	lines, err := scanLines(r, o.rawLines, o.limits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return FromLinesLenient(lines, opts...)
+}
+
+// invalidPatternWarnings converts the errors of the lines which failed to
+// compile into warnings.
+func invalidPatternWarnings(lines []string, errs ParseErrors) []Warning {
+	var warnings []Warning
+	for _, e := range errs {
+		warnings = append(warnings, Warning{
+			Line:    e.Line,
+			Pattern: lines[e.Line-1],
+			Kind:    WarnInvalidPattern,
+			Message: e.Err.Error(),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,55 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFromReaderLenient(t *testing.T) {
+	content := "*.log\n[z-a]\n# comment\nbuild/\n/b[9-0]/\n"
+	ps, warnings, err := FromReaderLenient(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if got := len(ps.Patterns()); got != 2 {
+		t.Errorf("FromReaderLenient() compiled %d patterns, want 2", got)
+	}
+	for name, want := range map[string]bool{"a.log": true, "build/": true, "z": false} {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("FromReaderLenient() returned %d warnings, want 2", len(warnings))
+	}
+	for i, want := range []Warning{{Line: 2, Pattern: "[z-a]"}, {Line: 5, Pattern: "/b[9-0]/"}} {
+		if w := warnings[i]; w.Line != want.Line || w.Pattern != want.Pattern || w.Kind != WarnInvalidPattern || w.Message == "" {
+			t.Errorf("FromReaderLenient() returned warning '%v', want one for line %d", w, want.Line)
+		}
+	}
+
+	for _, limits := range []Limits{{MaxPatterns: 1}, {MaxRegexSize: 20}} {
+		_, _, err = FromLinesLenient([]string{"a", "*a*b*c*d*e*"}, WithLimits(limits))
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("FromLinesLenient(%+v) returned error '%v', want ErrLimitExceeded", limits, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"regexp/syntax"
+)
+
+// Limits restricts the size of the ignore files a PathSpec is compiled from,
+// so a hostile file, e.g. uploaded to a service, fails to compile cleanly
+// instead of consuming unbounded memory or time. Zero fields impose no limit.
+type Limits struct {
+	// MaxLineLength is the maximum length of a line in bytes, without the
+	// line terminator. Readers stop at the first longer line.
+	MaxLineLength int
+	// MaxPatterns is the maximum number of patterns, not counting blank
+	// lines and comments. Readers stop at the first pattern too many.
+	MaxPatterns int
+	// MaxRegexSize is the maximum size of the regular expression of a
+	// single pattern, in instructions of the compiled program. Patterns
+	// like "*a*b*c*" or ones with many brace alternatives compile to large
+	// programs. The regular expression of WithBraceExpansion is checked
+	// before it is compiled.
+	MaxRegexSize int
+	// MaxExpansion is the maximum size in bytes of the alternatives a
+	// single pattern expands to with WithBraceExpansion or WithMinimatch,
+	// counting one byte per alternative for the separator. It is checked
+	// before the braces are expanded, as a short pattern like
+	// "{a,b}{a,b}{a,b}..." doubles in size with every brace.
+	MaxExpansion int
+}
+
+// WithLimits fails to compile input exceeding limits. The errors are
+// ParseErrors wrapping ErrLimitExceeded, reporting the offending line.
+func WithLimits(limits Limits) Option {
+	return func(o *options) {
+		o.limits = limits
+	}
+}
+
+// checkLines checks the length of lines and the number of patterns in them.
+func (l Limits) checkLines(lines []string) error {
+	patterns := 0
+	for i, line := range lines {
+		if err := l.checkLine(line, &patterns); err != nil {
+			return ParseErrors{{Line: i + 1, Err: err}}
+		}
+	}
+	return nil
+}
+
+// checkLine checks the length of line and, if it holds a pattern, increments
+// the number of patterns and checks it.
+func (l Limits) checkLine(line string, patterns *int) error {
+	if l.MaxLineLength > 0 && len(line) > l.MaxLineLength {
+		return l.lineTooLong()
+	}
+	if _, ok := patternFromLine(line); !ok {
+		return nil
+	}
+	*patterns++
+	if l.MaxPatterns > 0 && *patterns > l.MaxPatterns {
+		return fmt.Errorf("more than %d patterns: %w", l.MaxPatterns, ErrLimitExceeded)
+	}
+	return nil
+}
+
+// lineTooLong returns the error for a line longer than MaxLineLength.
+func (l Limits) lineTooLong() error {
+	return fmt.Errorf("line longer than %d bytes: %w", l.MaxLineLength, ErrLimitExceeded)
+}
+
+// checkRegex checks the size of the regular expression of p. Patterns
+// matched by custom Matchers are not checked.
+func (l Limits) checkRegex(p *Pattern) error {
+	if l.MaxRegexSize <= 0 || !p.hasRegex() || p.matcher != nil {
+		return nil
+	}
+	return l.checkExpr(p.text, p.regexString())
+}
+
+// checkExpr checks the size of the regular expression expr of the pattern
+// text, without compiling it to a Regexp.
+func (l Limits) checkExpr(text, expr string) error {
+	if l.MaxRegexSize <= 0 {
+		return nil
+	}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return err
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return err
+	}
+	if size := len(prog.Inst); size > l.MaxRegexSize {
+		return fmt.Errorf("pattern %q compiles to %d instructions, more than %d: %w", text, size, l.MaxRegexSize, ErrLimitExceeded)
+	}
+	return nil
+}
+
+// checkExpansion checks the size of the alternatives pattern expands to by
+// counting them, without expanding them.
+func (l Limits) checkExpansion(pattern string) error {
+	if l.MaxExpansion <= 0 {
+		return nil
+	}
+	count, length := braceExpansionSize(pattern, l.MaxExpansion)
+	if count+length > l.MaxExpansion {
+		return fmt.Errorf("pattern %q expands to more than %d bytes: %w", pattern, l.MaxExpansion, ErrLimitExceeded)
+	}
+	return nil
+}
+
+// braceExpansionSize returns the number of alternatives expandBraces returns
+// for pattern and their total length. Both saturate at max+1, so they do not
+// overflow.
+func braceExpansionSize(pattern string, max int) (count, length int) {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			alternatives, end, ok := splitBraces(pattern, i)
+			if !ok {
+				continue
+			}
+			suffixes, suffixLength := braceExpansionSize(pattern[end+1:], max)
+			for _, alternative := range alternatives {
+				n, l := braceExpansionSize(alternative, max)
+				expanded := saturatingMul(n, suffixes, max)
+				count = saturatingAdd(count, expanded, max)
+				length = saturatingAdd(length, saturatingMul(expanded, i, max), max)
+				length = saturatingAdd(length, saturatingMul(l, suffixes, max), max)
+				length = saturatingAdd(length, saturatingMul(n, suffixLength, max), max)
+			}
+			return count, length
+		}
+	}
+	if len(pattern) > max {
+		return 1, max + 1
+	}
+	return 1, len(pattern)
+}
+
+// saturatingAdd returns a+b, or max+1 if it is larger.
+func saturatingAdd(a, b, max int) int {
+	if a > max-b {
+		return max + 1
+	}
+	return a + b
+}
+
+// saturatingMul returns a*b, or max+1 if it is larger.
+func saturatingMul(a, b, max int) int {
+	if a != 0 && b > max/a {
+		return max + 1
+	}
+	return a * b
+}
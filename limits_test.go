@@ -0,0 +1,106 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		limits Limits
+		line   int
+	}{
+		{"line length", "*.o\n" + strings.Repeat("a", 100) + "\n", Limits{MaxLineLength: 50}, 2},
+		{"huge line", "*.o\n" + strings.Repeat("a", 200000) + "\n", Limits{MaxLineLength: 1000}, 2},
+		{"pattern count", "# comment\na\n\nb\nc\n", Limits{MaxPatterns: 2}, 5},
+		{"regex size", "*.o\n*a*b*c*d*e*f*g*\n", Limits{MaxRegexSize: 20}, 2},
+	}
+	for _, test := range tests {
+		_, err := FromReaderWithOptions(strings.NewReader(test.input), WithLimits(test.limits))
+		var perr *ParseError
+		if !errors.Is(err, ErrLimitExceeded) || !errors.As(err, &perr) || perr.Line != test.line {
+			t.Errorf("%s: FromReaderWithOptions() returned '%v', want a limit exceeded on line %d", test.name, err, test.line)
+		}
+		lines := strings.Split(strings.TrimSuffix(test.input, "\n"), "\n")
+		if _, err := FromLinesWithOptions(lines, WithLimits(test.limits)); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: FromLinesWithOptions() returned '%v', want a limit exceeded", test.name, err)
+		}
+	}
+
+	limits := Limits{MaxLineLength: 10, MaxPatterns: 3, MaxRegexSize: 100}
+	if _, err := FromReaderWithOptions(strings.NewReader("\ufeff*.o\r\n# a long comment\r\n*.a\r\n"), WithLimits(limits)); err == nil {
+		t.Errorf("FromReaderWithOptions() accepted a comment longer than the limit")
+	}
+	if _, err := FromReaderWithOptions(strings.NewReader("\ufeff*.o\r\n# comment\r\n*.a\r\nbuild/\r\n"), WithLimits(limits)); err != nil {
+		t.Errorf("FromReaderWithOptions() returned an unexpected error: %s", err)
+	}
+}
+
+func TestWithLimitsBraceExpansion(t *testing.T) {
+	tests := []struct {
+		pattern string
+		opt     Option
+	}{
+		{strings.Repeat("{a,b}", 16), WithBraceExpansion()},
+		{strings.Repeat("{,}", 24), WithBraceExpansion()},
+		{"{{a,b},{c,{d,e}}}" + strings.Repeat("{x,y,z}", 12), WithBraceExpansion()},
+		{"{1..999}{1..999}", WithMinimatch(MinimatchOptions{})},
+	}
+	for _, test := range tests {
+		start := time.Now()
+		_, err := FromLinesWithOptions([]string{test.pattern}, test.opt, WithLimits(Limits{MaxLineLength: 100, MaxExpansion: 1000}))
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("FromLinesWithOptions('%s') returned '%v', want ErrLimitExceeded", test.pattern, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("FromLinesWithOptions('%s') took %s to fail", test.pattern, elapsed)
+		}
+	}
+
+	if _, err := FromLinesWithOptions([]string{"*.{js,ts,jsx}", "{a,b}/{c,d}/"}, WithBraceExpansion(), WithLimits(Limits{MaxExpansion: 50})); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if _, err := FromLinesWithOptions([]string{"*.{js,ts,jsx}"}, WithBraceExpansion(), WithLimits(Limits{MaxRegexSize: 10})); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("FromLinesWithOptions() returned '%v', want ErrLimitExceeded", err)
+	}
+}
+
+func TestBraceExpansionSize(t *testing.T) {
+	for _, pattern := range []string{
+		"a", "{a}", "*.{js,ts,jsx}", "{a,b}{c,d,e}", "x{a,{b,c}d}y{,z}", `\{a,b}{c,d}`, "{a,b", "{,}{,}",
+	} {
+		alternatives := expandBraces(pattern)
+		length := 0
+		for _, a := range alternatives {
+			length += len(a)
+		}
+		count, l := braceExpansionSize(pattern, 1000)
+		if count != len(alternatives) || l != length {
+			t.Errorf("braceExpansionSize('%s') returned %d, %d, want %d, %d", pattern, count, l, len(alternatives), length)
+		}
+	}
+	if count, length := braceExpansionSize(strings.Repeat("{a,b}", 64), 1000); count != 1001 || length != 1001 {
+		t.Errorf("braceExpansionSize() returned %d, %d, want both saturated at 1001", count, length)
+	}
+}
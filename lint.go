@@ -0,0 +1,207 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WarningKind classifies a Warning.
+type WarningKind int
+
+const (
+	// WarnInvalidPattern means the line could not be compiled at all.
+	WarnInvalidPattern WarningKind = iota
+	// WarnDoubleAsterisk means a "**" which is not a whole path segment,
+	// like in "a**b". Git treats it like a single "*".
+	WarnDoubleAsterisk
+	// WarnTrailingWhitespace means unescaped trailing whitespace, which
+	// is ignored. Escape it with a backslash if it is intended.
+	WarnTrailingWhitespace
+	// WarnShadowed means a pattern which has no effect, because an
+	// earlier pattern already decides the same way about its paths.
+	WarnShadowed
+	// WarnIneffectiveNegation means a negation which can never re-include
+	// anything.
+	WarnIneffectiveNegation
+)
+
+// String returns a short name of the kind.
+func (k WarningKind) String() string {
+	switch k {
+	case WarnInvalidPattern:
+		return "invalid-pattern"
+	case WarnDoubleAsterisk:
+		return "double-asterisk"
+	case WarnTrailingWhitespace:
+		return "trailing-whitespace"
+	case WarnShadowed:
+		return "shadowed"
+	case WarnIneffectiveNegation:
+		return "ineffective-negation"
+	default:
+		return fmt.Sprintf("WarningKind(%d)", int(k))
+	}
+}
+
+// Warning describes a suspicious gitignore line.
+type Warning struct {
+	// Line is the line number, starting at 1.
+	Line int
+	// Pattern is the line as written.
+	Pattern string
+	// Kind classifies the warning.
+	Kind WarningKind
+	// Message describes the problem.
+	Message string
+}
+
+// String formats the warning as "line <n>: <pattern>: <message>".
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s: %s", w.Line, w.Pattern, w.Message)
+}
+
+// Lint checks gitignore lines for suspicious patterns: lines which cannot be
+// compiled, "**" which is not a whole path segment, unescaped trailing
+// whitespace, patterns shadowed by earlier ones and negations which can never
+// take effect. The warnings are ordered by line.
+func Lint(lines []string) []Warning {
+	var warnings []Warning
+	var patterns []*Pattern
+	for i, line := range lines {
+		pattern, ok := patternFromLine(line)
+		if !ok {
+			continue
+		}
+		warn := func(kind WarningKind, format string, args ...interface{}) {
+			warnings = append(warnings, Warning{
+				Line:    i + 1,
+				Pattern: line,
+				Kind:    kind,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		if trimmed := strings.TrimRight(line, " "); trimmed != line && !strings.HasSuffix(trimmed, "\\") {
+			warn(WarnTrailingWhitespace, "trailing whitespace is ignored, escape it with a backslash if it is intended")
+		}
+		p, err := NewPattern(pattern)
+		if err != nil {
+			warn(WarnInvalidPattern, "%s", err)
+			continue
+		}
+		p.line = i + 1
+		segments, _, _ := NormalizePattern(pattern)
+		for _, seg := range segments {
+			if seg != "**" && strings.Contains(seg, "**") {
+				warn(WarnDoubleAsterisk, "%q is not a whole path segment and matches like a single \"*\"", seg)
+				break
+			}
+		}
+		if earlier := shadowingPattern(patterns, p, segments); earlier != nil {
+			warn(WarnShadowed, "has no effect, the pattern on line %d already matches the same paths", earlier.line)
+		} else if p.negate {
+			if msg := ineffectiveNegation(patterns, segments); msg != "" {
+				warn(WarnIneffectiveNegation, "%s", msg)
+			}
+		}
+		patterns = append(patterns, p)
+	}
+	return warnings
+}
+
+// shadowingPattern returns the earlier pattern which makes p redundant, or
+// nil. That is an earlier pattern with the same negation, which matches the
+// same paths as p, provided no pattern of the opposite negation matches any
+// of them in between. Besides exact duplicates, this detects literal anchored
+// patterns like "/build/main.o" below an earlier "build/".
+func shadowingPattern(earlier []*Pattern, p *Pattern, segments []string) *Pattern {
+	var probes []string
+	if segments[0] != "**" && !p.dir && allLiteral(segments) {
+		name := strings.Join(segments, "/")
+		probes = []string{name, name + "/"}
+	}
+	for i := len(earlier) - 1; i >= 0; i-- {
+		q := earlier[i]
+		if q.negate != p.negate {
+			// A pattern of the opposite negation in between may
+			// change the decision, unless it provably does not
+			// match any path of p.
+			if probes == nil || q.match(probes[0]) || q.match(probes[1]) {
+				return nil
+			}
+			continue
+		}
+		if q.regexString() == p.regexString() {
+			return q
+		}
+		if probes != nil && q.match(probes[0]) && q.match(probes[1]) {
+			return q
+		}
+	}
+	return nil
+}
+
+// ineffectiveNegation explains why the negation with the given normalized
+// segments can never re-include a path, or returns an empty string. This is
+// the case if no earlier pattern ignores anything, or if a parent directory
+// of all paths it matches is ignored, since git does not look into ignored
+// directories.
+func ineffectiveNegation(earlier []*Pattern, segments []string) string {
+	ignores := false
+	for _, q := range earlier {
+		ignores = ignores || !q.negate
+	}
+	if !ignores {
+		return "has no effect, no earlier pattern ignores anything"
+	}
+
+	// Collect the literal leading directories of the pattern. For an
+	// unanchored pattern they must be ignored at any depth, which is
+	// approximated by checking them in the root and one level below.
+	anchored := segments[0] != "**"
+	if !anchored {
+		segments = segments[1:]
+	}
+	ps := NewPathSpec(earlier...)
+	dir := ""
+	for _, seg := range segments[:len(segments)-1] {
+		if !isLiteral(seg) || seg == "**" {
+			break
+		}
+		dir += seg + "/"
+		if ps.MatchState(dir) != StateIgnored {
+			continue
+		}
+		if anchored || ps.MatchState("x/"+dir) == StateIgnored {
+			return fmt.Sprintf("cannot re-include anything, the parent directory %q is ignored", dir)
+		}
+	}
+	return ""
+}
+
+// allLiteral reports whether all segments are literal.
+func allLiteral(segments []string) bool {
+	for _, seg := range segments {
+		if !isLiteral(seg) {
+			return false
+		}
+	}
+	return true
+}
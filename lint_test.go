@@ -0,0 +1,67 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	lines := []string{
+		"!early",        // 1: nothing ignored yet
+		"*.log",         // 2
+		"a**b",          // 3: double asterisk
+		"foo ",          // 4: trailing whitespace
+		"bar\\ ",        // 5: escaped trailing whitespace
+		"*.log",         // 6: duplicate of 2
+		"build/",        // 7
+		"/build/main.o", // 8: below build/
+		"!build/keep",   // 9: parent ignored
+		"!",             // 10: invalid
+		"!*.log",        // 11
+		"*.log",         // 12: not shadowed, negation in between
+		"# comment",     // 13
+		"/dist/app.js",  // 14
+		"!/dist/app.js", // 15
+		"/dist/",        // 16
+		"/dist/app.js",  // 17: shadowed by 16? no, 15 is in between but before 16
+	}
+	want := []Warning{
+		{Line: 1, Kind: WarnIneffectiveNegation},
+		{Line: 3, Kind: WarnDoubleAsterisk},
+		{Line: 4, Kind: WarnTrailingWhitespace},
+		{Line: 6, Kind: WarnShadowed},
+		{Line: 8, Kind: WarnShadowed},
+		{Line: 9, Kind: WarnIneffectiveNegation},
+		{Line: 10, Kind: WarnInvalidPattern},
+		{Line: 17, Kind: WarnShadowed},
+	}
+
+	warnings := Lint(lines)
+	if len(warnings) != len(want) {
+		t.Fatalf("Lint() returned %d warnings %v, want %d", len(warnings), warnings, len(want))
+	}
+	for i, w := range warnings {
+		if w.Line != want[i].Line || w.Kind != want[i].Kind {
+			t.Errorf("Lint()[%d] returned '%s' (%v), want line %d (%v)", i, w, w.Kind, want[i].Line, want[i].Kind)
+		}
+		if w.Pattern != lines[w.Line-1] || w.Message == "" {
+			t.Errorf("Lint()[%d] returned '%+v' without pattern or message", i, w)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// patternJSON is the JSON representation of a Pattern.
+type patternJSON struct {
+	Pattern string `json:"pattern"`
+	Syntax  string `json:"syntax,omitempty"`
+	Negate  bool   `json:"negate"`
+	Source  string `json:"source,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// MarshalText implements encoding.TextMarshaler. The text form of a pattern
+// is the pattern as written in a gitignore file, so only patterns of the
+// "gitwildmatch" syntax can be marshaled as text.
+func (p *Pattern) MarshalText() ([]byte, error) {
+	if p.syntax != "gitwildmatch" {
+		return nil, fmt.Errorf("pattern %q of syntax %q cannot be marshaled as text", p.text, p.syntax)
+	}
+	return []byte(p.text), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It compiles text as a
+// gitignore pattern.
+func (p *Pattern) UnmarshalText(text []byte) error {
+	q, err := NewPattern(string(text))
+	if err != nil {
+		return err
+	}
+	*p = *q
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Besides the pattern text, the JSON
+// form carries the syntax and source information, so patterns of every
+// registered syntax round-trip losslessly.
+func (p *Pattern) MarshalJSON() ([]byte, error) {
+	return json.Marshal(patternJSON{
+		Pattern: p.text,
+		Syntax:  p.syntax,
+		Negate:  p.negate,
+		Source:  p.source,
+		Line:    p.line,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The pattern is compiled with its
+// syntax, which defaults to "gitwildmatch". The negate field is informational
+// only; negation is taken from the pattern text.
+func (p *Pattern) UnmarshalJSON(data []byte) error {
+	var v patternJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Syntax == "" {
+		v.Syntax = "gitwildmatch"
+	}
+	q, err := NewPatternWithSyntax(v.Syntax, v.Pattern)
+	if err != nil {
+		return err
+	}
+	q.source = v.Source
+	q.line = v.Line
+	*p = *q
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The text form of a PathSpec
+// is a gitignore file with one pattern per line, so only PathSpecs of the
+// "gitwildmatch" syntax can be marshaled as text.
+func (ps *PathSpec) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, p := range ps.patterns {
+		text, err := p.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(text)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It compiles text as a
+// gitignore file, like FromReader.
+func (ps *PathSpec) UnmarshalText(text []byte) error {
+	q, err := FromReader(bytes.NewReader(text))
+	if err != nil {
+		return err
+	}
+	*ps = *q
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The JSON form of a PathSpec is an
+// array of its patterns.
+func (ps *PathSpec) MarshalJSON() ([]byte, error) {
+	patterns := ps.patterns
+	if patterns == nil {
+		patterns = []*Pattern{}
+	}
+	return json.Marshal(patterns)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an array of patterns
+// as produced by MarshalJSON, or a string holding a gitignore file.
+func (ps *PathSpec) UnmarshalJSON(data []byte) error {
+	if s := strings.TrimSpace(string(data)); strings.HasPrefix(s, `"`) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return ps.UnmarshalText([]byte(text))
+	}
+	var patterns []*Pattern
+	if err := json.Unmarshal(data, &patterns); err != nil {
+		return err
+	}
+	*ps = *NewPathSpec(patterns...)
+	return nil
+}
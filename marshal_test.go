@@ -0,0 +1,100 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPathSpecMarshalJSON(t *testing.T) {
+	gitignore, err := compileLines(".gitignore", []string{"*.log", "", "!keep.log", "build/"}, NewPattern)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	regex, err := NewPatternWithSyntax("regex", "!^tmp/[0-9]+$")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	docker, err := NewPatternWithSyntax("dockerignore", "/vendor/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps := NewPathSpec(append(gitignore.Patterns(), regex, docker)...)
+
+	data, err := json.Marshal(ps)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	var decoded PathSpec
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if len(decoded.Patterns()) != len(ps.Patterns()) {
+		t.Fatalf("json.Unmarshal(%s) returned %d patterns, want %d", data, len(decoded.Patterns()), len(ps.Patterns()))
+	}
+	for i, p := range decoded.Patterns() {
+		want := ps.Patterns()[i]
+		if !p.Equal(want) || p.Syntax() != want.Syntax() || p.Source() != want.Source() || p.Line() != want.Line() {
+			t.Errorf("json.Unmarshal(%s)[%d] returned '%s' (%s, %s:%d), want '%s' (%s, %s:%d)", data, i, p, p.Syntax(), p.Source(), p.Line(), want, want.Syntax(), want.Source(), want.Line())
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`"*.log\n!keep.log\n"`), &decoded); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !decoded.Match("a.log") || decoded.Match("keep.log") {
+		t.Errorf("json.Unmarshal() of a gitignore string returned a spec with wrong decisions")
+	}
+}
+
+func TestPathSpecMarshalText(t *testing.T) {
+	lines := []string{"*.log", "!keep.log", "\\#notes", "build/"}
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	text, err := ps.MarshalText()
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if want := strings.Join(lines, "\n") + "\n"; string(text) != want {
+		t.Errorf("MarshalText() returned %q, want %q", text, want)
+	}
+
+	var decoded PathSpec
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for i, p := range decoded.Patterns() {
+		if !p.Equal(ps.Patterns()[i]) {
+			t.Errorf("UnmarshalText(%q)[%d] returned '%s', want '%s'", text, i, p, ps.Patterns()[i])
+		}
+	}
+
+	regex, err := NewRegexPattern("^a$")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if _, err := NewPathSpec(regex).MarshalText(); err == nil {
+		t.Errorf("MarshalText() of a regex pattern returned no error, want one")
+	}
+}
@@ -0,0 +1,245 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MinimatchOptions configures the minimatch dialect, like the options of the
+// same name of the JavaScript minimatch library.
+type MinimatchOptions struct {
+	// Dot lets wildcards and "**" match names starting with a dot.
+	// Otherwise, such names are only matched by pattern segments which
+	// start with a literal dot, too.
+	Dot bool
+	// MatchBase matches patterns without slashes against the base name of
+	// a path, like gitignore does.
+	MatchBase bool
+	// NoCase matches case-insensitively. WithCaseSensitive(false) sets it,
+	// too.
+	NoCase bool
+}
+
+// WithMinimatch compiles the patterns like the JavaScript minimatch library
+// does, to evaluate globs written for front-end tool chains, like the "files"
+// of a package.json, without a JavaScript runtime. See NewMinimatchPattern
+// for the syntax. The patterns have the syntax "minimatch".
+func WithMinimatch(opts MinimatchOptions) Option {
+	return func(o *options) {
+		o.minimatch = &opts
+	}
+}
+
+// sequencePattern matches the brace sequences minimatch expands, like "{1..3}"
+// and "{a..c}".
+var sequencePattern = regexp.MustCompile(`\{(-?[0-9]+)\.\.(-?[0-9]+)\}|\{([a-zA-Z])\.\.([a-zA-Z])\}`)
+
+// maxSequenceLength limits the number of alternatives a brace sequence
+// expands to. Longer sequences are taken literally.
+const maxSequenceLength = 1000
+
+// NewMinimatchPattern compiles a glob with the semantics of minimatch:
+//
+// Patterns are matched against the whole path, unless opts.MatchBase is set
+// and they contain no slash, so "*.js" only matches at the root. A leading
+// "./" or "/" is ignored. "*" and "?" do not match slashes, and "**" as a
+// whole path segment matches any number of segments, but at least one at the
+// end of a pattern. Wildcards do not match names starting with a dot, unless
+// opts.Dot is set.
+//
+// Braces like "{a,b}" and sequences like "{1..3}" are expanded, and the
+// extended globs "@(a|b)", "?(a|b)", "*(a|b)", "+(a|b)" and "!(a|b)" are
+// supported, see WithExtglob. A leading "!" not starting an "!(...)" group
+// negates the pattern, and an even number of them cancels out. A trailing
+// slash only matches directories.
+func NewMinimatchPattern(line string, opts MinimatchOptions) (*Pattern, error) {
+	body := line
+	negate := false
+	for strings.HasPrefix(body, "!") && !strings.HasPrefix(body, "!(") {
+		negate = !negate
+		body = body[1:]
+	}
+	if opts.NoCase {
+		body = strings.ToLower(body)
+	}
+	m := &minimatcher{opts: opts}
+	for _, alternative := range expandBraces(expandSequences(body)) {
+		alt, err := newMinimatchAlternative(alternative)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", line, err)
+		}
+		m.alternatives = append(m.alternatives, alt)
+	}
+	return &Pattern{syntax: "minimatch", text: line, negate: negate, matcher: m, dir: strings.HasSuffix(body, "/")}, nil
+}
+
+// expandSequences replaces the sequences of pattern with the equivalent
+// brace alternatives, like "{1..3}" with "{1,2,3}".
+func expandSequences(pattern string) string {
+	return sequencePattern.ReplaceAllStringFunc(pattern, func(seq string) string {
+		sub := sequencePattern.FindStringSubmatch(seq)
+		var from, to int
+		if sub[1] != "" {
+			from, _ = strconv.Atoi(sub[1])
+			to, _ = strconv.Atoi(sub[2])
+		} else {
+			from, to = int(sub[3][0]), int(sub[4][0])
+		}
+		step := 1
+		if to < from {
+			step = -1
+		}
+		if (to-from)*step >= maxSequenceLength {
+			return seq
+		}
+		var items []string
+		for i := from; ; i += step {
+			if sub[1] != "" {
+				items = append(items, strconv.Itoa(i))
+			} else {
+				items = append(items, string(rune(i)))
+			}
+			if i == to {
+				break
+			}
+		}
+		if len(items) == 1 {
+			// Braces without a comma are taken literally.
+			return items[0]
+		}
+		return "{" + strings.Join(items, ",") + "}"
+	})
+}
+
+// minimatchSegment is a path segment of a minimatch pattern. A nil glob is a
+// "**" segment.
+type minimatchSegment struct {
+	glob *extglobSegment
+	// dot is true if the segment starts with a literal dot, so it may
+	// match names starting with a dot.
+	dot bool
+}
+
+// minimatchAlternative is a minimatch pattern after brace expansion.
+type minimatchAlternative struct {
+	segs []minimatchSegment
+	dir  bool
+	base bool
+}
+
+// newMinimatchAlternative compiles a minimatch pattern without braces.
+func newMinimatchAlternative(pattern string) (*minimatchAlternative, error) {
+	for strings.HasPrefix(pattern, "./") {
+		pattern = pattern[2:]
+	}
+	pattern = strings.TrimLeft(pattern, "/")
+	alt := &minimatchAlternative{dir: strings.HasSuffix(pattern, "/")}
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return nil, ErrEmptyPattern
+	}
+	alt.base = !strings.Contains(pattern, "/")
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == "**" {
+			alt.segs = append(alt.segs, minimatchSegment{})
+			continue
+		}
+		glob, err := newExtglobSegment(seg)
+		if err != nil {
+			return nil, err
+		}
+		alt.segs = append(alt.segs, minimatchSegment{glob: glob, dot: strings.HasPrefix(seg, ".")})
+	}
+	return alt, nil
+}
+
+// minimatcher matches a minimatch pattern, which matches if any of its
+// alternatives does.
+type minimatcher struct {
+	alternatives []*minimatchAlternative
+	opts         MinimatchOptions
+}
+
+// Match reports whether one of the alternatives matches name.
+func (m *minimatcher) Match(name string) bool {
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.TrimSuffix(name, "/")
+	if m.opts.NoCase {
+		name = strings.ToLower(name)
+	}
+	parts := strings.Split(name, "/")
+	for _, alt := range m.alternatives {
+		if alt.dir && !isDir {
+			continue
+		}
+		if alt.base && m.opts.MatchBase {
+			if alt.matchParts(parts[len(parts)-1:], m.opts.Dot) {
+				return true
+			}
+		} else if alt.matchParts(parts, m.opts.Dot) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchParts reports whether the segments of the alternative match all path
+// segments parts.
+func (alt *minimatchAlternative) matchParts(parts []string, dot bool) bool {
+	failed := make(map[[2]int]bool)
+	var matchFrom func(i, j int) bool
+	matchFrom = func(i, j int) bool {
+		if i == len(alt.segs) {
+			return j == len(parts)
+		}
+		key := [2]int{i, j}
+		if failed[key] {
+			return false
+		}
+		seg := alt.segs[i]
+		if seg.glob != nil {
+			if j < len(parts) && (dot || seg.dot || !strings.HasPrefix(parts[j], ".")) &&
+				seg.glob.match(parts[j]) && matchFrom(i+1, j+1) {
+				return true
+			}
+		} else {
+			// A trailing "**" matches at least one segment, others
+			// match zero or more. None of them matches names
+			// starting with a dot, unless dot is set.
+			min := 0
+			if i == len(alt.segs)-1 {
+				min = 1
+			}
+			for k := j; k <= len(parts); k++ {
+				if k-j >= min && matchFrom(i+1, k) {
+					return true
+				}
+				if k < len(parts) && !dot && strings.HasPrefix(parts[k], ".") {
+					break
+				}
+			}
+		}
+		failed[key] = true
+		return false
+	}
+	return matchFrom(0, 0)
+}
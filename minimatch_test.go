@@ -0,0 +1,109 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestNewMinimatchPattern(t *testing.T) {
+	// Verified with minimatch 9.
+	tests := []struct {
+		pattern string
+		name    string
+		opts    MinimatchOptions
+		want    bool
+	}{
+		{"*.js", "a.js", MinimatchOptions{}, true},
+		{"*.js", "src/a.js", MinimatchOptions{}, false},
+		{"**/*.js", "src/a.js", MinimatchOptions{}, true},
+		{"**/*.js", "a.js", MinimatchOptions{}, true},
+		{"src/**", "src", MinimatchOptions{}, false},
+		{"src/**", "src/a/b.js", MinimatchOptions{}, true},
+		{"*.js", ".eslintrc.js", MinimatchOptions{}, false},
+		{".*.js", ".eslintrc.js", MinimatchOptions{}, true},
+		{"**/*.js", ".hidden/a.js", MinimatchOptions{}, false},
+		{"src/**/*.ts", "src/.x/a.ts", MinimatchOptions{}, false},
+		{"*.{js,ts}", "a.ts", MinimatchOptions{}, true},
+		{"file{1..3}.txt", "file2.txt", MinimatchOptions{}, true},
+		{"file{1..3}.txt", "file4.txt", MinimatchOptions{}, false},
+		{"file{a..c}", "fileb", MinimatchOptions{}, true},
+		{"!(*.d).ts", "a.ts", MinimatchOptions{}, true},
+		{"+(a|b).js", "abab.js", MinimatchOptions{}, true},
+		{"@(a|b).js", "ab.js", MinimatchOptions{}, false},
+		{"a/**/b", "a/b", MinimatchOptions{}, true},
+		{"a/**/b", "a/x/y/b", MinimatchOptions{}, true},
+		{"dist/", "dist", MinimatchOptions{}, false},
+		{"dist/", "dist/", MinimatchOptions{}, true},
+		{"[!a]*.js", "b.js", MinimatchOptions{}, true},
+		{"?.js", "a.js", MinimatchOptions{}, true},
+		{"a/*/c", "a/b/c", MinimatchOptions{}, true},
+		{"a/*/c", "a/b/x/c", MinimatchOptions{}, false},
+		{"{a,b}/**", "b/c", MinimatchOptions{}, true},
+		{"*.js", ".eslintrc.js", MinimatchOptions{Dot: true}, true},
+		{"**/*.js", ".hidden/a.js", MinimatchOptions{Dot: true}, true},
+		{"*.js", "src/a.js", MinimatchOptions{MatchBase: true}, true},
+		{"src/*.js", "x/src/a.js", MinimatchOptions{MatchBase: true}, false},
+		{"*.JS", "src/A.js", MinimatchOptions{MatchBase: true, NoCase: true}, true},
+		{"./dist/*.js", "dist/a.js", MinimatchOptions{}, true},
+	}
+	for _, test := range tests {
+		p, err := NewMinimatchPattern(test.pattern, test.opts)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := p.Match(test.name); got != test.want {
+			t.Errorf("NewMinimatchPattern('%s', %+v).Match('%s') returned '%v', want '%v'", test.pattern, test.opts, test.name, got, test.want)
+		}
+	}
+
+	for pattern, want := range map[string]bool{"*.js": false, "!*.js": true, "!!*.js": false} {
+		p, err := NewMinimatchPattern(pattern, MinimatchOptions{})
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := p.Negate(); got != want {
+			t.Errorf("NewMinimatchPattern('%s').Negate() returned '%v', want '%v'", pattern, got, want)
+		}
+	}
+
+	for _, pattern := range []string{"!", "./", "a!(b!(c))"} {
+		if _, err := NewMinimatchPattern(pattern, MinimatchOptions{}); err == nil {
+			t.Errorf("NewMinimatchPattern('%s') returned no error", pattern)
+		}
+	}
+}
+
+func TestWithMinimatch(t *testing.T) {
+	ps, err := FromLinesWithOptions([]string{"dist/**", "!dist/**/*.map", "*.MD"}, WithMinimatch(MinimatchOptions{}), WithCaseSensitive(false))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := map[string]bool{
+		"dist/index.js":      true,
+		"dist/lib/index.map": false,
+		"README.md":          true,
+		"docs/README.md":     false,
+		"src/index.js":       false,
+	}
+	for name, want := range tests {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithUnicodeNormalization normalizes patterns and names to the Unicode
+// normalization form before matching. File systems like the ones of macOS
+// store names in NFD, while patterns are usually typed in NFC, so without
+// normalization a pattern like "café.txt" silently fails to match a name
+// written by such a file system. Normalizing both to norm.NFC fixes that.
+func WithUnicodeNormalization(form norm.Form) Option {
+	return func(o *options) {
+		o.normalize = form.String
+	}
+}
@@ -0,0 +1,60 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestWithUnicodeNormalization(t *testing.T) {
+	nfc := "caf\u00e9"
+	nfd := "cafe\u0301"
+	lines := []string{nfc + "/", "r\u00e9sum\u00e9*.pdf"}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if ps.Match(nfd + "/menu.txt") {
+		t.Errorf("Match('%s') without normalization returned 'true', want 'false'", nfd+"/menu.txt")
+	}
+
+	for _, form := range []norm.Form{norm.NFC, norm.NFD} {
+		ps, err := FromLinesWithOptions(lines, WithUnicodeNormalization(form))
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		tests := []struct {
+			name string
+			want bool
+		}{
+			{nfc + "/menu.txt", true},
+			{nfd + "/menu.txt", true},
+			{"docs/r\u00e9sum\u00e9-2020.pdf", true},
+			{"docs/re\u0301sume\u0301-2020.pdf", true},
+			{"cafe/menu.txt", false},
+		}
+		for _, test := range tests {
+			if got := ps.Match(test.name); got != test.want {
+				t.Errorf("Match('%s') with normalization %v returned '%v', want '%v'", test.name, form, got, test.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"io"
+	"path/filepath"
+)
+
+// Option configures how a PathSpec is compiled.
+type Option func(*options)
+
+// options holds the configuration of a PathSpec.
+type options struct {
+	braceExpansion  bool
+	fnmatch         bool
+	extglob         bool
+	caseInsensitive bool
+	windows         bool
+	windowsRoot     string
+	normalize       func(string) string
+	hooks           *Hooks
+	cacheSize       int
+	strict          bool
+	rawLines        bool
+	limits          Limits
+	wildmatch       bool
+	minimatch       *MinimatchOptions
+	patternStats    bool
+	lazyCompile     bool
+	tracked         func(string) bool
+}
+
+// newOptions applies opts to the default configuration.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// FromLinesWithOptions is like FromLines, but configurable with opts.
+func FromLinesWithOptions(lines []string, opts ...Option) (*PathSpec, error) {
+	ps, errs, err := newOptions(opts).fromLines(lines)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return ps, nil
+}
+
+// fromLines compiles lines according to the options. Patterns which fail to
+// compile are left out of the PathSpec and returned as errs; err reports
+// lines exceeding the limits.
+func (o *options) fromLines(lines []string) (*PathSpec, ParseErrors, error) {
+	if err := o.limits.checkLines(lines); err != nil {
+		return nil, nil, err
+	}
+	patterns, errs := compilePatterns("", lines, patternFromLine, o.compile)
+	ps := NewPathSpec(patterns...)
+	ps.pathFunc = o.pathFunc()
+	ps.hooks = o.hooks
+	ps.tracked = o.tracked
+	if o.cacheSize > 0 {
+		ps.cache = newMatchCache(o.cacheSize)
+	}
+	if o.patternStats {
+		ps.EnablePatternStats()
+	}
+	return ps, errs, nil
+}
+
+// FromReaderWithOptions is like FromReader, but configurable with opts.
+func FromReaderWithOptions(r io.Reader, opts ...Option) (*PathSpec, error) {
+	o := newOptions(opts)
+	lines, err := scanLines(r, o.rawLines, o.limits)
+	if err != nil {
+		return nil, err
+	}
+	return FromLinesWithOptions(lines, opts...)
+}
+
+// WithRawLines reads the lines of a gitignore file exactly as they are: a
+// leading UTF-8 byte order mark and carriage returns at the end of lines
+// become part of the patterns, which then only match names containing them.
+// By default, FromReaderWithOptions strips both, so files written on Windows
+// work as expected. Git strips the byte order mark, but keeps carriage
+// returns on systems with LF line endings. Lines passed to
+// FromLinesWithOptions are always used as they are.
+func WithRawLines() Option {
+	return func(o *options) {
+		o.rawLines = true
+	}
+}
+
+// compile compiles a single gitignore pattern according to the options.
+func (o *options) compile(line string) (*Pattern, error) {
+	if o.normalize != nil {
+		line = o.normalize(line)
+	}
+	if o.strict {
+		if err := checkStrict(line); err != nil {
+			return nil, err
+		}
+	}
+	var p *Pattern
+	var err error
+	switch {
+	case o.wildmatch:
+		p, err = newWildmatchPattern(line, o.caseInsensitive)
+	case o.minimatch != nil:
+		opts := *o.minimatch
+		opts.NoCase = opts.NoCase || o.caseInsensitive
+		if err := o.limits.checkExpansion(expandSequences(line)); err != nil {
+			return nil, err
+		}
+		p, err = NewMinimatchPattern(line, opts)
+	case o.fnmatch:
+		p, err = NewFnmatchPattern(line)
+	case o.extglob:
+		p, err = newExtglobPattern(line)
+	case o.braceExpansion:
+		p, err = newBracePattern(line, o.limits)
+	default:
+		p, err = newPattern(line, o.lazyCompile)
+	}
+	if err == nil && o.caseInsensitive {
+		p, err = foldCase(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := o.limits.checkRegex(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// pathFunc returns the function converting names to the slash-separated
+// paths the patterns are matched against, or nil for filepath.ToSlash.
+func (o *options) pathFunc() func(string) string {
+	var fn func(string) string
+	if o.windows {
+		fn = windowsPathFunc(o.windowsRoot)
+	}
+	if o.normalize == nil {
+		return fn
+	}
+	if fn == nil {
+		fn = filepath.ToSlash
+	}
+	normalize := o.normalize
+	return func(name string) string {
+		return normalize(fn(name))
+	}
+}
@@ -0,0 +1,642 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Matcher matches slash-separated paths. Directories are denoted by a
+// trailing slash. Custom pattern syntaxes implement Matcher, see
+// RegisterPatternFactory and NewMatcherPattern.
+type Matcher interface {
+	Match(name string) bool
+}
+
+// Pattern is a single compiled gitignore pattern.
+type Pattern struct {
+	syntax  string
+	text    string
+	negate  bool
+	regex   *regexp.Regexp
+	matcher Matcher
+	source  string
+	line    int
+	dir     bool
+	// factory is true if the pattern has been compiled by the
+	// PatternFactory registered as its syntax, so it can be compiled again
+	// from its text.
+	factory bool
+
+	// prefix is the literal first path segment of an anchored pattern and
+	// base the literal last path segment of an unanchored pattern. They
+	// are used to index patterns, see patternIndex.
+	prefix string
+	base   string
+
+	// literal is the path of a pattern without wildcards which does not
+	// end with a slash, like "docs/index.md" for "docs/index.md" and
+	// "/docs/index.md". Such patterns are matched by comparing strings
+	// instead of running the regular expression. anchored is true if the
+	// literal path must match from the root.
+	literal  string
+	anchored bool
+
+	// lazy holds the regular expression of a pattern compiled with
+	// WithLazyCompile instead of regex, until it is first needed.
+	lazy *lazyRegex
+}
+
+// NewPattern compiles a single gitignore pattern. Blank lines and comments
+// are not patterns; callers are expected to filter them out beforehand.
+func NewPattern(line string) (*Pattern, error) {
+	return newPattern(line, false)
+}
+
+// newPattern compiles a single gitignore pattern, deferring the compilation
+// of its regular expression to its first use if lazy is set.
+func newPattern(line string, lazy bool) (*Pattern, error) {
+	p, err := parsePattern(line)
+	if err != nil {
+		return nil, err
+	}
+	pattern := &Pattern{
+		syntax: "gitwildmatch",
+		text:   line,
+		negate: p.Include,
+		dir:    strings.HasSuffix(line, "/"),
+	}
+	if lazy {
+		if pattern.lazy, err = newLazyRegex(p.Regex); err != nil {
+			return nil, err
+		}
+	} else if pattern.regex, err = regexp.Compile(p.Regex); err != nil {
+		return nil, err
+	}
+	if first := p.Segments[0]; first != "**" && isLiteral(first) {
+		pattern.prefix = first
+	} else if last := p.Segments[len(p.Segments)-1]; first == "**" && !pattern.dir && isLiteral(last) {
+		pattern.base = last
+	}
+	if !pattern.dir {
+		pattern.literal, pattern.anchored = literalPath(p.Segments)
+	}
+	return pattern, nil
+}
+
+// literalPath returns the path normalized segments describe if they contain
+// no wildcards, and whether it is anchored at the root.
+func literalPath(segs []string) (string, bool) {
+	anchored := segs[0] != "**"
+	if !anchored {
+		segs = segs[1:]
+	}
+	if len(segs) == 0 {
+		return "", false
+	}
+	for _, seg := range segs {
+		if !isLiteral(seg) {
+			return "", false
+		}
+	}
+	return strings.Join(segs, "/"), anchored
+}
+
+// String returns the pattern as it was written.
+func (p *Pattern) String() string {
+	return p.text
+}
+
+// NewRegexPattern compiles a pattern which treats expr as a Go regular
+// expression instead of a gitignore glob, like the "regex" syntax of
+// python-pathspec. The expression is matched against slash-separated paths,
+// with a trailing slash for directories, and is not anchored implicitly.
+func NewRegexPattern(expr string) (*Pattern, error) {
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{syntax: "regex", text: expr, regex: regex}, nil
+}
+
+// Syntax returns the name of the pattern syntax the pattern was compiled
+// with, see RegisterPatternFactory.
+func (p *Pattern) Syntax() string {
+	return p.syntax
+}
+
+// Source returns the name of the file the pattern was read from, or an empty
+// string if it is unknown.
+func (p *Pattern) Source() string {
+	return p.source
+}
+
+// Line returns the line number of the pattern in its source, starting at 1,
+// or 0 if it is unknown.
+func (p *Pattern) Line() int {
+	return p.line
+}
+
+// Negate reports whether the pattern starts with "!" and therefore re-includes
+// the paths it matches.
+func (p *Pattern) Negate() bool {
+	return p.negate
+}
+
+// Regex returns the regular expression the pattern has been translated to, or
+// nil if the pattern is matched by a custom Matcher.
+func (p *Pattern) Regex() *regexp.Regexp {
+	return p.compiledRegex()
+}
+
+// Equal reports whether p and other are the same pattern, that is whether
+// they have the same source text, negation and regular expression. Patterns
+// matched by custom Matchers are compared by text and negation only.
+func (p *Pattern) Equal(other *Pattern) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	return p.text == other.text &&
+		p.negate == other.negate &&
+		p.regexString() == other.regexString()
+}
+
+// Match reports whether the pattern matches name, regardless of negation.
+func (p *Pattern) Match(name string) bool {
+	return p.match(filepath.ToSlash(name))
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (p *Pattern) MatchPath(name string, isDir bool) bool {
+	return p.Match(dirName(name, isDir))
+}
+
+// match reports whether the pattern matches the slash-separated path name.
+func (p *Pattern) match(name string) bool {
+	switch {
+	case p.matcher != nil:
+		return p.matcher.Match(name)
+	case p.literal != "":
+		return p.matchLiteral(name)
+	}
+	return p.compiledRegex().MatchString(name)
+}
+
+// matchLiteral matches a pattern without wildcards like its regular
+// expression would: the path, ignoring a trailing slash, must equal the
+// literal path or, for unanchored patterns, end with it after at least one
+// leading path segment.
+func (p *Pattern) matchLiteral(name string) bool {
+	name = strings.TrimSuffix(name, "/")
+	if name == p.literal {
+		return true
+	}
+	return !p.anchored && len(name) > len(p.literal)+1 &&
+		strings.HasSuffix(name, p.literal) && name[len(name)-len(p.literal)-1] == '/'
+}
+
+// regexString returns the source of the pattern's regular expression, or an
+// empty string if it has none.
+func (p *Pattern) regexString() string {
+	switch {
+	case p.lazy != nil:
+		return p.lazy.expr
+	case p.regex != nil:
+		return p.regex.String()
+	}
+	return ""
+}
+
+// hasRegex reports whether the pattern is matched by a regular expression,
+// compiled or not yet compiled.
+func (p *Pattern) hasRegex() bool {
+	return p.regex != nil || p.lazy != nil
+}
+
+// MatchState describes how a PathSpec decided about a path.
+type MatchState int
+
+const (
+	// StateUnmatched means no pattern matched the path.
+	StateUnmatched MatchState = iota
+	// StateIgnored means the last matching pattern ignored the path.
+	StateIgnored
+	// StateIncluded means the last matching pattern was a negation which
+	// explicitly re-included the path.
+	StateIncluded
+)
+
+// String returns a human readable name of the state.
+func (s MatchState) String() string {
+	switch s {
+	case StateIgnored:
+		return "ignored"
+	case StateIncluded:
+		return "included"
+	default:
+		return "unmatched"
+	}
+}
+
+// PathSpec is an ordered list of compiled gitignore patterns. Compiling the
+// patterns once and reusing the PathSpec is considerably cheaper than calling
+// GitIgnore or ReadGitIgnore for every name.
+type PathSpec struct {
+	patterns []*Pattern
+	index    *patternIndex
+	// pathFunc converts names to slash-separated paths, if it differs from
+	// filepath.ToSlash, see WithWindowsPaths.
+	pathFunc func(string) string
+	hooks    *Hooks
+	cache    *matchCache
+	// disabled holds the patterns which are not matched, see Disable.
+	disabled map[*Pattern]bool
+	// counters counts the decisions of the patterns, if enabled, see
+	// PatternStats.
+	counters *patternCounters
+	// tracked reports whether a path is tracked by git, see SetTracked.
+	tracked func(string) bool
+}
+
+// NewPathSpec returns a PathSpec matching the given patterns in order.
+func NewPathSpec(patterns ...*Pattern) *PathSpec {
+	return &PathSpec{patterns: patterns, index: newPatternIndex(patterns)}
+}
+
+// FromLines compiles a PathSpec from gitignore lines. Blank lines and comments
+// are skipped.
+func FromLines(lines ...string) (*PathSpec, error) {
+	return compileLines("", lines, NewPattern)
+}
+
+// FromReader compiles a PathSpec from a gitignore file, line by line. A
+// leading UTF-8 byte order mark is stripped from the first line, and CRLF line
+// endings are accepted as well, see WithRawLines.
+func FromReader(r io.Reader) (*PathSpec, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return compileLines("", lines, NewPattern)
+}
+
+// FromFile compiles a PathSpec from the gitignore file name. Unlike
+// FromReader, the patterns remember name as their source.
+func FromFile(name string) (*PathSpec, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines, err := readLines(f)
+	if err != nil {
+		return nil, err
+	}
+	return compileLines(name, lines, NewPattern)
+}
+
+// readLines reads all lines of r. A leading UTF-8 byte order mark is stripped
+// from the first line, and a carriage return from the end of every line, so
+// files written on Windows with CRLF line endings read like all others.
+func readLines(r io.Reader) ([]string, error) {
+	return scanLines(r, false, Limits{})
+}
+
+// scanLines reads all lines of r. Lines are terminated by "\n" and may be
+// arbitrarily long. Unless raw is set, they are cleaned up like readLines
+// does. Reading stops at the first line exceeding limits.
+func scanLines(r io.Reader, raw bool, limits Limits) ([]string, error) {
+	var lines []string
+	br := bufio.NewReader(r)
+	max := 0
+	if limits.MaxLineLength > 0 {
+		// Leave room for the BOM and the line terminator.
+		max = limits.MaxLineLength + len(byteOrderMark) + 2
+	}
+	patterns := 0
+	for {
+		line, err := readLine(br, max)
+		if err == io.EOF {
+			return lines, nil
+		} else if errors.Is(err, bufio.ErrTooLong) {
+			return nil, ParseErrors{{Line: len(lines) + 1, Err: limits.lineTooLong()}}
+		} else if err != nil {
+			return nil, err
+		}
+		if !raw {
+			if len(lines) == 0 {
+				line = strings.TrimPrefix(line, byteOrderMark)
+			}
+			line = strings.TrimSuffix(line, "\r")
+		}
+		if err := limits.checkLine(line, &patterns); err != nil {
+			return nil, ParseErrors{{Line: len(lines) + 1, Err: err}}
+		}
+		lines = append(lines, line)
+	}
+}
+
+// readLine reads the next line of br, without its terminating "\n". Unlike
+// bufio.Scanner, it reads lines of any length, unless max is positive: then
+// it stops with bufio.ErrTooLong once the line and its terminator exceed max
+// bytes, without reading the rest of the line. It returns io.EOF if there
+// are no more lines.
+func readLine(br *bufio.Reader, max int) (string, error) {
+	var long []byte
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if max > 0 && len(long)+len(chunk) > max {
+			return "", bufio.ErrTooLong
+		}
+		if err == bufio.ErrBufferFull {
+			long = append(long, chunk...)
+			continue
+		}
+		if err != nil && (err != io.EOF || len(long)+len(chunk) == 0) {
+			return "", err
+		}
+		chunk = bytes.TrimSuffix(chunk, []byte("\n"))
+		if long == nil {
+			return string(chunk), nil
+		}
+		return string(append(long, chunk...)), nil
+	}
+}
+
+// compileLines compiles the patterns of lines read from source with compile.
+// Blank lines and comments are skipped. If any line fails to compile, the
+// returned error is a ParseErrors listing all of them.
+func compileLines(source string, lines []string, compile func(string) (*Pattern, error)) (*PathSpec, error) {
+	return compileLinesWith(source, lines, patternFromLine, compile)
+}
+
+// compileLinesWith compiles lines like compileLines, but extracts their
+// patterns with extract instead of patternFromLine.
+func compileLinesWith(source string, lines []string, extract func(string) (string, bool), compile func(string) (*Pattern, error)) (*PathSpec, error) {
+	patterns, errs := compilePatterns(source, lines, extract, compile)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return NewPathSpec(patterns...), nil
+}
+
+// compilePatterns compiles the patterns of lines read from source with
+// compile, like compileLinesWith, but returns the patterns which compiled
+// together with the errors of the others.
+func compilePatterns(source string, lines []string, extract func(string) (string, bool), compile func(string) (*Pattern, error)) ([]*Pattern, ParseErrors) {
+	var patterns []*Pattern
+	var errs ParseErrors
+	for i, line := range lines {
+		pattern, ok := extract(line)
+		if !ok {
+			continue
+		}
+		p, err := compile(pattern)
+		if err != nil {
+			errs = append(errs, &ParseError{Source: source, Line: i + 1, Pattern: pattern, Err: err})
+			continue
+		}
+		p.source = source
+		p.line = i + 1
+		patterns = append(patterns, p)
+	}
+	return patterns, errs
+}
+
+// Patterns returns the compiled patterns in the order they were parsed.
+func (ps *PathSpec) Patterns() []*Pattern {
+	return ps.patterns
+}
+
+// Match reports whether name is ignored by the PathSpec. As in git, the last
+// matching pattern decides: a matching negated pattern re-includes name.
+// Directories are denoted by a trailing slash, e.g. "build/".
+func (ps *PathSpec) Match(name string) bool {
+	return ps.MatchState(name) == StateIgnored
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (ps *PathSpec) MatchPath(name string, isDir bool) bool {
+	return ps.Match(dirName(name, isDir))
+}
+
+// MatchEntry is like MatchPath, but takes whether name is a directory from
+// the directory entry d, e.g. as passed to an fs.WalkDirFunc.
+func (ps *PathSpec) MatchEntry(name string, d fs.DirEntry) bool {
+	return ps.MatchPath(name, d.IsDir())
+}
+
+// MatchState is like Match, but distinguishes names no pattern matched from
+// names a negated pattern explicitly re-included.
+func (ps *PathSpec) MatchState(name string) MatchState {
+	name = ps.slashPath(name)
+	if isTracked(ps.tracked, name) {
+		ps.decided(name, StateIncluded)
+		return StateIncluded
+	}
+	state, ok := StateUnmatched, false
+	if ps.cache != nil {
+		state, ok = ps.cache.get(name)
+	}
+	if !ok {
+		state = patternState(ps.lastMatch(name))
+		if ps.cache != nil {
+			ps.cache.put(name, state)
+		}
+	}
+	ps.decided(name, state)
+	return state
+}
+
+// MatchGit is like Match, but follows git's rule that a path cannot be
+// re-included if one of its parent directories is ignored: git does not look
+// into ignored directories, so a negation like "!build/keep" has no effect
+// once "build/" is ignored. MatchGit therefore agrees with "git status",
+// while Match only considers the patterns matching name itself.
+func (ps *PathSpec) MatchGit(name string) bool {
+	return ps.MatchStateGit(name) == StateIgnored
+}
+
+// MatchStateGit is like MatchGit, but distinguishes names no pattern matched
+// from names a negated pattern explicitly re-included.
+func (ps *PathSpec) MatchStateGit(name string) MatchState {
+	name = ps.slashPath(name)
+	if isTracked(ps.tracked, name) {
+		ps.decided(name, StateIncluded)
+		return StateIncluded
+	}
+	_, p := ps.decideGit(name)
+	state := patternState(p)
+	ps.decided(name, state)
+	return state
+}
+
+// decideGit returns the pattern deciding about the slash-separated path name
+// according to git's rules, and the path it matched, which is either name or
+// one of its parent directories.
+func (ps *PathSpec) decideGit(name string) (string, *Pattern) {
+	trimmed := strings.TrimSuffix(name, "/")
+	for i := strings.IndexByte(trimmed, '/'); i >= 0; i = nextSlash(trimmed, i) {
+		if p := ps.lastMatch(name[:i+1]); p != nil && !p.negate {
+			return name[:i+1], p
+		}
+	}
+	return name, ps.lastMatch(name)
+}
+
+// lastMatch returns the last pattern matching name, or nil.
+func (ps *PathSpec) lastMatch(name string) *Pattern {
+	return ps.patternAt(ps.lastMatchIndex(name))
+}
+
+// lastMatchIndex returns the position of the last pattern matching name, or
+// -1. The decision is counted and reported to hooks.
+func (ps *PathSpec) lastMatchIndex(name string) int {
+	i := ps.findLastIndex(name)
+	if i < 0 {
+		return i
+	}
+	if ps.counters != nil {
+		ps.counters.hit(ps.patterns[i])
+	}
+	if ps.hooks != nil && ps.hooks.OnMatch != nil {
+		ps.hooks.OnMatch(ps.patterns[i], name)
+	}
+	return i
+}
+
+// findLastMatch returns the last pattern matching name, or nil, without
+// calling hooks.
+func (ps *PathSpec) findLastMatch(name string) *Pattern {
+	return ps.patternAt(ps.findLastIndex(name))
+}
+
+// findLastIndex returns the position of the last pattern matching name, or
+// -1, without calling hooks.
+func (ps *PathSpec) findLastIndex(name string) int {
+	if ps.index == nil {
+		for i := len(ps.patterns) - 1; i >= 0; i-- {
+			if p := ps.patterns[i]; !ps.disabled[p] && p.match(name) {
+				return i
+			}
+		}
+		return -1
+	}
+	var buf [64]int
+	candidates := ps.index.candidates(name, buf[:0])
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if ps.patterns[candidates[i]].match(name) {
+			return candidates[i]
+		}
+	}
+	return -1
+}
+
+// patternAt returns the pattern at position i, or nil if i is negative.
+func (ps *PathSpec) patternAt(i int) *Pattern {
+	if i < 0 {
+		return nil
+	}
+	return ps.patterns[i]
+}
+
+// patternState returns the state of a path decided by the pattern p, which
+// may be nil if no pattern matched.
+func patternState(p *Pattern) MatchState {
+	switch {
+	case p == nil:
+		return StateUnmatched
+	case p.negate:
+		return StateIncluded
+	default:
+		return StateIgnored
+	}
+}
+
+// byteOrderMark is the UTF-8 encoded byte order mark some editors write at
+// the beginning of a file.
+const byteOrderMark = "\ufeff"
+
+// dirName appends a trailing slash to name if it is a directory.
+func dirName(name string, isDir bool) string {
+	if isDir && !strings.HasSuffix(name, "/") {
+		return name + "/"
+	}
+	return name
+}
+
+// patternFromLine extracts the pattern of a gitignore line and reports
+// whether it holds one, that is whether it is neither blank nor a comment.
+// Like in git, only a "#" at the very start of the line starts a comment,
+// leading whitespace is part of the pattern, and trailing spaces are removed
+// unless they are escaped with a backslash, see trimTrailingSpaces.
+func patternFromLine(line string) (string, bool) {
+	if len(line) == 0 || line[0] == '#' {
+		return "", false
+	}
+	pattern := trimTrailingSpaces(line)
+	return pattern, pattern != ""
+}
+
+// trimmedPatternFromLine extracts the pattern of a line of the Docker and
+// Helm dialects. Unlike git, both trim whitespace on both sides of a line
+// before checking for a comment, so "  # note" is a comment and "  foo" the
+// pattern "foo".
+func trimmedPatternFromLine(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	return line, line != "" && line[0] != '#'
+}
+
+// trimTrailingSpaces removes the trailing spaces of line which are not
+// escaped with a backslash, exactly like git does: "foo  " becomes "foo",
+// "foo \ " stays as it is, since its last space is escaped, and "foo\  "
+// becomes "foo\ ". Other whitespace, like tabs, is never removed. A trailing
+// backslash escapes nothing and is kept.
+func trimTrailingSpaces(line string) string {
+	lastSpace := -1
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			if lastSpace < 0 {
+				lastSpace = i
+			}
+		case '\\':
+			i++
+			if i == len(line) {
+				return line
+			}
+			lastSpace = -1
+		default:
+			lastSpace = -1
+		}
+	}
+	if lastSpace >= 0 {
+		return line[:lastSpace]
+	}
+	return line
+}
@@ -0,0 +1,256 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPathSpecMatch(t *testing.T) {
+	toInclude := []string{"foo.txt", "keep.log", "logs/keep.log", "build"}
+	toIgnore := []string{"debug.log", "logs/debug.log", "build/out.o", "build/", "logs/"}
+	lines := []string{"# comment", "", "*.log", "!keep.log", "build/", "logs"}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if len(ps.Patterns()) != 4 {
+		t.Fatalf("FromLines('%s') returned %d patterns, want 4", lines, len(ps.Patterns()))
+	}
+
+	for _, f := range toInclude {
+		if ps.Match(f) {
+			t.Errorf("Match('%s', %s) returned 'true', want 'false'", lines, f)
+		}
+	}
+
+	for _, f := range toIgnore {
+		if !ps.Match(f) {
+			t.Errorf("Match('%s', %s) returned 'false', want 'true'", lines, f)
+		}
+	}
+}
+
+func TestPathSpecMatchState(t *testing.T) {
+	lines := []string{"*.log", "!keep.log", "build/"}
+	tests := map[string]MatchState{
+		"foo.txt":       StateUnmatched,
+		"src/main.go":   StateUnmatched,
+		"debug.log":     StateIgnored,
+		"build/out.o":   StateIgnored,
+		"keep.log":      StateIncluded,
+		"logs/keep.log": StateIncluded,
+	}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for f, want := range tests {
+		if got := ps.MatchState(f); got != want {
+			t.Errorf("MatchState('%s', %s) returned '%v', want '%v'", lines, f, got, want)
+		}
+	}
+}
+
+func TestPathSpecMatchDeepPath(t *testing.T) {
+	lines := []string{"a/**/b/**/c", "**/x/**/y/**/z", "*/**/*.txt/**/end"}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	// A deep path of 10k characters which almost matches every pattern.
+	name := "a/" + strings.Repeat("b/x/y/", 10000/6) + "z.txt/nope"
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if ps.Match(name) {
+			t.Fatalf("Match('%s', <deep path>) returned 'true', want 'false'", lines)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Match('%s', <deep path>) took %s, want less than 1s", lines, elapsed)
+	}
+}
+
+func BenchmarkPathSpecMatchDeepPath(b *testing.B) {
+	ps, err := FromLines("a/**/b/**/c")
+	if err != nil {
+		b.Fatalf("Received an unexpected error: %s", err)
+	}
+	name := "a/" + strings.Repeat("b/", 5000) + "d"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ps.Match(name)
+	}
+}
+
+func TestFromReader(t *testing.T) {
+	tests := map[string]string{
+		"byte order mark":          "\ufefffoo\n",
+		"missing trailing newline": "bar\nfoo",
+		"CRLF":                     "bar\r\nfoo\r\n",
+		"CRLF without newline":     "bar\r\nfoo\r",
+		"byte order mark and CRLF": "\ufefffoo\r\nbar\r\n",
+		"line longer than 64KB":    strings.Repeat("a", 100000) + "\nfoo\n",
+	}
+
+	for name, content := range tests {
+		ps, err := FromReader(strings.NewReader(content))
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if !ps.Match("foo") {
+			t.Errorf("FromReader(%s).Match(foo) returned 'false', want 'true'", name)
+		}
+	}
+}
+
+func TestFromReaderRawLines(t *testing.T) {
+	ps, err := FromReaderWithOptions(strings.NewReader("\ufeffbom\nfoo\r\nbar\n"), WithRawLines())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for name, want := range map[string]bool{"bom": false, "\ufeffbom": true, "foo": false, "foo\r": true, "bar": true} {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match(%q) returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
+
+func TestPatternEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"*.log", "*.log", true},
+		{"foo/", "foo", false},
+		{"foo", "!foo", false},
+		{"/foo", "foo", false},
+	}
+
+	for _, test := range tests {
+		a, err := NewPattern(test.a)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		b, err := NewPattern(test.b)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := a.Equal(b); got != test.want {
+			t.Errorf("Equal(%s, %s) returned '%v', want '%v'", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestPathSpecMatchPath(t *testing.T) {
+	lines := []string{"build/", "*.log"}
+	tests := []struct {
+		name  string
+		isDir bool
+		want  bool
+	}{
+		{"build", true, true},
+		{"build/", true, true},
+		{"build", false, false},
+		{"src/build", true, true},
+		{"debug.log", false, true},
+		{"debug.log", true, true},
+		{"src", true, false},
+	}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for _, test := range tests {
+		if got := ps.MatchPath(test.name, test.isDir); got != test.want {
+			t.Errorf("MatchPath('%s', %s, %v) returned '%v', want '%v'", lines, test.name, test.isDir, got, test.want)
+		}
+	}
+}
+
+func TestPathSpecMatchGit(t *testing.T) {
+	lines := []string{"build/", "!build/keep", "logs/*", "!logs/keep", "*.tmp", "!a/b.tmp"}
+	tests := []struct {
+		name       string
+		match, git MatchState
+	}{
+		{"build/keep", StateIncluded, StateIgnored},
+		{"build/out", StateIgnored, StateIgnored},
+		{"logs/keep", StateIncluded, StateIncluded},
+		{"logs/out", StateIgnored, StateIgnored},
+		{"a/b.tmp", StateIncluded, StateIncluded},
+		{"c.tmp/d", StateUnmatched, StateIgnored},
+		{"src/main.go", StateUnmatched, StateUnmatched},
+	}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	for _, test := range tests {
+		if got := ps.MatchState(test.name); got != test.match {
+			t.Errorf("MatchState('%s', %s) returned '%v', want '%v'", lines, test.name, got, test.match)
+		}
+		if got := ps.MatchStateGit(test.name); got != test.git {
+			t.Errorf("MatchStateGit('%s', %s) returned '%v', want '%v'", lines, test.name, got, test.git)
+		}
+	}
+}
+
+// TestTrailingSpaces checks the whitespace rules of gitignore lines. The
+// expectations were taken from "git check-ignore --no-index".
+func TestTrailingSpaces(t *testing.T) {
+	ps, err := FromLines("a  ", "b\\ ", "c\\  ", "d \\ ", "e\\\\ ", "f\\", " g", "h\t")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for name, want := range map[string]bool{
+		"a":    true,
+		"a  ":  false,
+		"b ":   true,
+		"b":    false,
+		"c ":   true,
+		"c  ":  false,
+		"d  ":  true,
+		"d ":   false,
+		"e\\":  true,
+		"e\\ ": false,
+		"f\\":  false,
+		"f":    false,
+		" g":   true,
+		"g":    false,
+		"h\t":  true,
+		"h":    false,
+	} {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match(%q) returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathspecMagic is a set of git pathspec magic flags, see gitglossary(7).
+type PathspecMagic int
+
+const (
+	// MagicTop, ":(top)" or ":/", makes the pathspec relative to the root
+	// of the work tree. Pathspecs are always matched against paths
+	// relative to the root, so it has no effect on matching.
+	MagicTop PathspecMagic = 1 << iota
+	// MagicLiteral, ":(literal)", treats wildcards as ordinary characters.
+	MagicLiteral
+	// MagicGlob, ":(glob)", matches like gitignore patterns: wildcards do
+	// not match slashes, except for "**" forming whole path segments.
+	MagicGlob
+	// MagicIcase, ":(icase)", matches case-insensitively.
+	MagicIcase
+	// MagicExclude, ":(exclude)", ":!" or ":^", removes the paths it
+	// matches from the paths matched by the other pathspecs.
+	MagicExclude
+)
+
+// String formats the flags like the long form of pathspec magic, e.g.
+// "icase,exclude".
+func (m PathspecMagic) String() string {
+	var words []string
+	for _, w := range pathspecMagicWords {
+		if m&w.magic != 0 {
+			words = append(words, w.name)
+		}
+	}
+	return strings.Join(words, ",")
+}
+
+// pathspecMagicWords lists the long names of the magic flags.
+var pathspecMagicWords = []struct {
+	name  string
+	magic PathspecMagic
+}{
+	{"top", MagicTop},
+	{"literal", MagicLiteral},
+	{"glob", MagicGlob},
+	{"icase", MagicIcase},
+	{"exclude", MagicExclude},
+}
+
+// ParsePathspecMagic splits a git pathspec like ":(icase)*.md" or
+// ":!vendor/**" into its magic and the remaining pattern. The long form
+// ":(magic,...)" takes comma-separated magic words, the short form ":"
+// takes the mnemonics "/", "!" and "^", optionally terminated by another
+// ":". Magic git implements but this package does not, like ":(attr:...)",
+// is reported as an error, and so are "literal" combined with "glob" and
+// unknown magic words.
+func ParsePathspecMagic(arg string) (PathspecMagic, string, error) {
+	if !strings.HasPrefix(arg, ":") {
+		return 0, arg, nil
+	}
+	var magic PathspecMagic
+	if !strings.HasPrefix(arg, ":(") {
+		i := 1
+	short:
+		for ; i < len(arg); i++ {
+			switch arg[i] {
+			case '/':
+				magic |= MagicTop
+			case '!', '^':
+				magic |= MagicExclude
+			default:
+				break short
+			}
+		}
+		if i < len(arg) && arg[i] == ':' {
+			i++
+		}
+		return magic, arg[i:], nil
+	}
+
+	end := strings.IndexByte(arg, ')')
+	if end < 0 {
+		return 0, "", fmt.Errorf("missing ')' at the end of pathspec magic in %q", arg)
+	}
+	for _, word := range strings.Split(arg[2:end], ",") {
+		word = strings.TrimSpace(word)
+		found := false
+		for _, w := range pathspecMagicWords {
+			if word == w.name {
+				magic |= w.magic
+				found = true
+			}
+		}
+		switch {
+		case found, word == "":
+		case strings.HasPrefix(word, "attr:"), strings.HasPrefix(word, "prefix:"):
+			return 0, "", fmt.Errorf("unsupported pathspec magic %q in %q", word, arg)
+		default:
+			return 0, "", fmt.Errorf("invalid pathspec magic %q in %q", word, arg)
+		}
+	}
+	if magic&MagicLiteral != 0 && magic&MagicGlob != 0 {
+		return 0, "", fmt.Errorf("pathspec magic 'literal' and 'glob' are incompatible in %q", arg)
+	}
+	return magic, arg[end+1:], nil
+}
+
+// NewPathspecPattern compiles a git pathspec, as given on the command line of
+// git commands, into a pattern of the syntax "pathspec". Pathspecs with the
+// exclude magic are negated. See ParsePathspecMagic for the magic and
+// Pathspecs for matching a list of pathspecs like git does.
+//
+// Without magic, a pathspec matches a path which equals it or lies in the
+// directory it names, and its wildcards match like fnmatch(3) without
+// FNM_PATHNAME, so "*" also matches slashes: "Documentation/*.txt" matches
+// "Documentation/a/b.txt". With the glob magic, it matches like a gitignore
+// pattern anchored at the root instead.
+func NewPathspecPattern(arg string) (*Pattern, error) {
+	magic, pattern, err := ParsePathspecMagic(arg)
+	if err != nil {
+		return nil, err
+	}
+	m := &pathspecMatcher{magic: magic, pattern: pattern}
+	m.literal = magic&MagicLiteral != 0 || !strings.ContainsAny(pattern, "*?[\\")
+	if magic&MagicIcase != 0 {
+		m.flags = wmCaseFold
+	}
+	if magic&MagicGlob != 0 {
+		m.flags |= wmPathname
+	}
+	return &Pattern{syntax: "pathspec", text: arg, negate: magic&MagicExclude != 0, matcher: m}, nil
+}
+
+// pathspecMatcher matches a git pathspec like git's match_pathspec_item.
+type pathspecMatcher struct {
+	magic   PathspecMagic
+	pattern string
+	literal bool
+	flags   int
+}
+
+// Match reports whether the pathspec matches name, regardless of the exclude
+// magic.
+func (m *pathspecMatcher) Match(name string) bool {
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.TrimSuffix(name, "/")
+	folded, pattern := name, m.pattern
+	if m.magic&MagicIcase != 0 {
+		folded, pattern = strings.ToLower(name), strings.ToLower(pattern)
+	}
+	if pattern == "" || folded == pattern || (isDir && folded+"/" == pattern) {
+		return true
+	}
+	// A pathspec naming a directory matches everything inside it.
+	if strings.HasPrefix(folded, pattern) && (pattern[len(pattern)-1] == '/' || folded[len(pattern)] == '/') {
+		return true
+	}
+	return !m.literal && wildmatch(m.pattern, name, m.flags) == wmMatch
+}
+
+// Pathspecs selects paths with a list of git pathspecs, like the pathspec
+// arguments of "git ls-files" do: a path is selected if any pathspec without
+// the exclude magic matches it and no pathspec with the exclude magic does.
+// If all pathspecs have the exclude magic, every path they do not match is
+// selected.
+type Pathspecs struct {
+	patterns []*Pattern
+}
+
+// ParsePathspecs compiles the git pathspecs args, see NewPathspecPattern. The
+// returned ParseErrors give the position of an invalid pathspec in args,
+// starting at 1, as line number.
+func ParsePathspecs(args ...string) (*Pathspecs, error) {
+	ps := &Pathspecs{}
+	var errs ParseErrors
+	for i, arg := range args {
+		p, err := NewPathspecPattern(arg)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: i + 1, Pattern: arg, Err: err})
+			continue
+		}
+		p.line = i + 1
+		ps.patterns = append(ps.patterns, p)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return ps, nil
+}
+
+// Patterns returns the compiled pathspecs in order.
+func (ps *Pathspecs) Patterns() []*Pattern {
+	return ps.patterns
+}
+
+// Match reports whether the pathspecs select the slash-separated path name.
+// Directories are denoted by a trailing slash, e.g. "build/".
+func (ps *Pathspecs) Match(name string) bool {
+	included, positive := false, false
+	for _, p := range ps.patterns {
+		if p.negate {
+			if p.match(name) {
+				return false
+			}
+			continue
+		}
+		positive = true
+		included = included || p.match(name)
+	}
+	return included || !positive
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (ps *Pathspecs) MatchPath(name string, isDir bool) bool {
+	return ps.Match(dirName(name, isDir))
+}
@@ -0,0 +1,125 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePathspecMagic(t *testing.T) {
+	tests := []struct {
+		arg     string
+		magic   PathspecMagic
+		pattern string
+	}{
+		{"*.md", 0, "*.md"},
+		{":(icase)*.md", MagicIcase, "*.md"},
+		{":(glob,exclude)vendor/**", MagicGlob | MagicExclude, "vendor/**"},
+		{":!vendor/**", MagicExclude, "vendor/**"},
+		{":^vendor", MagicExclude, "vendor"},
+		{":/!:src", MagicTop | MagicExclude, "src"},
+		{"::src", 0, "src"},
+		{":/", MagicTop, ""},
+		{":(top,literal)a*b", MagicTop | MagicLiteral, "a*b"},
+	}
+	for _, test := range tests {
+		magic, pattern, err := ParsePathspecMagic(test.arg)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if magic != test.magic || pattern != test.pattern {
+			t.Errorf("ParsePathspecMagic('%s') returned '%v', '%s', want '%v', '%s'", test.arg, magic, pattern, test.magic, test.pattern)
+		}
+	}
+
+	for _, arg := range []string{":(glob", ":(nope)x", ":(attr:text)x", ":(literal,glob)x"} {
+		if _, _, err := ParsePathspecMagic(arg); err == nil {
+			t.Errorf("ParsePathspecMagic('%s') returned no error", arg)
+		}
+	}
+
+	if got := (MagicIcase | MagicExclude).String(); got != "icase,exclude" {
+		t.Errorf("PathspecMagic.String() returned '%s', want '%s'", got, "icase,exclude")
+	}
+}
+
+func TestPathspecs(t *testing.T) {
+	files := []string{
+		"Documentation/a/b.txt",
+		"Documentation/c.txt",
+		"README.d/x",
+		"README.md",
+		"a*b",
+		"aXb",
+		"src/Main.MD",
+		"src/main.go",
+		"vendor/x/y.go",
+	}
+	// Verified with git ls-files.
+	tests := map[string][]string{
+		"Documentation/*.txt":           {"Documentation/a/b.txt", "Documentation/c.txt"},
+		":(glob)Documentation/*.txt":    {"Documentation/c.txt"},
+		":(glob)Documentation/**/*.txt": {"Documentation/a/b.txt", "Documentation/c.txt"},
+		"src":                           {"src/Main.MD", "src/main.go"},
+		"src/":                          {"src/Main.MD", "src/main.go"},
+		":(icase)SRC":                   {"src/Main.MD", "src/main.go"},
+		":(icase)*.md":                  {"README.md", "src/Main.MD"},
+		":!vendor/**":                   files[:8],
+		":(literal)a*b":                 {"a*b"},
+		"a*b":                           {"a*b", "aXb"},
+		":(glob)*.go":                   nil,
+		"*.go":                          {"src/main.go", "vendor/x/y.go"},
+		"README":                        nil,
+	}
+	for arg, want := range tests {
+		ps, err := ParsePathspecs(arg)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		var got []string
+		for _, name := range files {
+			if ps.Match(name) {
+				got = append(got, name)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParsePathspecs('%s') selected '%v', want '%v'", arg, got, want)
+		}
+	}
+
+	ps, err := ParsePathspecs("src", "*.txt", ":!*.go", ":(exclude)Documentation/a")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for name, want := range map[string]bool{
+		"src/Main.MD":           true,
+		"src/main.go":           false,
+		"Documentation/c.txt":   true,
+		"Documentation/a/b.txt": false,
+		"README.md":             false,
+	} {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+
+	if _, err := ParsePathspecs("src", ":(nope)x"); err == nil {
+		t.Errorf("ParsePathspecs(':(nope)x') returned no error")
+	}
+}
@@ -0,0 +1,286 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RegexDialect names a regular expression syntax Pattern.RegexString emits.
+type RegexDialect string
+
+const (
+	// RegexRE2 is the syntax of Go's regexp package and RE2.
+	RegexRE2 RegexDialect = "re2"
+	// RegexPCRE is the syntax of PCRE, as used by PHP and Perl, among
+	// others.
+	RegexPCRE RegexDialect = "pcre"
+	// RegexECMAScript is the syntax of JavaScript regular expressions. The
+	// expressions are meant to be compiled with the u flag.
+	RegexECMAScript RegexDialect = "ecmascript"
+)
+
+// RegexString returns the regular expression the pattern has been translated
+// to in the given dialect, so it can be evaluated by other runtimes. Like
+// Regex, the expression matches slash-separated paths with a trailing slash
+// for directories, regardless of negation.
+//
+// Named groups are written as (?P<name>...) in RE2 and as (?<name>...)
+// otherwise; the end of the text is written as \z in PCRE, whose $ also
+// matches before a trailing newline; and case-insensitive parts, which
+// ECMAScript cannot switch on inline, are spelled out as character classes
+// there. Patterns without a regular expression, which are matched by a custom
+// Matcher, and multi-line anchors in ECMAScript return an error wrapping
+// ErrUntranslatable.
+func (p *Pattern) RegexString(dialect RegexDialect) (string, error) {
+	if dialect != RegexRE2 && dialect != RegexPCRE && dialect != RegexECMAScript {
+		return "", fmt.Errorf("unknown regex dialect %q", dialect)
+	}
+	if !p.hasRegex() {
+		return "", fmt.Errorf("%s pattern has no regular expression: %w", p.syntax, ErrUntranslatable)
+	}
+	if dialect == RegexRE2 {
+		return p.regexString(), nil
+	}
+	re, err := syntax.Parse(p.regexString(), syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	w := regexWriter{dialect: dialect}
+	if err := w.write(re); err != nil {
+		return "", err
+	}
+	return w.b.String(), nil
+}
+
+// regexWriter writes a parsed regular expression in a dialect other than RE2.
+// It follows the String method of syntax.Regexp, which writes RE2.
+type regexWriter struct {
+	b       strings.Builder
+	dialect RegexDialect
+}
+
+// write writes re.
+func (w *regexWriter) write(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		w.b.WriteString(`[^\s\S]`)
+	case syntax.OpEmptyMatch:
+		w.b.WriteString(`(?:)`)
+	case syntax.OpLiteral:
+		w.literal(re)
+	case syntax.OpCharClass:
+		w.charClass(re.Rune)
+	case syntax.OpAnyCharNotNL:
+		if w.dialect == RegexECMAScript {
+			// The dot of ECMAScript does not match \r and the
+			// Unicode line separators either.
+			w.b.WriteString(`[^\n]`)
+		} else {
+			w.b.WriteString(`.`)
+		}
+	case syntax.OpAnyChar:
+		w.b.WriteString(`[\s\S]`)
+	case syntax.OpBeginLine, syntax.OpEndLine:
+		if w.dialect == RegexECMAScript {
+			return fmt.Errorf("multi-line anchor in %s: %w", w.dialect, ErrUntranslatable)
+		}
+		w.b.WriteString(map[bool]string{true: `(?m:^)`, false: `(?m:$)`}[re.Op == syntax.OpBeginLine])
+	case syntax.OpBeginText:
+		w.b.WriteString(`^`)
+	case syntax.OpEndText:
+		if w.dialect == RegexPCRE {
+			w.b.WriteString(`\z`)
+		} else {
+			w.b.WriteString(`$`)
+		}
+	case syntax.OpWordBoundary:
+		w.b.WriteString(`\b`)
+	case syntax.OpNoWordBoundary:
+		w.b.WriteString(`\B`)
+	case syntax.OpCapture:
+		w.b.WriteString(`(`)
+		if re.Name != "" {
+			w.b.WriteString(`?<` + re.Name + `>`)
+		}
+		if err := w.write(re.Sub[0]); err != nil {
+			return err
+		}
+		w.b.WriteString(`)`)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		sub := re.Sub[0]
+		if err := w.group(sub, sub.Op > syntax.OpCapture || sub.Op == syntax.OpLiteral && len(sub.Rune) > 1); err != nil {
+			return err
+		}
+		switch re.Op {
+		case syntax.OpStar:
+			w.b.WriteString(`*`)
+		case syntax.OpPlus:
+			w.b.WriteString(`+`)
+		case syntax.OpQuest:
+			w.b.WriteString(`?`)
+		case syntax.OpRepeat:
+			w.b.WriteString(`{` + strconv.Itoa(re.Min))
+			if re.Max != re.Min {
+				w.b.WriteString(`,`)
+				if re.Max >= 0 {
+					w.b.WriteString(strconv.Itoa(re.Max))
+				}
+			}
+			w.b.WriteString(`}`)
+		}
+		if re.Flags&syntax.NonGreedy != 0 {
+			w.b.WriteString(`?`)
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := w.group(sub, sub.Op == syntax.OpAlternate); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		for i, sub := range re.Sub {
+			if i > 0 {
+				w.b.WriteString(`|`)
+			}
+			if err := w.write(sub); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported regular expression %s: %w", re, ErrUntranslatable)
+	}
+	return nil
+}
+
+// group writes re, in a non-capturing group if paren is true.
+func (w *regexWriter) group(re *syntax.Regexp, paren bool) error {
+	if paren {
+		w.b.WriteString(`(?:`)
+	}
+	if err := w.write(re); err != nil {
+		return err
+	}
+	if paren {
+		w.b.WriteString(`)`)
+	}
+	return nil
+}
+
+// literal writes the literal re, switching to case-insensitive matching if
+// it folds case.
+func (w *regexWriter) literal(re *syntax.Regexp) {
+	fold := re.Flags&syntax.FoldCase != 0 && strings.IndexFunc(string(re.Rune), func(r rune) bool {
+		return unicode.SimpleFold(r) != r
+	}) >= 0
+	if fold && w.dialect == RegexPCRE {
+		w.b.WriteString(`(?i:`)
+		defer w.b.WriteString(`)`)
+	}
+	for _, r := range re.Rune {
+		if fold && w.dialect == RegexECMAScript && unicode.SimpleFold(r) != r {
+			w.b.WriteString(`[`)
+			for f := r; ; {
+				w.classRune(f)
+				if f = unicode.SimpleFold(f); f == r {
+					break
+				}
+			}
+			w.b.WriteString(`]`)
+			continue
+		}
+		w.rune(r)
+	}
+}
+
+// rune writes the rune r outside of a character class.
+func (w *regexWriter) rune(r rune) {
+	if strings.ContainsRune(`\.+*?()|[]{}^$/`, r) {
+		w.b.WriteString(`\`)
+		w.b.WriteRune(r)
+		return
+	}
+	w.printable(r)
+}
+
+// classRune writes the rune r inside of a character class.
+func (w *regexWriter) classRune(r rune) {
+	if strings.ContainsRune(`\[]^-/`, r) {
+		w.b.WriteString(`\`)
+		w.b.WriteRune(r)
+		return
+	}
+	w.printable(r)
+}
+
+// printable writes r as it is if it is printable, or escaped otherwise.
+func (w *regexWriter) printable(r rune) {
+	switch {
+	case unicode.IsPrint(r):
+		w.b.WriteRune(r)
+	case r == '\n':
+		w.b.WriteString(`\n`)
+	case r == '\t':
+		w.b.WriteString(`\t`)
+	case w.dialect == RegexECMAScript:
+		w.b.WriteString(`\u{` + strconv.FormatInt(int64(r), 16) + `}`)
+	default:
+		w.b.WriteString(`\x{` + strconv.FormatInt(int64(r), 16) + `}`)
+	}
+}
+
+// charClass writes the character class consisting of the rune ranges, given
+// as pairs of their first and last runes, negating it if that is shorter.
+func (w *regexWriter) charClass(ranges []rune) {
+	switch {
+	case len(ranges) == 0:
+		w.b.WriteString(`[^\s\S]`)
+		return
+	case len(ranges) == 2 && ranges[0] == 0 && ranges[1] == unicode.MaxRune:
+		w.b.WriteString(`[\s\S]`)
+		return
+	}
+	w.b.WriteString(`[`)
+	if ranges[0] == 0 && ranges[len(ranges)-1] == unicode.MaxRune {
+		// Write the complement of the ranges.
+		w.b.WriteString(`^`)
+		var complement []rune
+		next := rune(0)
+		for i := 0; i < len(ranges); i += 2 {
+			if ranges[i] > next {
+				complement = append(complement, next, ranges[i]-1)
+			}
+			next = ranges[i+1] + 1
+		}
+		ranges = complement
+	}
+	for i := 0; i < len(ranges); i += 2 {
+		w.classRune(ranges[i])
+		if ranges[i+1] != ranges[i] {
+			if ranges[i+1] > ranges[i]+1 {
+				w.b.WriteString(`-`)
+			}
+			w.classRune(ranges[i+1])
+		}
+	}
+	w.b.WriteString(`]`)
+}
@@ -0,0 +1,72 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegexString(t *testing.T) {
+	tests := []struct {
+		pattern string
+		regex   bool
+		dialect RegexDialect
+		want    string
+	}{
+		{"*.log", false, RegexRE2, `^(?:.+/)?[^/]*\.log/?$`},
+		{"*.log", false, RegexPCRE, `^(?:.+\/)?[^\/]*\.log\/?\z`},
+		{"*.log", false, RegexECMAScript, `^(?:[^\n]+\/)?[^\/]*\.log\/?$`},
+		{"/build/", false, RegexECMAScript, `^build\/[^\n]*$`},
+		{"a[!b-d]c?", false, RegexPCRE, `^(?:.+\/)?a[^b-d]c[^\/]\/?\z`},
+		{`(?i)^(?P<dir>ab|c)+x{2,}/$`, true, RegexRE2, `(?i)^(?P<dir>ab|c)+x{2,}/$`},
+		{`(?i)^(?P<dir>ab|c)+x{2,}/$`, true, RegexPCRE, `^(?<dir>(?i:AB)|(?i:C))+(?i:X){2,}\/\z`},
+		{`(?i)^(?P<dir>ab|c)+x{2,}/$`, true, RegexECMAScript, `^(?<dir>[Aa][Bb]|[Cc])+[Xx]{2,}\/$`},
+		{`(?s)^a.*?b$`, true, RegexECMAScript, `^a[\s\S]*?b$`},
+	}
+	for _, test := range tests {
+		var p *Pattern
+		var err error
+		if test.regex {
+			p, err = NewRegexPattern(test.pattern)
+		} else {
+			p, err = NewPattern(test.pattern)
+		}
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		got, err := p.RegexString(test.dialect)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got != test.want {
+			t.Errorf("RegexString('%s', %s) returned '%s', want '%s'", test.pattern, test.dialect, got, test.want)
+		}
+	}
+
+	p, err := NewRegexPattern(`(?m)^a$`)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if _, err := p.RegexString(RegexECMAScript); !errors.Is(err, ErrUntranslatable) {
+		t.Errorf("RegexString('(?m)^a$', ecmascript) returned error '%v', want ErrUntranslatable", err)
+	}
+	if _, err := p.RegexString("posix"); err == nil {
+		t.Errorf("RegexString('(?m)^a$', posix) returned no error")
+	}
+}
@@ -0,0 +1,148 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PatternFactory compiles a single pattern line into a Matcher. The line has
+// already been trimmed, and a leading "!" negating the pattern has already
+// been removed.
+type PatternFactory func(line string) (Matcher, error)
+
+var (
+	patternFactoriesMu sync.RWMutex
+	patternFactories   = map[string]PatternFactory{
+		"gitwildmatch": func(line string) (Matcher, error) {
+			return NewPattern(line)
+		},
+		"dockerignore": func(line string) (Matcher, error) {
+			return newDockerPattern(line)
+		},
+		"regex": func(line string) (Matcher, error) {
+			return NewRegexPattern(line)
+		},
+		"braces": func(line string) (Matcher, error) {
+			return newBracePattern(line, Limits{})
+		},
+		"editorconfig": func(line string) (Matcher, error) {
+			return NewEditorConfigGlob(line)
+		},
+		"fnmatch": func(line string) (Matcher, error) {
+			return NewFnmatchPattern(line)
+		},
+		"wildmatch": func(line string) (Matcher, error) {
+			return newWildmatchPattern(line, false)
+		},
+		"minimatch": func(line string) (Matcher, error) {
+			return NewMinimatchPattern(line, MinimatchOptions{})
+		},
+	}
+)
+
+// RegisterPatternFactory makes a pattern syntax available under name for
+// FromLinesWithSyntax. The syntaxes "gitwildmatch", "dockerignore", "regex",
+// "braces", gitwildmatch with brace expansion, "editorconfig", "fnmatch",
+// "wildmatch", gitwildmatch matched by git's algorithm, and "minimatch" are
+// registered by default. If RegisterPatternFactory is called twice with the
+// same name or fn is nil, it panics.
+func RegisterPatternFactory(name string, fn PatternFactory) {
+	patternFactoriesMu.Lock()
+	defer patternFactoriesMu.Unlock()
+	if fn == nil {
+		panic("pathspec: RegisterPatternFactory factory is nil")
+	}
+	if _, dup := patternFactories[name]; dup {
+		panic("pathspec: RegisterPatternFactory called twice for syntax " + name)
+	}
+	patternFactories[name] = fn
+}
+
+// PatternSyntaxes returns a sorted list of the names of the registered
+// pattern syntaxes.
+func PatternSyntaxes() []string {
+	patternFactoriesMu.RLock()
+	defer patternFactoriesMu.RUnlock()
+	names := make([]string, 0, len(patternFactories))
+	for name := range patternFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FromLinesWithSyntax compiles a PathSpec from lines using the pattern syntax
+// registered as syntax. Blank lines and comments are skipped, and a leading
+// "!" negates a pattern, regardless of the syntax.
+func FromLinesWithSyntax(syntax string, lines ...string) (*PathSpec, error) {
+	factory, err := lookupPatternFactory(syntax)
+	if err != nil {
+		return nil, err
+	}
+	return compileLines("", lines, func(line string) (*Pattern, error) {
+		return newFactoryPattern(syntax, factory, line)
+	})
+}
+
+// NewPatternWithSyntax compiles a single pattern using the pattern syntax
+// registered as syntax. A leading "!" negates the pattern. Together with
+// NewPathSpec it allows mixing syntaxes within a PathSpec.
+func NewPatternWithSyntax(syntax, line string) (*Pattern, error) {
+	factory, err := lookupPatternFactory(syntax)
+	if err != nil {
+		return nil, err
+	}
+	return newFactoryPattern(syntax, factory, line)
+}
+
+// lookupPatternFactory returns the factory registered as syntax.
+func lookupPatternFactory(syntax string) (PatternFactory, error) {
+	patternFactoriesMu.RLock()
+	defer patternFactoriesMu.RUnlock()
+	factory, ok := patternFactories[syntax]
+	if !ok {
+		return nil, fmt.Errorf("unknown pattern syntax %q", syntax)
+	}
+	return factory, nil
+}
+
+// newFactoryPattern compiles line with the factory registered as syntax into
+// a Pattern.
+func newFactoryPattern(syntax string, factory PatternFactory, line string) (*Pattern, error) {
+	pattern := line
+	negate := strings.HasPrefix(pattern, "!")
+	if negate {
+		pattern = pattern[1:]
+	}
+	m, err := factory(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := m.(*Pattern); ok {
+		p.syntax = syntax
+		p.text = line
+		p.negate = negate
+		p.factory = true
+		return p, nil
+	}
+	return &Pattern{syntax: syntax, text: line, negate: negate, matcher: m, factory: true}, nil
+}
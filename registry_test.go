@@ -0,0 +1,109 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+)
+
+// suffixMatcher matches names ending with a suffix.
+type suffixMatcher string
+
+func (m suffixMatcher) Match(name string) bool {
+	return strings.HasSuffix(name, string(m))
+}
+
+func TestFromLinesWithSyntax(t *testing.T) {
+	RegisterPatternFactory("test-suffix", func(line string) (Matcher, error) {
+		return suffixMatcher(line), nil
+	})
+
+	tests := []struct {
+		syntax    string
+		lines     []string
+		toInclude []string
+		toIgnore  []string
+	}{
+		{"gitwildmatch", []string{"*.log", "!keep.log"}, []string{"keep.log", "a/keep.log"}, []string{"a.log", "a/b.log"}},
+		{"dockerignore", []string{"*.log", "!keep.log"}, []string{"keep.log", "a/b.log"}, []string{"a.log"}},
+		{"test-suffix", []string{"~", "!important~"}, []string{"a/important~", "b.txt"}, []string{"a/b~"}},
+	}
+
+	for _, test := range tests {
+		ps, err := FromLinesWithSyntax(test.syntax, test.lines...)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+
+		for _, f := range test.toInclude {
+			if ps.Match(f) {
+				t.Errorf("FromLinesWithSyntax(%s, '%s').Match(%s) returned 'true', want 'false'", test.syntax, test.lines, f)
+			}
+		}
+
+		for _, f := range test.toIgnore {
+			if !ps.Match(f) {
+				t.Errorf("FromLinesWithSyntax(%s, '%s').Match(%s) returned 'false', want 'true'", test.syntax, test.lines, f)
+			}
+		}
+	}
+
+	if _, err := FromLinesWithSyntax("unknown", "foo"); err == nil {
+		t.Errorf("FromLinesWithSyntax(unknown) returned no error, want one")
+	}
+}
+
+func TestNewPatternWithSyntax(t *testing.T) {
+	lines := [][2]string{
+		{"gitwildmatch", "*.log"},
+		{"regex", `^logs/[0-9]+\.log$`},
+		{"regex", "!^logs/1"},
+	}
+	toInclude := []string{"logs/1.log", "logs/12.log", "logs/1.txt"}
+	toIgnore := []string{"a.log", "logs/2.log", "logs/23.log"}
+
+	var patterns []*Pattern
+	for _, line := range lines {
+		p, err := NewPatternWithSyntax(line[0], line[1])
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		patterns = append(patterns, p)
+	}
+	ps := NewPathSpec(patterns...)
+
+	for _, f := range toInclude {
+		if ps.Match(f) {
+			t.Errorf("Match('%s', %s) returned 'true', want 'false'", lines, f)
+		}
+	}
+
+	for _, f := range toIgnore {
+		if !ps.Match(f) {
+			t.Errorf("Match('%s', %s) returned 'false', want 'true'", lines, f)
+		}
+	}
+
+	if patterns[1].Regex() == nil || patterns[1].Regex().String() != `^logs/[0-9]+\.log$` {
+		t.Errorf("NewPatternWithSyntax(regex).Regex() returned '%v', want the expression itself", patterns[1].Regex())
+	}
+	if _, err := NewPatternWithSyntax("regex", "[a-"); err == nil {
+		t.Errorf("NewPatternWithSyntax(regex, [a-) returned no error, want one")
+	}
+}
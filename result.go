@@ -0,0 +1,153 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// MatchResult describes the pattern which decided about a path, like
+// "git check-ignore --verbose" does.
+type MatchResult struct {
+	// Pattern is the deciding pattern.
+	Pattern *Pattern
+	// Text is the pattern as it was written.
+	Text string
+	// Source is the name of the file the pattern was read from, if known.
+	Source string
+	// Line is the line number of the pattern in Source, if known.
+	Line int
+	// Index is the position of the pattern in the Patterns of its
+	// PathSpec. For a GitIgnoreTree, that is the PathSpec compiled from
+	// the ignore files of the directory holding the pattern.
+	Index int
+	// Negate is true if the pattern re-included the path.
+	Negate bool
+	// Dir is true if the pattern matched the path as a directory, either
+	// because the path is a directory, or because the pattern only matches
+	// directories and the path lies inside one.
+	Dir bool
+	// Detail tells whether the pattern matched the path itself or only a
+	// parent directory of it.
+	Detail MatchDetail
+	// Parent is the slash-separated parent directory the pattern matched,
+	// with a trailing slash, if Detail is DetailParent.
+	Parent string
+}
+
+// newMatchResult describes the match of the pattern p on the slash-separated
+// path name, where matched is either name or the parent directory of name p
+// matched, and i is the position of p in its PathSpec.
+func newMatchResult(p *Pattern, i int, name, matched string) *MatchResult {
+	r := &MatchResult{
+		Pattern: p,
+		Text:    p.text,
+		Source:  p.source,
+		Line:    p.line,
+		Index:   i,
+		Negate:  p.negate,
+		Dir:     p.dir || strings.HasSuffix(matched, "/"),
+		Detail:  DetailSelf,
+	}
+	if matched != name {
+		r.Detail = DetailParent
+		r.Parent = matched
+	}
+	return r
+}
+
+// MatchDetail tells whether a pattern matched a path itself, or only as the
+// content of a matching parent directory.
+type MatchDetail int
+
+const (
+	// DetailNone means the pattern did not match the path.
+	DetailNone MatchDetail = iota
+	// DetailSelf means the pattern matched the path itself.
+	DetailSelf
+	// DetailParent means a directory pattern, like "build/", matched a
+	// parent directory of the path, so the path only matched as part of
+	// that directory. Archive and sync tools can then skip the whole
+	// directory instead of recursing into it.
+	DetailParent
+)
+
+// String returns a human readable name of the detail.
+func (d MatchDetail) String() string {
+	switch d {
+	case DetailSelf:
+		return "self"
+	case DetailParent:
+		return "parent"
+	default:
+		return "none"
+	}
+}
+
+// MatchDetail reports how the pattern matches name, regardless of negation.
+// Directories are denoted by a trailing slash, e.g. "build/". If a directory
+// pattern matches both a directory and one of its parents, like "**/a/" does
+// "a/a/", DetailParent is returned, since the parent covers the directory.
+func (p *Pattern) MatchDetail(name string) MatchDetail {
+	name = filepath.ToSlash(name)
+	if !p.match(name) {
+		return DetailNone
+	}
+	if p.matchedPath(name) != name {
+		return DetailParent
+	}
+	return DetailSelf
+}
+
+// matchedPath returns the shortest parent directory of the slash-separated
+// path name, with a trailing slash, matched by the directory pattern p, or
+// name if p is no directory pattern or matches no parent. p must match name.
+func (p *Pattern) matchedPath(name string) string {
+	if !p.dir {
+		return name
+	}
+	trimmed := strings.TrimSuffix(name, "/")
+	for i := strings.IndexByte(trimmed, '/'); i >= 0; i = nextSlash(trimmed, i) {
+		if p.match(name[:i+1]) {
+			return name[:i+1]
+		}
+	}
+	return name
+}
+
+// State returns the decision about the path.
+func (r *MatchResult) State() MatchState {
+	return patternState(r.Pattern)
+}
+
+// MatchP returns details about the last pattern matching name, which decides
+// about it, or nil if no pattern matched or name is tracked, see SetTracked.
+// Directories are denoted by a trailing slash, e.g. "build/".
+func (ps *PathSpec) MatchP(name string) *MatchResult {
+	name = ps.slashPath(name)
+	if isTracked(ps.tracked, name) {
+		return nil
+	}
+	i := ps.lastMatchIndex(name)
+	if i < 0 {
+		return nil
+	}
+	p := ps.patterns[i]
+	return newMatchResult(p, i, name, p.matchedPath(name))
+}
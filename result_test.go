@@ -0,0 +1,119 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPathSpecMatchP(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ".gitignore")
+	if err := os.WriteFile(name, []byte("# logs\n*.log\n\nbuild/\n!keep.log\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	ps, err := FromFile(name)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := map[string]MatchResult{
+		"debug.log":   {Text: "*.log", Source: name, Line: 2, Detail: DetailSelf},
+		"logs/":       {},
+		"keep.log":    {Text: "!keep.log", Source: name, Line: 5, Index: 2, Negate: true, Detail: DetailSelf},
+		"build/out.o": {Text: "build/", Source: name, Line: 4, Index: 1, Dir: true, Detail: DetailParent, Parent: "build/"},
+		"a.log/":      {Text: "*.log", Source: name, Line: 2, Dir: true, Detail: DetailSelf},
+	}
+
+	for f, want := range tests {
+		result := ps.MatchP(f)
+		if want.Text == "" {
+			if result != nil {
+				t.Errorf("MatchP(%s) returned '%+v', want 'nil'", f, result)
+			}
+			continue
+		}
+		if result == nil {
+			t.Errorf("MatchP(%s) returned 'nil', want '%+v'", f, want)
+			continue
+		}
+		want.Pattern = result.Pattern
+		if *result != want {
+			t.Errorf("MatchP(%s) returned '%+v', want '%+v'", f, *result, want)
+		}
+	}
+}
+
+func TestGitIgnoreTreeMatchP(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.o\nvendor/\n")},
+		"src/.gitignore": {Data: []byte("\n*.gen.go\n")},
+	}
+
+	tree, err := NewGitIgnoreTree(fsys)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := map[string]MatchResult{
+		"src/a.gen.go":        {Text: "*.gen.go", Source: "src/.gitignore", Line: 2, Detail: DetailSelf},
+		"vendor/src/a.go":     {Text: "vendor/", Source: ".gitignore", Line: 2, Index: 1, Dir: true, Detail: DetailParent, Parent: "vendor/"},
+		"src/vendor/x.gen.go": {Text: "vendor/", Source: ".gitignore", Line: 2, Index: 1, Dir: true, Detail: DetailParent, Parent: "src/vendor/"},
+	}
+
+	for f, want := range tests {
+		result := tree.MatchP(f)
+		if result == nil {
+			t.Errorf("GitIgnoreTree.MatchP(%s) returned 'nil', want '%+v'", f, want)
+			continue
+		}
+		want.Pattern = result.Pattern
+		if *result != want {
+			t.Errorf("GitIgnoreTree.MatchP(%s) returned '%+v', want '%+v'", f, *result, want)
+		}
+	}
+}
+
+func TestPatternMatchDetail(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    MatchDetail
+	}{
+		{"build/", "build/", DetailSelf},
+		{"build/", "a/build/", DetailSelf},
+		{"build/", "build/out.o", DetailParent},
+		{"build/", "a/build/x/", DetailParent},
+		{"build/", "build", DetailNone},
+		{"*.log", "a/debug.log", DetailSelf},
+		{"*.log", "a.log/", DetailSelf},
+		{"**/a/", "a/a/", DetailParent},
+		{"/docs", "docs/index.md", DetailNone},
+	}
+	for _, test := range tests {
+		p, err := NewPattern(test.pattern)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := p.MatchDetail(test.name); got != test.want {
+			t.Errorf("MatchDetail('%s', '%s') returned '%v', want '%v'", test.pattern, test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RootedSpec matches absolute paths against a PathSpec whose patterns are
+// relative to a root directory, like the directory containing a gitignore
+// file. It is created with NewRootedSpec.
+type RootedSpec struct {
+	root string
+	spec *PathSpec
+}
+
+// NewRootedSpec binds spec to the directory root. A relative root is made
+// absolute with filepath.Abs.
+func NewRootedSpec(root string, spec *PathSpec) (*RootedSpec, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	return &RootedSpec{root: root, spec: spec}, nil
+}
+
+// Root returns the absolute root directory.
+func (rs *RootedSpec) Root() string {
+	return rs.root
+}
+
+// Spec returns the PathSpec matched against root-relative paths.
+func (rs *RootedSpec) Spec() *PathSpec {
+	return rs.spec
+}
+
+// Rel returns the slash-separated path of name relative to the root, with a
+// trailing slash if name has one. name is cleaned lexically, resolving "."
+// and ".." segments without following symbolic links. Relative names are
+// relative to the root. If name does not resolve to the root or below it,
+// Rel returns an error wrapping ErrOutsideRoot. The root itself is ".".
+func (rs *RootedSpec) Rel(name string) (string, error) {
+	isDir := strings.HasSuffix(name, "/") || strings.HasSuffix(name, string(os.PathSeparator))
+	abs := name
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(rs.root, abs)
+	}
+	rel, err := filepath.Rel(rs.root, filepath.Clean(abs))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("path %q: %w", name, ErrOutsideRoot)
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		return rel, nil
+	}
+	return dirName(rel, isDir), nil
+}
+
+// Match reports whether name is ignored by the spec. Directories are denoted
+// by a trailing path separator. The root itself is never ignored.
+func (rs *RootedSpec) Match(name string) (bool, error) {
+	state, err := rs.MatchState(name)
+	return state == StateIgnored, err
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing separator for directories.
+func (rs *RootedSpec) MatchPath(name string, isDir bool) (bool, error) {
+	rel, err := rs.Rel(name)
+	if err != nil || rel == "." {
+		return false, err
+	}
+	return rs.spec.MatchPath(rel, isDir), nil
+}
+
+// MatchState is like Match, but distinguishes names no pattern matched from
+// names a negated pattern explicitly re-included.
+func (rs *RootedSpec) MatchState(name string) (MatchState, error) {
+	rel, err := rs.Rel(name)
+	if err != nil || rel == "." {
+		return StateUnmatched, err
+	}
+	return rs.spec.MatchState(rel), nil
+}
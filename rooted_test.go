@@ -0,0 +1,95 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestRootedSpec(t *testing.T) {
+	root := t.TempDir()
+	ps, err := FromLines("/build/", "*.log", "!keep.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	rs, err := NewRootedSpec(root, ps)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		want    MatchState
+		outside bool
+	}{
+		{filepath.Join(root, "build", "out.o"), StateIgnored, false},
+		{filepath.Join(root, "src", "build", "out.o"), StateUnmatched, false},
+		{filepath.Join(root, "src", "..", "build") + string(filepath.Separator), StateIgnored, false},
+		{filepath.Join(root, "a.log"), StateIgnored, false},
+		{filepath.Join(root, "keep.log"), StateIncluded, false},
+		{filepath.Join("src", ".", "debug.log"), StateIgnored, false},
+		{root, StateUnmatched, false},
+		{filepath.Join(root, "..", "build", "out.o"), StateUnmatched, true},
+		{filepath.Join(root+"sibling", "a.log"), StateUnmatched, true},
+		{filepath.Join("..", "a.log"), StateUnmatched, true},
+	}
+	for _, test := range tests {
+		got, err := rs.MatchState(test.name)
+		if test.outside {
+			if !errors.Is(err, ErrOutsideRoot) {
+				t.Errorf("MatchState('%s') returned error '%v', want '%v'", test.name, err, ErrOutsideRoot)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got != test.want {
+			t.Errorf("MatchState('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRootedSpecRel(t *testing.T) {
+	root := t.TempDir()
+	rs, err := NewRootedSpec(root, NewPathSpec())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{root, "."},
+		{filepath.Join(root, "a", "b"), "a/b"},
+		{filepath.Join(root, "a", "..", "b") + string(filepath.Separator), "b/"},
+		{filepath.Join("a", "b"), "a/b"},
+	}
+	for _, test := range tests {
+		got, err := rs.Rel(test.name)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got != test.want {
+			t.Errorf("Rel('%s') returned '%s', want '%s'", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// RsyncFilter is a compiled list of rsync filter rules, see
+// FromRsyncFilter.
+type RsyncFilter struct {
+	// Spec matches paths against the include and exclude rules. Include
+	// rules are negated patterns, so Spec.Match reports whether rsync
+	// excludes a path.
+	Spec *PathSpec
+	// DirMerge lists the names of the per-directory merge files declared
+	// by "dir-merge" rules, like ".rsync-filter", in order. Reading them is
+	// left to the caller.
+	DirMerge []string
+}
+
+// FromRsyncFilter compiles rsync filter rules, as passed to rsync with
+// --filter or read from a filter file. The supported rules are "+" and
+// "include", "-" and "exclude", "S" and "show", "H" and "hide", "!" and
+// "clear", which drops all preceding rules, and "dir-merge" or ":". Rule
+// modifiers are not supported. The rule and its pattern are separated by a
+// space or an underscore. Blank lines and lines starting with "#" or ";" are
+// comments.
+//
+// Unlike gitignore, the first matching rsync rule decides, so the rules are
+// stored in reverse order in the resulting PathSpec. Patterns follow rsync's
+// rules:
+//
+// A leading slash anchors the pattern at the root of the transfer, otherwise
+// it matches at the end of the path. A trailing slash matches directories
+// only. "*" matches within a path segment, "**" across segments, and a
+// trailing "dir/***" matches "dir" as well as everything beneath it. A
+// backslash escapes the next character only if the pattern contains a
+// wildcard.
+//
+// A rule only matches the path itself. Like rsync, callers must not descend
+// into excluded directories, which Walk and MatchGit take care of.
+func FromRsyncFilter(r io.Reader) (*RsyncFilter, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return RsyncFilterFromLines(lines...)
+}
+
+// RsyncFilterFromLines is like FromRsyncFilter, but takes the rules as lines.
+func RsyncFilterFromLines(lines ...string) (*RsyncFilter, error) {
+	f := &RsyncFilter{}
+	var patterns []*Pattern
+	var errs ParseErrors
+	for i, line := range lines {
+		rule := strings.TrimSpace(line)
+		if rule == "" || rule[0] == '#' || rule[0] == ';' {
+			continue
+		}
+		name, arg := rule, ""
+		if j := strings.IndexAny(rule, " _"); j >= 0 {
+			name, arg = rule[:j], rule[j+1:]
+		}
+		var include bool
+		switch name {
+		case "+", "include", "S", "show":
+			include = true
+		case "-", "exclude", "H", "hide":
+		case "!", "clear":
+			patterns = patterns[:0]
+			f.DirMerge = f.DirMerge[:0]
+			continue
+		case ":", "dir-merge":
+			f.DirMerge = append(f.DirMerge, arg)
+			continue
+		default:
+			err := fmt.Errorf("unsupported rsync filter rule %q", name)
+			errs = append(errs, &ParseError{Line: i + 1, Pattern: rule, Err: err})
+			continue
+		}
+		p, err := newRsyncPattern(arg, include)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: i + 1, Pattern: rule, Err: err})
+			continue
+		}
+		p.text = rule
+		p.line = i + 1
+		patterns = append(patterns, p)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// The first matching rsync rule decides, the last matching pattern of
+	// a PathSpec does.
+	for i, j := 0, len(patterns)-1; i < j; i, j = i+1, j-1 {
+		patterns[i], patterns[j] = patterns[j], patterns[i]
+	}
+	f.Spec = NewPathSpec(patterns...)
+	return f, nil
+}
+
+// Match reports whether rsync excludes name. Directories are denoted by a
+// trailing slash.
+func (f *RsyncFilter) Match(name string) bool {
+	return f.Spec.Match(name)
+}
+
+// newRsyncPattern compiles a single rsync filter pattern. include rules are
+// returned as negated patterns.
+func newRsyncPattern(pattern string, include bool) (*Pattern, error) {
+	if pattern == "" {
+		return nil, ErrEmptyPattern
+	}
+	dir := strings.HasSuffix(pattern, "/")
+	body := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(body, "/")
+	body = strings.TrimPrefix(body, "/")
+	// "dir/***" matches "dir" and everything beneath it.
+	contents := strings.HasSuffix(body, "/***")
+	body = strings.TrimSuffix(body, "/***")
+
+	var expr bytes.Buffer
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	if strings.ContainsAny(body, "*?[") {
+		expr.WriteString(translateRsyncGlob(body))
+	} else {
+		expr.WriteString(regexp.QuoteMeta(body))
+	}
+	switch {
+	case contents:
+		expr.WriteString("(?:/.*)?$")
+	case dir:
+		expr.WriteString("/$")
+	default:
+		expr.WriteString("/?$")
+	}
+
+	regex, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{syntax: "rsync", text: pattern, negate: include, regex: regex, dir: dir}, nil
+}
+
+// translateRsyncGlob translates an rsync wildcard pattern into a regular
+// expression. Unlike in gitignore, "**" matches across path segments
+// anywhere in the pattern.
+func translateRsyncGlob(glob string) string {
+	var regex bytes.Buffer
+	for i := 0; i < len(glob); i++ {
+		switch char := glob[i]; {
+		case char == '\\' && i+1 < len(glob):
+			i++
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case char == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			for i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+			}
+			regex.WriteString(".*")
+		case char == '*' && (i == 0 || glob[i-1] == '/') && (i+1 == len(glob) || glob[i+1] == '/'):
+			// A whole path segment is never empty, which keeps "dir/*"
+			// from matching "dir/", the directory itself.
+			regex.WriteString("[^/]+")
+		case char == '*':
+			regex.WriteString("[^/]*")
+		case char == '?':
+			regex.WriteString("[^/]")
+		case char == '[':
+			regex.WriteString(translateBracketExpression(&i, glob))
+		default:
+			regex.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return regex.String()
+}
+
+// ToRsyncArgs translates the gitignore patterns of the PathSpec into rsync
+// --exclude and --include arguments, like "--exclude=*.log", which make rsync
+// skip the paths MatchGit ignores. Since the first matching rsync rule
+// decides, the arguments are in reverse order of the patterns. Negated
+// patterns become --include arguments, and a pattern with inner "**"
+// segments, which match zero or more directories in gitignore but at least
+// one in rsync, becomes one argument per combination of leaving them out.
+//
+// The translation has caveats: patterns of other syntaxes than gitignore and
+// disabled patterns are left out; an escaped character in a pattern without
+// wildcards is unescaped, since rsync only honors escapes in patterns with
+// wildcards; and rsync only sees the patterns, so the arguments must be
+// passed before any other filter rules of the caller. The arguments are not
+// quoted for a shell.
+func ToRsyncArgs(ps *PathSpec) []string {
+	var args []string
+	patterns := ps.active()
+	for i := len(patterns) - 1; i >= 0; i-- {
+		p := patterns[i]
+		if p.syntax != "gitwildmatch" || p.matcher != nil {
+			continue
+		}
+		flag := "--exclude="
+		text := p.text
+		if p.negate {
+			flag, text = "--include=", text[1:]
+		}
+		segs, _, err := NormalizePattern(text)
+		if err != nil {
+			continue
+		}
+		for _, rule := range rsyncRules(segs, p.dir) {
+			args = append(args, flag+rule)
+		}
+	}
+	return args
+}
+
+// rsyncRules translates the normalized segments of a gitignore pattern into
+// rsync patterns. dir is true if the pattern ends with a slash.
+func rsyncRules(segs []string, dir bool) []string {
+	prefix := "/"
+	if segs[0] == "**" && len(segs) > 1 {
+		// Rsync matches patterns not starting with a slash at the end
+		// of the path.
+		prefix, segs = "", segs[1:]
+	}
+	suffix := ""
+	switch last := len(segs) - 1; {
+	case dir:
+		suffix, segs = "/", segs[:last]
+	case segs[last] == "**":
+		// Everything beneath the directory: rsync does not descend
+		// into excluded directories, so matching the children
+		// suffices.
+		segs = append(segs[:last:last], "*")
+	}
+	if len(segs) == 0 {
+		return []string{"*"}
+	}
+
+	rules := []string{""}
+	for i, seg := range segs {
+		var next []string
+		for _, rule := range rules {
+			if seg == "**" {
+				// Leave out the inner "**" to match no directory.
+				next = append(next, rule)
+			}
+			if i > 0 {
+				rule += "/"
+			}
+			next = append(next, rule+seg)
+		}
+		rules = next
+	}
+	for i, rule := range rules {
+		rule = strings.TrimPrefix(rule, "/")
+		if !strings.ContainsAny(rule, "*?[") {
+			rule = unescapeGlob(rule)
+		}
+		rules[i] = prefix + rule + suffix
+	}
+	return rules
+}
+
+// unescapeGlob removes the backslashes escaping characters from glob.
+func unescapeGlob(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		if glob[i] == '\\' && i+1 < len(glob) {
+			i++
+		}
+		b.WriteByte(glob[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRsyncFilter(t *testing.T) {
+	rules := `# sync sources only
++ /src/***
++ *.md
+- /build/
+exclude *.o
+- **/tmp/**
+: .rsync-filter
++ */
+- *
+`
+	f, err := FromRsyncFilter(strings.NewReader(rules))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"src", false},
+		{"src/a/main.o", false},
+		{"README.md", false},
+		{"docs/guide.md", false},
+		{"build/", true},
+		{"docs/build/", false},
+		{"lib/", false},
+		{"lib/x.o", true},
+		{"lib/tmp/x.c", true},
+		{"lib/x.c", true},
+		{"go.mod", true},
+	}
+	for _, test := range tests {
+		if got := f.Match(test.name); got != test.want {
+			t.Errorf("RsyncFilter.Match('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+	if len(f.DirMerge) != 1 || f.DirMerge[0] != ".rsync-filter" {
+		t.Errorf("RsyncFilter.DirMerge is '%v', want '[.rsync-filter]'", f.DirMerge)
+	}
+	if p := f.Spec.MatchP("go.mod"); p == nil || p.Line != 9 || p.Text != "- *" {
+		t.Errorf("RsyncFilter.Spec.MatchP('go.mod') returned '%+v', want rule '- *' in line 9", p)
+	}
+}
+
+func TestRsyncFilterClear(t *testing.T) {
+	f, err := RsyncFilterFromLines("- *.o", "!", "- *.a")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if f.Match("x.o") || !f.Match("x.a") {
+		t.Errorf("RsyncFilter did not clear the rules before '!'")
+	}
+}
+
+func TestRsyncFilterEscape(t *testing.T) {
+	f, err := RsyncFilterFromLines(`- a\*b`, `- c\d*`, `- e\f`)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	tests := map[string]bool{
+		"a*b":  true,
+		`a\*b`: false,
+		"cd":   true,
+		"cdx":  true,
+		`e\f`:  true,
+		"ef":   false,
+	}
+	for name, want := range tests {
+		if got := f.Match(name); got != want {
+			t.Errorf("RsyncFilter.Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
+
+func TestRsyncFilterErrors(t *testing.T) {
+	_, err := RsyncFilterFromLines("- a", "merge other.rules", "P protected", "+")
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("RsyncFilterFromLines() returned '%v', want ParseErrors", err)
+	}
+	if len(errs) != 3 || errs[0].Line != 2 || errs[1].Line != 3 || errs[2].Line != 4 {
+		t.Errorf("RsyncFilterFromLines() returned '%v', want errors in lines 2, 3 and 4", err)
+	}
+}
+
+func TestToRsyncArgs(t *testing.T) {
+	lines := []string{"*.log", "!keep.log", "build/", "/docs/**/index.md", "vendor/**", "!vendor/keep/", "/a\\ b", "**/tmp/*"}
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	got := ToRsyncArgs(ps)
+	want := []string{
+		"--exclude=tmp/*",
+		"--exclude=/a b",
+		"--include=vendor/keep/",
+		"--exclude=vendor/*",
+		"--exclude=/docs/index.md",
+		"--exclude=/docs/**/index.md",
+		"--exclude=build/",
+		"--include=keep.log",
+		"--exclude=*.log",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToRsyncArgs('%v') returned '%v', want '%v'", lines, got, want)
+	}
+
+	var rules []string
+	for _, arg := range got {
+		rule := strings.Replace(arg, "--exclude=", "- ", 1)
+		rules = append(rules, strings.Replace(rule, "--include=", "+ ", 1))
+	}
+	f, err := RsyncFilterFromLines(rules...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for _, name := range []string{
+		"debug.log", "logs/keep.log", "build/", "build/x", "src/build/", "docs/index.md",
+		"docs/a/b/index.md", "src/docs/index.md", "vendor/", "vendor/x.go", "vendor/keep/",
+		"vendor/keep/x.go", "a b", "x/a b", "tmp/", "tmp/x", "src/tmp/y/", "main.go",
+	} {
+		if got, want := f.Spec.MatchGit(name), ps.MatchGit(name); got != want {
+			t.Errorf("ToRsyncArgs('%v') excludes '%s' '%v', want '%v'", lines, name, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SafeSpec holds a PathSpec which can be replaced while other goroutines are
+// matching against it, so long-running programs can reload their ignore
+// rules without stopping. Matching goroutines call Load, or the match
+// methods of SafeSpec, and always see either the old or the new spec, never
+// a mix. The zero value holds no spec and matches nothing.
+type SafeSpec struct {
+	// mu serializes Store and Update, so concurrent updates are not lost.
+	mu sync.Mutex
+	v  atomic.Value // *PathSpec
+}
+
+// NewSafeSpec returns a SafeSpec holding ps.
+func NewSafeSpec(ps *PathSpec) *SafeSpec {
+	s := &SafeSpec{}
+	s.Store(ps)
+	return s
+}
+
+// Load returns the current spec, or nil if none was stored. The spec must
+// not be modified, since other goroutines may be matching against it.
+func (s *SafeSpec) Load() *PathSpec {
+	ps, _ := s.v.Load().(*PathSpec)
+	return ps
+}
+
+// Store replaces the current spec by ps. Goroutines which already loaded the
+// old spec keep using it until they load again.
+func (s *SafeSpec) Store(ps *PathSpec) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.v.Store(ps)
+}
+
+// Update replaces the current spec by the result of f, which is called with
+// the current spec, or nil if none was stored, and returns the new spec.
+// Calls of Update are serialized. f must not modify its argument, but return
+// a new spec, e.g. one built with Merge.
+func (s *SafeSpec) Update(f func(*PathSpec) *PathSpec) *PathSpec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ps := f(s.Load())
+	s.v.Store(ps)
+	return ps
+}
+
+// Match is like PathSpec.Match of the current spec.
+func (s *SafeSpec) Match(name string) bool {
+	return s.MatchState(name) == StateIgnored
+}
+
+// MatchPath is like PathSpec.MatchPath of the current spec.
+func (s *SafeSpec) MatchPath(name string, isDir bool) bool {
+	return s.Match(dirName(name, isDir))
+}
+
+// MatchState is like PathSpec.MatchState of the current spec.
+func (s *SafeSpec) MatchState(name string) MatchState {
+	ps := s.Load()
+	if ps == nil {
+		return StateUnmatched
+	}
+	return ps.MatchState(name)
+}
+
+// Decide is like PathSpec.Decide of the current spec.
+func (s *SafeSpec) Decide(name string) Decision {
+	return s.MatchState(name)
+}
@@ -0,0 +1,85 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSafeSpec(t *testing.T) {
+	var s SafeSpec
+	if s.Load() != nil || s.Match("a.o") {
+		t.Errorf("The zero SafeSpec holds a spec")
+	}
+
+	ps, err := FromLines("*.o")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	s.Store(ps)
+	if !s.Match("a.o") || s.Load() != ps {
+		t.Errorf("SafeSpec does not hold the stored spec")
+	}
+
+	s.Update(func(old *PathSpec) *PathSpec {
+		other, err := FromLines("!keep.o")
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		return Merge(old, other)
+	})
+	if got := s.MatchState("keep.o"); got != StateIncluded {
+		t.Errorf("MatchState('keep.o') returned '%v', want '%v'", got, StateIncluded)
+	}
+	if got := ps.MatchState("keep.o"); got != StateIgnored {
+		t.Errorf("Update() modified the old spec")
+	}
+}
+
+func TestSafeSpecConcurrent(t *testing.T) {
+	ignoreAll, err := FromLines("*")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	s := NewSafeSpec(ignoreAll)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				// Every stored spec ignores "a.o".
+				if !s.Match("a.o") {
+					t.Errorf("Match('a.o') returned 'false' during an update")
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		s.Update(func(old *PathSpec) *PathSpec {
+			return Merge(old)
+		})
+	}
+	wg.Wait()
+	if got := len(s.Load().Patterns()); got != 1 {
+		t.Errorf("Patterns() returned %d patterns, want 1", got)
+	}
+}
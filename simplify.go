@@ -0,0 +1,201 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import "strings"
+
+// Simplify returns a copy of the PathSpec without redundant patterns, and the
+// changes made, in the order of the patterns. Besides the changes of
+// Normalize, it removes patterns subsumed by another pattern with the same
+// negation, which matches all of their paths: a pattern subsumed by a later
+// one never decides, and a pattern subsumed by an earlier one is removed if
+// no pattern of the opposite negation in between may match its paths. The
+// copy therefore decides like the PathSpec about every path, including
+// negations, both with Match and MatchGit.
+//
+// Subsumption is only detected between gitignore patterns, from their path
+// segments, so Simplify is conservative: "*.log" subsumes "debug.log" and
+// "/logs/*.log", and "build/" subsumes "/build/foo.txt" and "build/tmp/",
+// but "*.log" does not subsume "debug*.log". Disabled patterns are left out
+// of the copy, which converts names and calls hooks like the PathSpec, see
+// Merge.
+func Simplify(ps *PathSpec) (*PathSpec, []NormalizeChange) {
+	simple := Merge(ps)
+	changes := simple.Normalize()
+
+	patterns := simple.patterns
+	segments := make([][]string, len(patterns))
+	for i, p := range patterns {
+		segments[i] = subsumptionSegments(p)
+	}
+	removed := make([]bool, len(patterns))
+	for i, p := range patterns {
+		if segments[i] == nil {
+			continue
+		}
+		if j := subsumingPattern(patterns, segments, removed, i); j >= 0 {
+			removed[i] = true
+			changes = append(changes, NormalizeChange{Kind: ChangeSubsumed, Pattern: p, Replacement: patterns[j]})
+		}
+	}
+	kept := patterns[:0:0]
+	for i, p := range patterns {
+		if !removed[i] {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) < len(patterns) {
+		simple.setPatterns(kept)
+	}
+	return simple, changes
+}
+
+// subsumingPattern returns the position of a pattern which is not removed and
+// makes the pattern at position i redundant, or -1. Later patterns are
+// preferred.
+func subsumingPattern(patterns []*Pattern, segments [][]string, removed []bool, i int) int {
+	p := patterns[i]
+	for j := len(patterns) - 1; j > i; j-- {
+		if !removed[j] && subsumes(patterns[j], segments[j], p, segments[i]) {
+			return j
+		}
+	}
+	for j := i - 1; j >= 0; j-- {
+		q := patterns[j]
+		if removed[j] {
+			continue
+		}
+		if q.negate != p.negate {
+			// The pattern in between may decide differently about
+			// the paths of p, once p is removed.
+			if segments[j] == nil || overlapsSegments(segments[j], segments[i]) {
+				return -1
+			}
+			continue
+		}
+		if subsumes(q, segments[j], p, segments[i]) {
+			return j
+		}
+	}
+	return -1
+}
+
+// subsumptionSegments returns the normalized segments of the gitignore
+// pattern p, or nil if p is of another syntax. A trailing "**" segment of a
+// directory pattern, which also matches the directory itself, is written as
+// "/**" to tell it from a written trailing "**", which does not.
+func subsumptionSegments(p *Pattern) []string {
+	if p.syntax != "gitwildmatch" || p.matcher != nil || !p.hasRegex() {
+		return nil
+	}
+	text := p.text
+	if p.negate {
+		text = text[1:]
+	}
+	segs, _, err := NormalizePattern(text)
+	if err != nil {
+		return nil
+	}
+	if p.dir {
+		segs[len(segs)-1] = "/**"
+	}
+	return segs
+}
+
+// subsumes reports whether the pattern q with the subsumption segments qs
+// matches every path the pattern p with the segments ps matches.
+func subsumes(q *Pattern, qs []string, p *Pattern, ps []string) bool {
+	if qs == nil || q.negate != p.negate || isFolded(q) != isFolded(p) {
+		return false
+	}
+	return coversSegments(qs, ps)
+}
+
+// isFolded reports whether the pattern p matches case-insensitively.
+func isFolded(p *Pattern) bool {
+	return strings.HasPrefix(p.regexString(), "(?i)")
+}
+
+// coversSegments reports whether the subsumption segments qs match every
+// path the segments ps match.
+func coversSegments(qs, ps []string) bool {
+	if len(qs) == 0 {
+		return len(ps) == 0
+	}
+	switch q := qs[0]; {
+	case q == "**" && len(qs) > 1:
+		// A leading or inner "**" matches any number of segments.
+		for k := 0; k <= len(ps); k++ {
+			if coversSegments(qs[1:], ps[k:]) {
+				return true
+			}
+		}
+		return false
+	case q == "**" || q == "/**":
+		// A trailing "**" matches one or more segments. Only the
+		// trailing "/**" of a directory pattern also matches the
+		// directory itself, which must not be a file then: ps must
+		// not end here.
+		if q == "/**" {
+			return len(ps) > 0
+		}
+		return minSegments(ps) > 0
+	case len(ps) == 0 || ps[0] == "**" || ps[0] == "/**":
+		return false
+	}
+	return coversSegment(qs[0], ps[0]) && coversSegments(qs[1:], ps[1:])
+}
+
+// minSegments returns the least number of path segments the subsumption
+// segments ps match.
+func minSegments(ps []string) int {
+	n := 0
+	for i, p := range ps {
+		if p != "**" && p != "/**" || p == "**" && i == len(ps)-1 {
+			n++
+		}
+	}
+	return n
+}
+
+// coversSegment reports whether the glob segment q matches every name the
+// glob segment p matches.
+func coversSegment(q, p string) bool {
+	switch {
+	case q == p || q == "*":
+		return true
+	case isLiteral(p):
+		return matchSegment(q, p, false)
+	}
+	return false
+}
+
+// overlapsSegments reports whether the subsumption segments a and b may
+// match a common path. It is conservative, and only proves them disjoint if
+// they are anchored and differ in a literal leading segment.
+func overlapsSegments(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == "**" || a[i] == "/**" || b[i] == "**" || b[i] == "/**" {
+			return true
+		}
+		if isLiteral(a[i]) && isLiteral(b[i]) && a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimplify(t *testing.T) {
+	cases := []struct {
+		lines   []string
+		want    []string
+		changes []string
+	}{
+		{
+			lines:   []string{"build/", "/build/foo.txt", "build/tmp/", "*.log", "debug.log", "/logs/*.log", "debug*.log"},
+			want:    []string{"build/", "*.log", "debug*.log"},
+			changes: []string{"subsumed: /build/foo.txt (line 2) by build/ (line 1)", "subsumed: build/tmp/ (line 3) by build/ (line 1)", "subsumed: debug.log (line 5) by debug*.log (line 7)", "subsumed: /logs/*.log (line 6) by *.log (line 4)"},
+		},
+		{
+			// The negation in between may re-include debug.log.
+			lines: []string{"*.log", "!debug*", "debug.log"},
+			want:  []string{"*.log", "!debug*", "debug.log"},
+		},
+		{
+			// The negation in between is disjoint from /src/debug.log.
+			lines:   []string{"*.log", "!/docs/*", "/src/debug.log"},
+			want:    []string{"*.log", "!/docs/*"},
+			changes: []string{"subsumed: /src/debug.log (line 3) by *.log (line 1)"},
+		},
+		{
+			// A pattern subsumed by a later one never decides.
+			lines:   []string{"debug.log", "!keep.log", "*.log"},
+			want:    []string{"!keep.log", "*.log"},
+			changes: []string{"subsumed: debug.log (line 1) by *.log (line 3)"},
+		},
+		{
+			lines:   []string{"!/src/keep.txt", "!src/"},
+			want:    []string{"!src/"},
+			changes: []string{"subsumed: !/src/keep.txt (line 1) by !src/ (line 2)"},
+		},
+		{
+			// Directory patterns do not match files, "build" does not
+			// match paths beneath build, and "foo/**" does not match
+			// the directory foo itself.
+			lines:   []string{"build", "build/", "foo/", "foo/**"},
+			want:    []string{"build", "build/", "foo/"},
+			changes: []string{"subsumed: foo/** (line 4) by foo/ (line 3)"},
+		},
+		{
+			lines:   []string{"*.o", "*.o", "a/**/**/b"},
+			want:    []string{"*.o", "a/**/b"},
+			changes: []string{"duplicate: *.o (line 1) by *.o (line 2)", "collapsed: a/**/**/b (line 3) to a/**/b (line 3)"},
+		},
+	}
+	for _, c := range cases {
+		ps, err := FromLines(c.lines...)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		simple, changes := Simplify(ps)
+		var got []string
+		for _, p := range simple.Patterns() {
+			got = append(got, p.String())
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Simplify('%v') returned '%v', want '%v'", c.lines, got, c.want)
+		}
+		var gotChanges []string
+		for _, change := range changes {
+			gotChanges = append(gotChanges, change.String())
+		}
+		if !reflect.DeepEqual(gotChanges, c.changes) {
+			t.Errorf("Simplify('%v') made changes '%v', want '%v'", c.lines, gotChanges, c.changes)
+		}
+		if len(ps.Patterns()) != len(c.lines) {
+			t.Errorf("Simplify('%v') modified the PathSpec", c.lines)
+		}
+		for _, name := range []string{"build/", "build", "build/foo.txt", "build/tmp/x", "debug.log", "logs/debug.log", "src/debug.log", "src/keep.txt", "docs/x", "foo/", "foo/x", "x.o", "a/b", "a/x/y/b"} {
+			if got, want := simple.MatchState(name), ps.MatchState(name); got != want {
+				t.Errorf("Simplify('%v') decides '%s' as '%s', want '%s'", c.lines, name, got, want)
+			}
+		}
+	}
+}
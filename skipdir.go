@@ -0,0 +1,118 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CanSkipDir reports whether the directory dir and everything beneath it are
+// guaranteed to be ignored by Match, so a walker can prune it without
+// missing a re-included path. This is the case if dir is ignored by a
+// pattern which matches everything beneath it, like "build/", and no later
+// negated pattern may match a path beneath it, like "!build/keep" or
+// "!*.keep" do. CanSkipDir is conservative: it returns false for patterns
+// whose matches cannot be predicted, like custom Matchers, and for
+// directories reported as tracked, see SetTracked.
+//
+// Under git's rules, as implemented by MatchGit and Walk, nothing beneath an
+// ignored directory can be re-included, so every ignored directory can be
+// skipped.
+func (ps *PathSpec) CanSkipDir(dir string) bool {
+	name := dirName(ps.slashPath(dir), true)
+	if isTracked(ps.tracked, name) {
+		return false
+	}
+	p := ps.findLastMatch(name)
+	if p == nil || p.negate || !coversDescendants(p) {
+		return false
+	}
+	segs := strings.Split(strings.TrimSuffix(name, "/"), "/")
+	decided := false
+	for _, q := range ps.active() {
+		if q == p {
+			decided = true
+			continue
+		}
+		if decided && q.negate && mayMatchBeneath(q, segs) {
+			return false
+		}
+	}
+	return true
+}
+
+// coversDescendants reports whether the pattern p, having matched a
+// directory, matches everything beneath it as well.
+func coversDescendants(p *Pattern) bool {
+	switch p.syntax {
+	case "gitwildmatch", "braces":
+		// Directory patterns end with "/.*$".
+		return p.dir && p.matcher == nil
+	case "dockerignore":
+		return true
+	}
+	return false
+}
+
+// mayMatchBeneath reports whether the pattern p may match a path beneath the
+// directory with the path segments dirSegs.
+func mayMatchBeneath(p *Pattern, dirSegs []string) bool {
+	if p.syntax != "gitwildmatch" {
+		return true
+	}
+	segs, _, err := NormalizePattern(p.text)
+	if err != nil {
+		return true
+	}
+	// Patterns compiled with WithCaseSensitive(false) fold case, see
+	// foldCase.
+	fold := strings.HasPrefix(p.regexString(), "(?i)")
+	for i, seg := range segs {
+		if seg == "**" {
+			return true
+		}
+		if i == len(dirSegs) {
+			// The pattern has segments left for the path beneath the
+			// directory.
+			return true
+		}
+		if !matchSegment(seg, dirSegs[i], fold) {
+			return false
+		}
+	}
+	// The pattern only matches the directory or one of its parents.
+	return false
+}
+
+// matchSegment reports whether the pattern segment seg matches the path
+// segment name, ignoring case if fold is set.
+func matchSegment(seg, name string, fold bool) bool {
+	if isLiteral(seg) {
+		if fold {
+			return strings.EqualFold(seg, name)
+		}
+		return seg == name
+	}
+	expr := "^" + translateGlob(seg) + "$"
+	if fold {
+		expr = "(?i)" + expr
+	}
+	regex, err := regexp.Compile(expr)
+	return err != nil || regex.MatchString(name)
+}
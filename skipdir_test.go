@@ -0,0 +1,87 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestPathSpecCanSkipDir(t *testing.T) {
+	tests := []struct {
+		lines []string
+		dir   string
+		want  bool
+	}{
+		{[]string{"build/"}, "build", true},
+		{[]string{"build/"}, "src/build", true},
+		{[]string{"build/"}, "build/sub", true},
+		{[]string{"build"}, "build", false},
+		{[]string{"build/"}, "src", false},
+		{[]string{"build/", "!build/keep"}, "build", false},
+		{[]string{"build/", "!build/keep"}, "src/build", false},
+		{[]string{"build/", "!/build/keep"}, "build/sub", true},
+		{[]string{"build/", "!/build/keep"}, "build", false},
+		{[]string{"/build/", "!/src/keep"}, "build", true},
+		{[]string{"/build/", "!/b*/keep"}, "build", false},
+		{[]string{"/build/", "!/a*/keep"}, "build", true},
+		{[]string{"/build/", "!/build"}, "build/sub", true},
+		{[]string{"build/", "!*.keep"}, "build", false},
+		{[]string{"!*.keep", "build/"}, "build", true},
+		{[]string{"build/", "!build/"}, "build", false},
+	}
+	for _, test := range tests {
+		ps, err := FromLines(test.lines...)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := ps.CanSkipDir(test.dir); got != test.want {
+			t.Errorf("CanSkipDir('%s', %s) returned '%v', want '%v'", test.lines, test.dir, got, test.want)
+		}
+	}
+
+	folded := []struct {
+		lines []string
+		dir   string
+		want  bool
+	}{
+		{[]string{"/build/", "!/BUILD/keep"}, "build", false},
+		{[]string{"/build/", "!/B*/keep"}, "build", false},
+		{[]string{"/build/", "!/A*/keep"}, "build", true},
+		{[]string{"/BUILD/"}, "build", true},
+	}
+	for _, test := range folded {
+		ps, err := FromLinesWithOptions(test.lines, WithCaseSensitive(false))
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := ps.CanSkipDir(test.dir); got != test.want {
+			t.Errorf("CanSkipDir('%s', %s) without case sensitivity returned '%v', want '%v'", test.lines, test.dir, got, test.want)
+		}
+		if test.want && !ps.Match(test.dir+"/keep") {
+			t.Errorf("Match('%s/keep') returned 'false' for a skippable directory", test.dir)
+		}
+	}
+
+	ps, err := DockerIgnore("build", "!build/keep")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if ps.CanSkipDir("build") {
+		t.Errorf("CanSkipDir('build') returned 'true' despite a dockerignore exception")
+	}
+}
@@ -0,0 +1,263 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SparseCheckout answers whether paths are part of a git sparse checkout, as
+// configured by a $GIT_DIR/info/sparse-checkout file.
+//
+// In full pattern mode, the file holds gitignore patterns selecting the
+// paths to check out, matched like git does, see WithWildmatch. The last
+// pattern matching a path decides, and a path no pattern matches belongs to
+// the checkout if its nearest decided parent directory does.
+//
+// In cone mode, the file may only hold the patterns "git sparse-checkout set
+// --cone" writes, see ConePatterns: all files at the root are checked out,
+// and for every directory in the cone either all of its contents
+// (recursively), or only the files directly inside it (as a parent of a
+// deeper directory of the cone).
+type SparseCheckout struct {
+	cone bool
+	spec *PathSpec
+	// recursive holds the directories whose contents are checked out
+	// recursively and parents the directories whose files are checked out,
+	// both without leading or trailing slash.
+	recursive map[string]bool
+	parents   map[string]bool
+}
+
+// NewSparseCheckout compiles the lines of a sparse-checkout file, in cone
+// mode if cone is true. In cone mode, patterns not written by cone mode are
+// reported as errors, like git warns about them; callers can fall back to
+// full pattern mode like git does.
+func NewSparseCheckout(cone bool, lines ...string) (*SparseCheckout, error) {
+	if !cone {
+		ps, err := compileLines("", lines, func(line string) (*Pattern, error) {
+			return newWildmatchPattern(line, false)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &SparseCheckout{spec: ps}, nil
+	}
+
+	s := &SparseCheckout{cone: true, recursive: make(map[string]bool), parents: make(map[string]bool)}
+	var errs ParseErrors
+	for i, line := range lines {
+		if i == 0 {
+			line = strings.TrimPrefix(line, byteOrderMark)
+		}
+		pattern, ok := patternFromLine(line)
+		if !ok {
+			continue
+		}
+		if err := s.addConePattern(pattern); err != nil {
+			errs = append(errs, &ParseError{Line: i + 1, Pattern: pattern, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return s, nil
+}
+
+// FromSparseCheckout compiles a sparse-checkout file, line by line. See
+// NewSparseCheckout.
+func FromSparseCheckout(r io.Reader, cone bool) (*SparseCheckout, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewSparseCheckout(cone, lines...)
+}
+
+// addConePattern adds a cone mode pattern, like git's add_pattern_to_hashsets.
+func (s *SparseCheckout) addConePattern(pattern string) error {
+	if pattern == "/*" || pattern == "!/*/" {
+		return nil
+	}
+	if strings.HasPrefix(pattern, "!") {
+		dir, ok := coneDir(strings.TrimSuffix(pattern[1:], "*/"))
+		if !ok || !strings.HasSuffix(pattern, "/*/") || !s.recursive[dir] {
+			return fmt.Errorf("unrecognized negative pattern %q for cone mode", pattern)
+		}
+		delete(s.recursive, dir)
+		s.parents[dir] = true
+		return nil
+	}
+	dir, ok := coneDir(pattern)
+	if !ok {
+		return fmt.Errorf("unrecognized pattern %q for cone mode", pattern)
+	}
+	s.recursive[dir] = true
+	for i := strings.LastIndexByte(dir, '/'); i > 0; i = strings.LastIndexByte(dir[:i], '/') {
+		s.parents[dir[:i]] = true
+	}
+	return nil
+}
+
+// coneDir returns the directory of the cone mode pattern "/dir/", with
+// escaped wildcards unescaped, and whether pattern has that form.
+func coneDir(pattern string) (string, bool) {
+	if len(pattern) < 3 || pattern[0] != '/' || pattern[len(pattern)-1] != '/' {
+		return "", false
+	}
+	var b strings.Builder
+	for i := 1; i < len(pattern)-1; i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern)-1:
+			i++
+			c = pattern[i]
+		case isGlobSpecial(c):
+			return "", false
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), true
+}
+
+// Cone reports whether the SparseCheckout uses cone mode.
+func (s *SparseCheckout) Cone() bool {
+	return s.cone
+}
+
+// Contains reports whether the slash-separated path name is part of the
+// sparse checkout. Directories are denoted by a trailing slash, e.g. "src/".
+// A directory is part of a cone mode checkout if it is inside the cone or
+// leads to it.
+func (s *SparseCheckout) Contains(name string) bool {
+	if !s.cone {
+		return s.containsPattern(name)
+	}
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.Trim(name, "/")
+	dir := name
+	if !isDir {
+		dir = ""
+		if i := strings.LastIndexByte(name, '/'); i >= 0 {
+			dir = name[:i]
+		}
+	}
+	if dir == "" || s.recursive[dir] || s.parents[dir] {
+		return true
+	}
+	for i := strings.LastIndexByte(dir, '/'); i > 0; i = strings.LastIndexByte(dir[:i], '/') {
+		if s.recursive[dir[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsPath is like Contains, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (s *SparseCheckout) ContainsPath(name string, isDir bool) bool {
+	return s.Contains(dirName(name, isDir))
+}
+
+// containsPattern decides about name in full pattern mode: the last pattern
+// matching name itself decides, or if there is none, the decision about its
+// nearest parent directory. Unlike with PathSpec.Match, directory patterns
+// only match directories, not their contents.
+func (s *SparseCheckout) containsPattern(name string) bool {
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.TrimSuffix(name, "/")
+	for {
+		for i := len(s.spec.patterns) - 1; i >= 0; i-- {
+			p := s.spec.patterns[i]
+			if p.matcher.(*wildmatcher).matchPath(name, isDir) {
+				return !p.negate
+			}
+		}
+		i := strings.LastIndexByte(name, '/')
+		if i < 0 {
+			return false
+		}
+		name, isDir = name[:i], true
+	}
+}
+
+// ConePatterns returns the lines of a cone mode sparse-checkout file
+// checking out the directories dirs recursively, like "git sparse-checkout
+// set --cone" writes them: the files at the root, and for every parent
+// directory of dirs the files directly inside it. Directories inside other
+// directories of dirs are dropped, since they are checked out anyway.
+func ConePatterns(dirs ...string) []string {
+	recursive := make(map[string]bool)
+	for _, dir := range dirs {
+		if dir = strings.Trim(dir, "/"); dir != "" {
+			recursive[dir] = true
+		}
+	}
+	covered := func(dir string) bool {
+		for i := strings.LastIndexByte(dir, '/'); i > 0; i = strings.LastIndexByte(dir[:i], '/') {
+			if recursive[dir[:i]] {
+				return true
+			}
+		}
+		return false
+	}
+
+	parentSet := make(map[string]bool)
+	var roots []string
+	for dir := range recursive {
+		if covered(dir) {
+			continue
+		}
+		roots = append(roots, dir)
+		for i := strings.LastIndexByte(dir, '/'); i > 0; i = strings.LastIndexByte(dir[:i], '/') {
+			if !recursive[dir[:i]] {
+				parentSet[dir[:i]] = true
+			}
+		}
+	}
+	var parents []string
+	for dir := range parentSet {
+		parents = append(parents, dir)
+	}
+	sort.Strings(parents)
+	sort.Strings(roots)
+
+	lines := []string{"/*", "!/*/"}
+	for _, dir := range parents {
+		lines = append(lines, "/"+escapeConeDir(dir)+"/", "!/"+escapeConeDir(dir)+"/*/")
+	}
+	for _, dir := range roots {
+		lines = append(lines, "/"+escapeConeDir(dir)+"/")
+	}
+	return lines
+}
+
+// escapeConeDir escapes the wildcards in dir with backslashes.
+func escapeConeDir(dir string) string {
+	var b strings.Builder
+	for i := 0; i < len(dir); i++ {
+		if isGlobSpecial(dir[i]) {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(dir[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sparseFiles are the files of the repository the sparse-checkout tests were
+// verified against with "git ls-files -t".
+var sparseFiles = []string{
+	"A/B/b.txt",
+	"A/B/x/y.txt",
+	"A/C/c.txt",
+	"A/a.txt",
+	"D/E/F/f.txt",
+	"D/E/F/g/z.txt",
+	"D/E/e.txt",
+	"D/E/h/i.txt",
+	"D/d.txt",
+	"root.txt",
+	"w*x/k.txt",
+}
+
+// checkedOut returns the sparseFiles s contains.
+func checkedOut(s *SparseCheckout) []string {
+	var names []string
+	for _, name := range sparseFiles {
+		if s.Contains(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestConePatterns(t *testing.T) {
+	got := ConePatterns("A/B", "/D/E/F/", "w*x", "A/B/x")
+	want := []string{"/*", "!/*/", "/A/", "!/A/*/", "/D/", "!/D/*/", "/D/E/", "!/D/E/*/", "/A/B/", "/D/E/F/", `/w\*x/`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ConePatterns() returned '%v', want '%v'", got, want)
+	}
+	if got := ConePatterns(); !reflect.DeepEqual(got, []string{"/*", "!/*/"}) {
+		t.Errorf("ConePatterns() returned '%v', want '%v'", got, []string{"/*", "!/*/"})
+	}
+}
+
+func TestSparseCheckoutCone(t *testing.T) {
+	s, err := FromSparseCheckout(strings.NewReader(strings.Join(ConePatterns("A/B", "D/E/F", "w*x"), "\n")), true)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !s.Cone() {
+		t.Errorf("Cone() returned 'false', want 'true'")
+	}
+	want := []string{"A/B/b.txt", "A/B/x/y.txt", "A/a.txt", "D/E/F/f.txt", "D/E/F/g/z.txt", "D/E/e.txt", "D/d.txt", "root.txt", "w*x/k.txt"}
+	if got := checkedOut(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("Contains() selected '%v', want '%v'", got, want)
+	}
+
+	dirs := map[string]bool{"A": true, "A/C": false, "D/E/F/g": true, "D/E/h": false, "X": false}
+	for dir, want := range dirs {
+		if got := s.ContainsPath(dir, true); got != want {
+			t.Errorf("ContainsPath('%s', true) returned '%v', want '%v'", dir, got, want)
+		}
+	}
+
+	for _, line := range []string{"/A/*.txt", "!/A/B/*/", "A/", "/A/*/"} {
+		if _, err := NewSparseCheckout(true, "/*", "!/*/", line); err == nil {
+			t.Errorf("NewSparseCheckout(true, '%s') returned no error", line)
+		}
+	}
+}
+
+func TestSparseCheckoutFull(t *testing.T) {
+	s, err := NewSparseCheckout(false, "/*", "!/*/", "/A/", "!/A/C/", "/D/E/F/g", "E/h/*.txt")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if s.Cone() {
+		t.Errorf("Cone() returned 'true', want 'false'")
+	}
+	want := []string{"A/B/b.txt", "A/B/x/y.txt", "A/a.txt", "D/E/F/g/z.txt", "root.txt"}
+	if got := checkedOut(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("Contains() selected '%v', want '%v'", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Precedence orders the layers of a SpecStack: layers with a higher
+// precedence are consulted first. The predefined precedences follow the order
+// documented in gitignore(5), extended by command-line and environment
+// layers. They are spaced apart, so callers can slot in layers of their own,
+// like PrecedenceEnv + 1.
+type Precedence int
+
+const (
+	// PrecedenceGlobal is the precedence of the user's global excludes
+	// file, configured as core.excludesFile.
+	PrecedenceGlobal Precedence = 100 * (iota + 1)
+	// PrecedenceRepo is the precedence of the $GIT_DIR/info/exclude file
+	// of a repository.
+	PrecedenceRepo
+	// PrecedenceTree is the precedence of the .gitignore files of a work
+	// tree, as matched by GitIgnoreTree.
+	PrecedenceTree
+	// PrecedenceEnv is the precedence of patterns taken from an
+	// environment variable.
+	PrecedenceEnv
+	// PrecedenceCommandLine is the precedence of patterns given on the
+	// command line, like with "git ls-files --exclude".
+	PrecedenceCommandLine
+)
+
+// StackLayer is a source of patterns in a SpecStack.
+type StackLayer struct {
+	// Name describes the source, like a file name or "--exclude".
+	Name string
+	// Precedence orders the layer relative to the other layers.
+	Precedence Precedence
+	// Decider decides about paths for the layer.
+	Decider Decider
+}
+
+// SpecStack matches paths against several sources of patterns, like git
+// consults command-line excludes, the .gitignore files, info/exclude and the
+// global excludes file: the layers are asked in order of decreasing
+// precedence, and the first one matching a path decides about it, see
+// DecideLayers. Within a layer, the last matching pattern decides as usual.
+//
+// The zero value is an empty SpecStack ready to use. Pushing layers must not
+// happen concurrently with matching.
+type SpecStack struct {
+	layers []StackLayer
+}
+
+// NewSpecStack returns an empty SpecStack.
+func NewSpecStack() *SpecStack {
+	return &SpecStack{}
+}
+
+// Push adds the layer d with the given precedence. Of layers with the same
+// precedence, the layer pushed last is consulted first, just like later
+// lines of a gitignore file take precedence.
+func (s *SpecStack) Push(name string, prec Precedence, d Decider) {
+	i := 0
+	for i < len(s.layers) && s.layers[i].Precedence > prec {
+		i++
+	}
+	s.layers = append(s.layers, StackLayer{})
+	copy(s.layers[i+1:], s.layers[i:])
+	s.layers[i] = StackLayer{Name: name, Precedence: prec, Decider: d}
+}
+
+// PushLines compiles gitignore lines, like the values of repeated --exclude
+// flags, and pushes them as a layer. Blank lines and comments are skipped.
+func (s *SpecStack) PushLines(name string, prec Precedence, lines ...string) error {
+	ps, err := FromLines(lines...)
+	if err != nil {
+		return err
+	}
+	s.Push(name, prec, ps)
+	return nil
+}
+
+// PushFile compiles the gitignore file name and pushes it as a layer. A
+// missing file is skipped, since most of the files git consults are
+// optional.
+func (s *SpecStack) PushFile(name string, prec Precedence) error {
+	ps, err := FromFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	s.Push(name, prec, ps)
+	return nil
+}
+
+// PushEnv compiles the patterns in the environment variable key, separated
+// by os.PathListSeparator, and pushes them as a layer with PrecedenceEnv. An
+// unset or empty variable is skipped.
+func (s *SpecStack) PushEnv(key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	return s.PushLines("$"+key, PrecedenceEnv, strings.Split(value, string(os.PathListSeparator))...)
+}
+
+// PushGitSources pushes the sources of ignore patterns git consults for the
+// work tree at repoRoot as three layers: its .gitignore files with
+// PrecedenceTree, $GIT_DIR/info/exclude with PrecedenceRepo and the global
+// excludes file with PrecedenceGlobal. Unlike FromGitSources, the layers are
+// consulted independently, so a negation in a .gitignore file re-includes
+// paths inside a directory ignored by a lower layer.
+func (s *SpecStack) PushGitSources(repoRoot string) error {
+	gitDir, err := findGitDir(repoRoot)
+	if err != nil {
+		return err
+	}
+	if global := excludesFile(gitDir); global != "" {
+		if err := s.PushFile(global, PrecedenceGlobal); err != nil {
+			return err
+		}
+	}
+	if err := s.PushFile(filepath.Join(gitDir, "info", "exclude"), PrecedenceRepo); err != nil {
+		return err
+	}
+	tree, err := NewGitIgnoreTree(os.DirFS(repoRoot))
+	if err != nil {
+		return err
+	}
+	s.Push(repoRoot, PrecedenceTree, tree)
+	return nil
+}
+
+// Layers returns the layers in the order they are consulted.
+func (s *SpecStack) Layers() []StackLayer {
+	return s.layers
+}
+
+// Decide returns the decision of the layer with the highest precedence
+// matching name. Directories are denoted by a trailing slash, e.g. "build/".
+func (s *SpecStack) Decide(name string) Decision {
+	d, _ := s.decide(name)
+	return d
+}
+
+// DecidingLayer returns the layer deciding about name, or nil if no layer
+// matched it.
+func (s *SpecStack) DecidingLayer(name string) *StackLayer {
+	_, i := s.decide(name)
+	if i < 0 {
+		return nil
+	}
+	return &s.layers[i]
+}
+
+// decide returns the decision about name and the position of the deciding
+// layer, or -1.
+func (s *SpecStack) decide(name string) (Decision, int) {
+	for i, layer := range s.layers {
+		if d := layer.Decider.Decide(name); d != Unmatched {
+			return d, i
+		}
+	}
+	return Unmatched, -1
+}
+
+// Match reports whether name is ignored by the SpecStack.
+func (s *SpecStack) Match(name string) bool {
+	return s.Decide(name) == Ignored
+}
+
+// MatchPath is like Match, but takes whether name is a directory as an
+// argument instead of requiring a trailing slash for directories.
+func (s *SpecStack) MatchPath(name string, isDir bool) bool {
+	return s.Match(dirName(name, isDir))
+}
+
+// MatchState is like Decide.
+func (s *SpecStack) MatchState(name string) MatchState {
+	return s.Decide(name)
+}
@@ -0,0 +1,120 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpecStack(t *testing.T) {
+	home := t.TempDir()
+	repo := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("PATHSPEC_EXCLUDE", strings.Join([]string{"*.env", "!keep.tmp"}, string(os.PathListSeparator)))
+	writeFiles(t, home, map[string]string{
+		".config/git/ignore": "*.swp\n*.tmp\n",
+	})
+	writeFiles(t, repo, map[string]string{
+		".git/info/exclude": "local/\n",
+		".gitignore":        "*.log\n!a.swp\n",
+	})
+
+	s := NewSpecStack()
+	if err := s.PushLines("--exclude", PrecedenceCommandLine, "!debug.log", "*.go"); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := s.PushGitSources(repo); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := s.PushEnv("PATHSPEC_EXCLUDE"); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := s.PushEnv("PATHSPEC_UNSET"); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	wantLayers := []Precedence{PrecedenceCommandLine, PrecedenceEnv, PrecedenceTree, PrecedenceRepo, PrecedenceGlobal}
+	layers := s.Layers()
+	if len(layers) != len(wantLayers) {
+		t.Fatalf("Layers() returned %d layers, want %d", len(layers), len(wantLayers))
+	}
+	for i, layer := range layers {
+		if layer.Precedence != wantLayers[i] {
+			t.Errorf("Layers()[%d] has precedence '%v', want '%v'", i, layer.Precedence, wantLayers[i])
+		}
+	}
+
+	tests := []struct {
+		name  string
+		want  Decision
+		layer string
+	}{
+		{"main.go", Ignored, "--exclude"},
+		{"debug.log", Included, "--exclude"},
+		{"other.log", Ignored, repo},
+		{"a.env", Ignored, "$PATHSPEC_EXCLUDE"},
+		{"keep.tmp", Included, "$PATHSPEC_EXCLUDE"},
+		{"a.swp", Included, repo},
+		{"b.swp", Ignored, filepath.Join(home, ".config", "git", "ignore")},
+		{"local/x", Ignored, filepath.Join(repo, ".git", "info", "exclude")},
+		{"README.md", Unmatched, ""},
+	}
+	for _, test := range tests {
+		if got := s.Decide(test.name); got != test.want {
+			t.Errorf("Decide('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+		layer := s.DecidingLayer(test.name)
+		switch {
+		case layer == nil && test.layer != "":
+			t.Errorf("DecidingLayer('%s') returned 'nil', want '%s'", test.name, test.layer)
+		case layer != nil && layer.Name != test.layer:
+			t.Errorf("DecidingLayer('%s') returned '%s', want '%s'", test.name, layer.Name, test.layer)
+		}
+	}
+}
+
+func TestSpecStackPush(t *testing.T) {
+	first, err := FromLines("*.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	second, err := FromLines("!keep.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	var s SpecStack
+	s.Push("first", PrecedenceRepo, first)
+	s.Push("second", PrecedenceRepo, second)
+	if got := s.MatchPath("keep.log", false); got {
+		t.Errorf("MatchPath('keep.log', false) returned '%v', want '%v'", got, false)
+	}
+	if got := s.Match("debug.log"); !got {
+		t.Errorf("Match('debug.log') returned '%v', want '%v'", got, true)
+	}
+	if err := s.PushFile("does-not-exist", PrecedenceGlobal); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if got := len(s.Layers()); got != 2 {
+		t.Errorf("Layers() returned %d layers, want %d", got, 2)
+	}
+}
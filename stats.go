@@ -0,0 +1,127 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// Count is a number of files and their total size in bytes.
+type Count struct {
+	Files int
+	Bytes int64
+}
+
+// add counts a file of size bytes.
+func (c *Count) add(size int64) {
+	c.Files++
+	c.Bytes += size
+}
+
+// PatternReport describes the files a pattern decided about.
+type PatternReport struct {
+	Pattern *Pattern
+	// Count holds the files the pattern ignored or, for a negated pattern,
+	// re-included.
+	Count
+}
+
+// Report summarizes which files of a tree a PathSpec ignores, see Stats.
+type Report struct {
+	// Included holds the files which are not ignored, whether no pattern
+	// matched them or a negated pattern re-included them.
+	Included Count
+	// Ignored holds the ignored files.
+	Ignored Count
+	// Patterns holds a report for every pattern, in order.
+	Patterns []PatternReport
+}
+
+// Unused returns the patterns which did not decide about any file. They are
+// either stale, or always overridden by later patterns.
+func (r *Report) Unused() []*Pattern {
+	var unused []*Pattern
+	for _, pr := range r.Patterns {
+		if pr.Files == 0 {
+			unused = append(unused, pr.Pattern)
+		}
+	}
+	return unused
+}
+
+// Stats walks all files of fsys, including those in ignored directories, and
+// reports how many of them and how many bytes the PathSpec ignores, broken
+// down by the pattern deciding about them. Like with MatchGit, the files in
+// an ignored directory are attributed to the pattern ignoring it. Directories
+// themselves are not counted, and only regular files have a size. Disabled
+// patterns decide about nothing.
+func (ps *PathSpec) Stats(fsys fs.FS) (*Report, error) {
+	r := &Report{Patterns: make([]PatternReport, len(ps.patterns))}
+	for i, p := range ps.patterns {
+		r.Patterns[i].Pattern = p
+	}
+	// ignoredDir is the directory being walked, with a trailing slash,
+	// which the pattern at ignoredBy ignores.
+	ignoredDir, ignoredBy := "", -1
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		if ignoredBy >= 0 && !strings.HasPrefix(name, ignoredDir) {
+			ignoredDir, ignoredBy = "", -1
+		}
+		i := ignoredBy
+		if i < 0 {
+			i = ps.findLastIndex(dirName(name, d.IsDir()))
+		}
+		if isTracked(ps.tracked, name) {
+			i = -1
+		}
+		if d.IsDir() {
+			if ignoredBy < 0 && i >= 0 && !ps.patterns[i].negate {
+				ignoredDir, ignoredBy = name+"/", i
+			}
+			return nil
+		}
+		var size int64
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size = info.Size()
+		}
+		if i >= 0 {
+			r.Patterns[i].add(size)
+		}
+		if patternState(ps.patternAt(i)) == StateIgnored {
+			r.Ignored.add(size)
+		} else {
+			r.Included.add(size)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
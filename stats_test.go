@@ -0,0 +1,61 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPathSpecStats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":                        {Data: []byte("package main")},
+		"debug.log":                      {Data: []byte("12345")},
+		"logs/keep.log":                  {Data: []byte("123")},
+		"build/out/main":                 {Data: []byte("1234567890")},
+		"build/keep.log":                 {Data: []byte("1")},
+		"node_modules/left-pad/index.js": {Data: []byte("leftPad")},
+	}
+	ps, err := FromLines("*.log", "!keep.log", "build/", "node_modules/", "*.tmp")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	r, err := ps.Stats(fsys)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	if want := (Count{Files: 2, Bytes: 15}); r.Included != want {
+		t.Errorf("Stats() included '%+v', want '%+v'", r.Included, want)
+	}
+	if want := (Count{Files: 4, Bytes: 23}); r.Ignored != want {
+		t.Errorf("Stats() ignored '%+v', want '%+v'", r.Ignored, want)
+	}
+	var got []Count
+	for _, pr := range r.Patterns {
+		got = append(got, pr.Count)
+	}
+	want := []Count{{1, 5}, {1, 3}, {2, 11}, {1, 7}, {0, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats() counted '%v' by pattern, want '%v'", got, want)
+	}
+	if unused := r.Unused(); len(unused) != 1 || unused[0].String() != "*.tmp" {
+		t.Errorf("Unused() returned '%v', want '[*.tmp]'", unused)
+	}
+}
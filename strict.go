@@ -0,0 +1,60 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithStrict rejects patterns git documents as invalid instead of silently
+// translating them: a "**" which is not a whole path segment, like in
+// "a**b", "**.go" or "docs**/", matches like a single "*" by default. In
+// strict mode such lines fail to compile with an error wrapping
+// ErrInvalidDoubleAsterisk, reported with their line number in a ParseError.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// checkStrict returns an error if the pattern contains a "**" which is not
+// a whole path segment. Escaped asterisks are literal and never count.
+func checkStrict(pattern string) error {
+	body := strings.TrimPrefix(pattern, "!")
+	for _, seg := range strings.Split(body, "/") {
+		if seg != "**" && hasDoubleAsterisk(seg) {
+			return fmt.Errorf("invalid pattern %q: segment %q: %w", pattern, seg, ErrInvalidDoubleAsterisk)
+		}
+	}
+	return nil
+}
+
+// hasDoubleAsterisk reports whether seg contains two consecutive unescaped
+// asterisks.
+func hasDoubleAsterisk(seg string) bool {
+	for i := 0; i < len(seg); i++ {
+		switch {
+		case seg[i] == '\\':
+			i++
+		case seg[i] == '*' && i+1 < len(seg) && seg[i+1] == '*':
+			return true
+		}
+	}
+	return false
+}
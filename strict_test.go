@@ -0,0 +1,44 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithStrict(t *testing.T) {
+	valid := []string{"**/foo", "foo/**", "a/**/b", "*.go", "!**/*.log", "a\\**b", "a\\*\\*b", "**"}
+	for _, line := range valid {
+		if _, err := FromLinesWithOptions([]string{line}, WithStrict()); err != nil {
+			t.Errorf("FromLinesWithOptions('%s', WithStrict()) returned an unexpected error: %s", line, err)
+		}
+	}
+
+	invalid := []string{"a**b", "**.go", "docs**/", "!foo/***", "a/b**"}
+	for _, line := range invalid {
+		_, err := FromLinesWithOptions([]string{"# comment", line}, WithStrict())
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != 2 || !errors.Is(err, ErrInvalidDoubleAsterisk) {
+			t.Errorf("FromLinesWithOptions('%s', WithStrict()) returned '%v', want an invalid double asterisk on line 2", line, err)
+		}
+		if _, err := FromLines(line); err != nil {
+			t.Errorf("FromLines('%s') returned an unexpected error: %s", line, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"path"
+	"strings"
+)
+
+// SvnDefaultGlobalIgnores is the global-ignores list Subversion uses if none
+// is configured.
+const SvnDefaultGlobalIgnores = "*.o *.lo *.la *.al .libs *.so *.so.[0-9]* *.a *.pyc *.pyo __pycache__ *.rej *~ #*# .#* .*.swp .DS_Store [Tt]humbs.db"
+
+// SvnIgnore compiles a PathSpec from the value of the svn:ignore property of
+// the directory dir, which holds one pattern per line. Like in Subversion,
+// the patterns only apply to the direct children of dir, not to deeper
+// paths. dir is slash-separated and relative to the root of the working
+// copy; "" and "." denote the root itself.
+//
+// Patterns are matched with fnmatch against the base name of a path: "*"
+// and "?" match any character, including a leading dot, and "[...]" matches
+// a character class, negated by "!" or "^". A backslash escapes the next
+// character. Leading and trailing white space of a line is ignored, and
+// there are no comments or negated patterns.
+func SvnIgnore(dir, value string) *PathSpec {
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	var patterns []*Pattern
+	for i, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			p := newSvnPattern(line, dir, false)
+			p.line = i + 1
+			patterns = append(patterns, p)
+		}
+	}
+	return NewPathSpec(patterns...)
+}
+
+// SvnGlobalIgnores compiles a PathSpec from a Subversion global-ignores list,
+// as found in the "global-ignores" option of the Subversion configuration or
+// the svn:global-ignores property. The patterns are separated by white space
+// and apply to paths in every directory. See SvnIgnore for the syntax.
+func SvnGlobalIgnores(value string) *PathSpec {
+	var patterns []*Pattern
+	for _, field := range strings.Fields(value) {
+		patterns = append(patterns, newSvnPattern(field, "", true))
+	}
+	return NewPathSpec(patterns...)
+}
+
+// newSvnPattern compiles a Subversion ignore pattern applying to the direct
+// children of dir, or to all paths if recursive is true.
+func newSvnPattern(pattern, dir string, recursive bool) *Pattern {
+	m := &svnMatcher{pattern: pattern, dir: dir, recursive: recursive}
+	return &Pattern{syntax: "svnignore", text: pattern, matcher: m}
+}
+
+// svnMatcher matches a Subversion ignore pattern like Subversion does.
+type svnMatcher struct {
+	pattern   string
+	dir       string
+	recursive bool
+}
+
+// Match reports whether the pattern matches the base name of name and, if it
+// is not recursive, name lies directly in the directory of the pattern.
+func (m *svnMatcher) Match(name string) bool {
+	name = strings.TrimSuffix(name, "/")
+	parent, base := "", name
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		parent, base = name[:i], name[i+1:]
+	}
+	if !m.recursive && parent != m.dir {
+		return false
+	}
+	return wildmatch(m.pattern, base, 0) == wmMatch
+}
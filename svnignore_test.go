@@ -0,0 +1,83 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestSvnIgnore(t *testing.T) {
+	tests := []struct {
+		dir   string
+		value string
+		name  string
+		want  bool
+	}{
+		{"", "*.o\nbuild\n", "main.o", true},
+		{"", "*.o\nbuild\n", "build/", true},
+		{"", "*.o\nbuild\n", "src/main.o", false},
+		{".", "*.o", "main.o", true},
+		{"src", "*.o", "src/main.o", true},
+		{"src/", "*.o", "src/lib/main.o", false},
+		{"src", "*.o", "main.o", false},
+		{"", "  *.log  \r\n\n.*", ".hidden", true},
+		{"", "  *.log  \r\n\n.*", "debug.log", true},
+		{"", "[!a]*", "abc", false},
+		{"", "[^a]*", "bcd", true},
+		{"", `\*`, "*", true},
+		{"", `\*`, "a", false},
+	}
+	for _, test := range tests {
+		ps := SvnIgnore(test.dir, test.value)
+		if got := ps.Match(test.name); got != test.want {
+			t.Errorf("SvnIgnore('%s', %q).Match('%s') returned '%v', want '%v'", test.dir, test.value, test.name, got, test.want)
+		}
+	}
+}
+
+func TestSvnGlobalIgnores(t *testing.T) {
+	ps := SvnGlobalIgnores(SvnDefaultGlobalIgnores)
+	tests := map[string]bool{
+		"main.o":             true,
+		"a/b/lib.so.1":       true,
+		"src/__pycache__/":   true,
+		"docs/Thumbs.db":     true,
+		"docs/thumbs.db":     true,
+		"src/.main.go.swp":   true,
+		"src/main.go":        false,
+		"notes.txt~":         true,
+		"src/.libs/x":        false,
+		"src/so.1":           false,
+		"patches/fix.rej":    true,
+		"patches/fix.orig":   false,
+		"src/#autosave#":     true,
+		"src/.#lockfile":     true,
+		"src/lib.a":          true,
+		"src/lib.a/main.go":  false,
+		"src/.DS_Store":      true,
+		"src/sub/.DS_Store/": true,
+	}
+	for name, want := range tests {
+		if got := ps.Match(name); got != want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", name, got, want)
+		}
+	}
+	if got := len(SvnGlobalIgnores(" *.o\t*.a\n").Patterns()); got != 2 {
+		t.Errorf("SvnGlobalIgnores() returned %d patterns, want %d", got, 2)
+	}
+}
@@ -0,0 +1,196 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// WalkOption configures how Walk treats the file tree.
+type WalkOption func(*walkOptions)
+
+// walkOptions holds the configuration of a walk.
+type walkOptions struct {
+	symlinkDirs    bool
+	followSymlinks bool
+	ignoreFiles    []string
+}
+
+// newWalkOptions applies opts to the default configuration.
+func newWalkOptions(opts []WalkOption) *walkOptions {
+	o := &walkOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSymlinkDirs treats symbolic links pointing to directories as
+// directories when matching, so "dir/" patterns match them. The links are
+// still not followed. Git treats them as files instead.
+func WithSymlinkDirs() WalkOption {
+	return func(o *walkOptions) {
+		o.symlinkDirs = true
+	}
+}
+
+// WithFollowSymlinks follows symbolic links pointing to directories and walks
+// their contents, treating them as directories when matching. The walk
+// function receives a directory entry describing the link target for them.
+//
+// A link pointing to one of its own ancestor directories is not followed;
+// the walk function is called for it with an error wrapping ErrSymlinkLoop
+// instead. Loops are detected with os.SameFile, which requires fsys to
+// return the file information of the os package, as os.DirFS does.
+func WithFollowSymlinks() WalkOption {
+	return func(o *walkOptions) {
+		o.symlinkDirs = true
+		o.followSymlinks = true
+	}
+}
+
+// WithIgnoreFiles discovers the ignore files called names, ".gitignore" if
+// there are none, in every directory the walk enters, like GitIgnoreTree
+// does. Their patterns apply to the directory holding them and take
+// precedence over the patterns of ignore files above it and of the PathSpec
+// walked with.
+func WithIgnoreFiles(names ...string) WalkOption {
+	if len(names) == 0 {
+		names = []string{GitIgnoreFile}
+	}
+	return func(o *walkOptions) {
+		o.ignoreFiles = names
+	}
+}
+
+// isSymlinkDir reports whether the entry d of fsys is a symbolic link
+// pointing to a directory. Broken links are not.
+func isSymlinkDir(fsys fs.FS, name string, d fs.DirEntry) bool {
+	if d.Type()&fs.ModeSymlink == 0 {
+		return false
+	}
+	info, err := fs.Stat(fsys, name)
+	return err == nil && info.IsDir()
+}
+
+// walkFollow is like WalkContext, but follows symbolic links pointing to
+// directories.
+func (m *walkMatcher) walkFollow(ctx context.Context, fn fs.WalkDirFunc) error {
+	info, err := fs.Stat(m.fsys, ".")
+	if err != nil {
+		err = fn(".", nil, err)
+	} else {
+		err = m.walkDir(ctx, ".", fs.FileInfoToDirEntry(info), fn, []fs.FileInfo{info})
+	}
+	if err == fs.SkipDir {
+		return nil
+	}
+	return err
+}
+
+// walkDir walks the directory name like fs.WalkDir, resolving symbolic links
+// to directories. ancestors holds the file information of name and all of its
+// parent directories, for loop detection.
+func (m *walkMatcher) walkDir(ctx context.Context, name string, d fs.DirEntry, fn fs.WalkDirFunc, ancestors []fs.FileInfo) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := fn(name, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fs.ReadDir(m.fsys, name)
+	if err == nil {
+		err = m.enter(name)
+	}
+	if err != nil {
+		// Second call, to report the ReadDir error.
+		if err = fn(name, d, err); err != nil {
+			if err == fs.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, e := range entries {
+		child := path.Join(name, e.Name())
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		var info fs.FileInfo
+		if e.Type()&fs.ModeSymlink != 0 {
+			if target, err := fs.Stat(m.fsys, child); err == nil && target.IsDir() {
+				info = target
+				e = fs.FileInfoToDirEntry(target)
+			}
+		}
+		if m.match(child, e.IsDir()) {
+			continue
+		}
+		if !e.IsDir() {
+			if err := fn(child, e, nil); err != nil {
+				if err == fs.SkipDir {
+					break
+				}
+				return err
+			}
+			continue
+		}
+
+		if info == nil {
+			if info, err = e.Info(); err != nil {
+				if err := fn(child, e, err); err != nil && err != fs.SkipDir {
+					return err
+				}
+				continue
+			}
+		}
+		if isAncestor(info, ancestors) {
+			loopErr := &fs.PathError{Op: "walk", Path: child, Err: ErrSymlinkLoop}
+			if err := fn(child, e, loopErr); err != nil && err != fs.SkipDir {
+				return err
+			}
+			continue
+		}
+		if err := m.walkDir(ctx, child, e, fn, append(ancestors, info)); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// isAncestor reports whether the directory info is one of ancestors.
+func isAncestor(info fs.FileInfo, ancestors []fs.FileInfo) bool {
+	for _, a := range ancestors {
+		if os.SameFile(info, a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPathSpecWalkSymlinks(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"real/a.txt":   "",
+		"real/b.log":   "",
+		"other/c.txt":  "",
+		"other/d.txt":  "",
+		"top/file.txt": "",
+	})
+	links := map[string]string{
+		"link":       "real",
+		"real/back":  "..",
+		"top/broken": "missing",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Skipf("Symbolic links are not supported: %s", err)
+		}
+	}
+	fsys := os.DirFS(root)
+
+	ps, err := FromLines("*.log", "link/", "other/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name  string
+		opts  []WalkOption
+		want  []string
+		loops []string
+	}{
+		{
+			name: "default",
+			want: []string{".", "link", "real", "real/a.txt", "real/back", "top", "top/broken", "top/file.txt"},
+		},
+		{
+			name: "symlink dirs",
+			opts: []WalkOption{WithSymlinkDirs()},
+			want: []string{".", "real", "real/a.txt", "real/back", "top", "top/broken", "top/file.txt"},
+		},
+		{
+			name:  "follow",
+			opts:  []WalkOption{WithFollowSymlinks()},
+			want:  []string{".", "real", "real/a.txt", "top", "top/broken", "top/file.txt"},
+			loops: []string{"real/back"},
+		},
+	}
+	for _, test := range tests {
+		var visited, loops []string
+		err := ps.Walk(fsys, func(name string, d fs.DirEntry, err error) error {
+			if errors.Is(err, ErrSymlinkLoop) {
+				loops = append(loops, name)
+				return nil
+			} else if err != nil {
+				return err
+			}
+			visited = append(visited, name)
+			return nil
+		}, test.opts...)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if strings.Join(visited, ",") != strings.Join(test.want, ",") {
+			t.Errorf("Walk() with %s policy visited '%s', want '%s'", test.name, visited, test.want)
+		}
+		if strings.Join(loops, ",") != strings.Join(test.loops, ",") {
+			t.Errorf("Walk() with %s policy reported loops '%s', want '%s'", test.name, loops, test.loops)
+		}
+	}
+
+	// Followed links are walked like directories.
+	ps, err = FromLines("*.log", "other/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var visited []string
+	err = ps.Walk(fsys, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		visited = append(visited, name)
+		return nil
+	}, WithFollowSymlinks())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := []string{".", "link", "link/a.txt", "real", "real/a.txt", "top", "top/broken", "top/file.txt"}
+	if strings.Join(visited, ",") != strings.Join(want, ",") {
+		t.Errorf("Walk() following links visited '%s', want '%s'", visited, want)
+	}
+}
@@ -0,0 +1,103 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package tarutil writes tar archives of the files a PathSpec does not
+// ignore, like the build context of "docker build" honoring .dockerignore.
+package tarutil
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+
+	pathspec "github.com/shibumi/go-pathspec"
+)
+
+// readLinkFS is implemented by file systems which can read the target of
+// symbolic links, like os.DirFS does since Go 1.25.
+type readLinkFS interface {
+	ReadLink(name string) (string, error)
+}
+
+// WriteTar writes a tar archive of the files and directories of fsys which
+// spec does not ignore to w. Ignored directories are not descended into.
+// Entries keep their permissions and modification times. Symbolic links are
+// archived as links, which requires fsys to implement
+// "ReadLink(name string) (string, error)"; they are never followed. Other
+// file types, like devices and sockets, are skipped. WriteTar does not close
+// w.
+func WriteTar(w io.Writer, fsys fs.FS, spec *pathspec.PathSpec) error {
+	tw := tar.NewWriter(w)
+	err := spec.Walk(fsys, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		return writeEntry(tw, fsys, name, d)
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// writeEntry writes the header, and for regular files the content, of the
+// entry d called name to tw.
+func writeEntry(tw *tar.Writer, fsys fs.FS, name string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	var link string
+	switch mode := info.Mode(); {
+	case mode&fs.ModeSymlink != 0:
+		rfs, ok := fsys.(readLinkFS)
+		if !ok {
+			return fmt.Errorf("cannot archive symbolic link %s: file system cannot read links", name)
+		}
+		if link, err = rfs.ReadLink(name); err != nil {
+			return err
+		}
+	case !mode.IsRegular() && !mode.IsDir():
+		return nil
+	}
+
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
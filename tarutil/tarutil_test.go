@@ -0,0 +1,121 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tarutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	pathspec "github.com/shibumi/go-pathspec"
+)
+
+// readTar returns the names of the entries of the tar archive data, and the
+// headers and contents by name.
+func readTar(t *testing.T, data []byte) ([]string, map[string]*tar.Header, map[string]string) {
+	t.Helper()
+	var names []string
+	headers := make(map[string]*tar.Header)
+	contents := make(map[string]string)
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		names = append(names, hdr.Name)
+		headers[hdr.Name] = hdr
+		contents[hdr.Name] = string(content)
+	}
+	return names, headers, contents
+}
+
+func TestWriteTar(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":      {Data: []byte("package main\n"), Mode: 0o644},
+		"run.sh":       {Data: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"debug.log":    {Data: []byte("log\n")},
+		"build/out":    {Data: []byte("out\n")},
+		"src/lib.go":   {Data: []byte("package lib\n")},
+		"src/lib.o":    {Data: []byte("obj\n")},
+		"src/keep.log": {Data: []byte("keep\n")},
+	}
+	spec, err := pathspec.FromLines("*.log", "!keep.log", "build/", "*.o")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTar(&buf, fsys, spec); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	names, headers, contents := readTar(t, buf.Bytes())
+	want := []string{"main.go", "run.sh", "src/", "src/keep.log", "src/lib.go"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("WriteTar() archived %v, want %v", names, want)
+	}
+	if got := headers["run.sh"].FileInfo().Mode().Perm(); got != 0o755 {
+		t.Errorf("WriteTar() archived run.sh with mode %v, want %v", got, fs.FileMode(0o755))
+	}
+	if headers["src/"].Typeflag != tar.TypeDir {
+		t.Errorf("WriteTar() archived src/ with type %c, want a directory", headers["src/"].Typeflag)
+	}
+	if got := contents["src/lib.go"]; got != "package lib\n" {
+		t.Errorf("WriteTar() archived src/lib.go with content %q", got)
+	}
+}
+
+func TestWriteTarSymlink(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "target.txt"), []byte("target\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := os.Symlink("target.txt", filepath.Join(dir, "link.txt")); err != nil {
+		t.Skipf("Cannot create symbolic links: %s", err)
+	}
+	fsys := os.DirFS(dir)
+	if _, ok := fsys.(readLinkFS); !ok {
+		t.Skip("os.DirFS cannot read symbolic links")
+	}
+	spec, err := pathspec.FromLines("*.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTar(&buf, fsys, spec); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	_, headers, _ := readTar(t, buf.Bytes())
+	hdr := headers["link.txt"]
+	if hdr == nil || hdr.Typeflag != tar.TypeSymlink || hdr.Linkname != "target.txt" {
+		t.Errorf("WriteTar() archived link.txt as %+v, want a symbolic link to target.txt", hdr)
+	}
+}
@@ -0,0 +1,140 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+// Disable turns off the pattern at index i of Patterns, so it no longer
+// matches anything, without recompiling the spec. The pattern keeps its
+// position and can be turned on again with Enable. Disabled patterns are
+// still returned by Patterns and serialized like all others, but are left
+// out by Append, Prepend, Merge and Compile. Disable must not be called
+// concurrently with matching.
+func (ps *PathSpec) Disable(i int) {
+	p := ps.patterns[i]
+	if ps.disabled[p] {
+		return
+	}
+	// The same pattern may occur more than once, for example after
+	// merging a spec with itself, so only the one at i is disabled.
+	for j, q := range ps.patterns {
+		if q == p && j != i {
+			copied := *p
+			p = &copied
+			ps.patterns[i] = p
+			break
+		}
+	}
+	if ps.disabled == nil {
+		ps.disabled = make(map[*Pattern]bool)
+	}
+	ps.disabled[p] = true
+	ps.indexed().remove(i, p)
+	ps.Invalidate()
+}
+
+// Enable turns the pattern at index i of Patterns on again after Disable.
+// Enable must not be called concurrently with matching.
+func (ps *PathSpec) Enable(i int) {
+	p := ps.patterns[i]
+	if !ps.disabled[p] {
+		return
+	}
+	delete(ps.disabled, p)
+	ps.indexed().insert(i, p)
+	ps.Invalidate()
+}
+
+// Enabled reports whether the pattern at index i of Patterns is enabled.
+func (ps *PathSpec) Enabled(i int) bool {
+	return !ps.disabled[ps.patterns[i]]
+}
+
+// DisableFunc disables every pattern f returns true for and returns how many
+// patterns it disabled, see Disable.
+func (ps *PathSpec) DisableFunc(f func(*Pattern) bool) int {
+	n := 0
+	for i, p := range ps.patterns {
+		if ps.Enabled(i) && f(p) {
+			ps.Disable(i)
+			n++
+		}
+	}
+	return n
+}
+
+// EnableFunc enables every disabled pattern f returns true for and returns
+// how many patterns it enabled, see Enable.
+func (ps *PathSpec) EnableFunc(f func(*Pattern) bool) int {
+	n := 0
+	for i, p := range ps.patterns {
+		if !ps.Enabled(i) && f(p) {
+			ps.Enable(i)
+			n++
+		}
+	}
+	return n
+}
+
+// Remove removes the pattern at index i of Patterns. The patterns after it
+// move up by one. Remove must not be called concurrently with matching.
+func (ps *PathSpec) Remove(i int) {
+	ps.setPatterns(concatPatterns(ps.patterns[:i], ps.patterns[i+1:]))
+}
+
+// RemoveFunc removes every pattern f returns true for and returns how many
+// patterns it removed.
+func (ps *PathSpec) RemoveFunc(f func(*Pattern) bool) int {
+	var kept []*Pattern
+	for _, p := range ps.patterns {
+		if !f(p) {
+			kept = append(kept, p)
+		}
+	}
+	n := len(ps.patterns) - len(kept)
+	if n > 0 {
+		ps.setPatterns(kept)
+	}
+	return n
+}
+
+// active returns the enabled patterns in order.
+func (ps *PathSpec) active() []*Pattern {
+	if len(ps.disabled) == 0 {
+		return ps.patterns
+	}
+	patterns := make([]*Pattern, 0, len(ps.patterns))
+	for _, p := range ps.patterns {
+		if !ps.disabled[p] {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// indexed returns the index of the PathSpec, building it first for a
+// PathSpec which was not created by NewPathSpec.
+func (ps *PathSpec) indexed() *patternIndex {
+	if ps.index == nil {
+		ps.index = newPatternIndex(ps.patterns)
+		for i, p := range ps.patterns {
+			if ps.disabled[p] {
+				ps.index.remove(i, p)
+			}
+		}
+	}
+	return ps.index
+}
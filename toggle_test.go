@@ -0,0 +1,101 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPathSpecDisable(t *testing.T) {
+	ps, err := FromLines("*.o", "!keep.o", "/build/main.o", "build/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	check := func(step string, want map[string]MatchState) {
+		t.Helper()
+		cs, err := ps.Compile()
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		for name, state := range want {
+			if got := ps.MatchState(name); got != state {
+				t.Errorf("%s: MatchState('%s') returned '%v', want '%v'", step, name, got, state)
+			}
+			if got := cs.MatchState(name); got != state {
+				t.Errorf("%s: CompiledSpec.MatchState('%s') returned '%v', want '%v'", step, name, got, state)
+			}
+		}
+	}
+
+	ps.Disable(1)
+	ps.Disable(3)
+	if ps.Enabled(1) || !ps.Enabled(0) {
+		t.Errorf("Enabled() does not reflect Disable()")
+	}
+	check("disabled", map[string]MatchState{"keep.o": StateIgnored, "build/": StateUnmatched, "build/main.o": StateIgnored})
+	if e := ps.Explain("keep.o"); e.Steps[1].Outcome != OutcomeDisabled {
+		t.Errorf("Explain() reported '%v' for a disabled pattern, want '%v'", e.Steps[1].Outcome, OutcomeDisabled)
+	}
+
+	ps.Enable(1)
+	check("enabled", map[string]MatchState{"keep.o": StateIncluded, "build/": StateUnmatched})
+
+	// Disabled patterns stay disabled when other patterns are removed.
+	ps.Remove(0)
+	if got := patternTexts(ps.Patterns()); !reflect.DeepEqual(got, []string{"!keep.o", "/build/main.o", "build/"}) {
+		t.Errorf("Patterns() after Remove() returned %v", got)
+	}
+	check("removed", map[string]MatchState{"main.o": StateUnmatched, "keep.o": StateIncluded, "build/": StateUnmatched})
+
+	if n := ps.EnableFunc(func(p *Pattern) bool { return strings.HasPrefix(p.String(), "build") }); n != 1 {
+		t.Errorf("EnableFunc() returned %d, want 1", n)
+	}
+	check("enabled func", map[string]MatchState{"build/": StateIgnored})
+
+	if n := ps.DisableFunc(func(p *Pattern) bool { return p.Negate() }); n != 1 {
+		t.Errorf("DisableFunc() returned %d, want 1", n)
+	}
+	check("disabled func", map[string]MatchState{"keep.o": StateUnmatched})
+
+	if n := ps.RemoveFunc(func(p *Pattern) bool { return !p.Negate() }); n != 2 {
+		t.Errorf("RemoveFunc() returned %d, want 2", n)
+	}
+	if len(ps.Patterns()) != 1 || ps.Enabled(0) {
+		t.Errorf("RemoveFunc() left %v, want the disabled negation only", patternTexts(ps.Patterns()))
+	}
+}
+
+func TestPathSpecDisableShared(t *testing.T) {
+	ps, err := FromLines("*.o")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	merged := Merge(ps, ps)
+	merged.Disable(0)
+	if !merged.Enabled(1) {
+		t.Errorf("Disable(0) disabled the same pattern at index 1")
+	}
+	if !merged.Match("a.o") {
+		t.Errorf("Match('a.o') returned 'false', want 'true'")
+	}
+	if !ps.Enabled(0) {
+		t.Errorf("Disable() of a merged spec disabled the pattern of its source")
+	}
+}
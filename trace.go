@@ -0,0 +1,68 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+// TraceEntry records the evaluation of a single pattern by Trace.
+type TraceEntry struct {
+	// Pattern is the evaluated pattern.
+	Pattern *Pattern
+	// Matched is true if the pattern matched the path.
+	Matched bool
+	// Winner is true for the last matching pattern, which decides about
+	// the path. It is false for all entries if no pattern matched or the
+	// path is tracked, see SetTracked.
+	Winner bool
+	// State is the decision about the path after evaluating the pattern.
+	State MatchState
+}
+
+// Trace evaluates every enabled pattern of the PathSpec against name, in
+// order, and returns one entry per pattern. The State of the last entry is
+// the final decision, as returned by MatchState, unless name is tracked, see
+// SetTracked: then no entry is the winner and the decision is StateIncluded
+// regardless of the patterns. Trace is meant for debugging and is
+// considerably slower than Match, because it cannot stop at the first
+// deciding pattern.
+func (ps *PathSpec) Trace(name string) []TraceEntry {
+	return ps.trace(ps.slashPath(name))
+}
+
+// trace is like Trace, but takes a slash-separated path.
+func (ps *PathSpec) trace(name string) []TraceEntry {
+	patterns := ps.active()
+	trace := make([]TraceEntry, 0, len(patterns))
+	state := StateUnmatched
+	winner := -1
+	for i, p := range patterns {
+		entry := TraceEntry{Pattern: p}
+		if p.match(name) {
+			entry.Matched = true
+			winner = i
+			state = StateIgnored
+			if p.negate {
+				state = StateIncluded
+			}
+		}
+		entry.State = state
+		trace = append(trace, entry)
+	}
+	if winner >= 0 && !isTracked(ps.tracked, name) {
+		trace[winner].Winner = true
+	}
+	return trace
+}
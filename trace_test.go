@@ -0,0 +1,61 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestPathSpecTrace(t *testing.T) {
+	lines := []string{"*.txt", "build/", "!keep.txt", "docs/", "docs/*.txt"}
+	want := []TraceEntry{
+		{Matched: true, Winner: false, State: StateIgnored},
+		{Matched: false, Winner: false, State: StateIgnored},
+		{Matched: true, Winner: false, State: StateIncluded},
+		{Matched: true, Winner: true, State: StateIgnored},
+		{Matched: false, Winner: false, State: StateIgnored},
+	}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	trace := ps.Trace("docs/sub/keep.txt")
+	if len(trace) != len(want) {
+		t.Fatalf("Trace('%s', docs/sub/keep.txt) returned %d entries, want %d", lines, len(trace), len(want))
+	}
+	for i, entry := range trace {
+		if entry.Pattern != ps.Patterns()[i] {
+			t.Errorf("Trace('%s', docs/sub/keep.txt)[%d] has pattern '%s', want '%s'", lines, i, entry.Pattern, ps.Patterns()[i])
+		}
+		entry.Pattern = nil
+		if entry != want[i] {
+			t.Errorf("Trace('%s', docs/sub/keep.txt)[%d] returned '%+v', want '%+v'", lines, i, entry, want[i])
+		}
+	}
+	if got := ps.MatchState("docs/sub/keep.txt"); got != trace[len(trace)-1].State {
+		t.Errorf("MatchState('%s', docs/sub/keep.txt) returned '%v', want '%v'", lines, got, trace[len(trace)-1].State)
+	}
+
+	for i, entry := range ps.Trace("main.go") {
+		if entry.Winner {
+			t.Errorf("Trace('%s', main.go)[%d] is the winner, want none", lines, i)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import "strings"
+
+// WithTrackedSet makes the compiled PathSpec treat the paths tracked reports
+// as tracked, see SetTracked.
+func WithTrackedSet(tracked func(path string) bool) Option {
+	return func(o *options) {
+		o.tracked = tracked
+	}
+}
+
+// SetTracked installs a function reporting whether a path is tracked, that
+// is, in the index of a git repository, replacing a previously installed one.
+// Git never ignores tracked files, so every decision about a tracked path is
+// StateIncluded, regardless of the patterns, matching the output of
+// "git status". MatchState, MatchStateGit, Decide and the functions built on
+// them, like Match, MatchGit, MatchAll, MatchBytes, the Filter functions and
+// Walk, report it as included. MatchP and CheckIgnore report no deciding
+// pattern for it, Trace and Explain mark no pattern as the winner, CanSkipDir
+// does not skip a tracked directory, and Stats counts tracked files as
+// included. CompiledSpecs and merged PathSpecs inherit tracked. Only
+// MatchingPatterns still lists the patterns matching a tracked path.
+//
+// tracked is called with slash-separated paths without a trailing slash,
+// also for directories; it may report directories containing tracked files
+// to keep walks from pruning them. A nil tracked removes the function.
+// SetTracked must not be called concurrently with matching.
+func (ps *PathSpec) SetTracked(tracked func(path string) bool) {
+	ps.tracked = tracked
+}
+
+// isTracked reports whether the slash-separated path name is tracked, see
+// SetTracked.
+func isTracked(tracked func(string) bool, name string) bool {
+	return tracked != nil && tracked(strings.TrimSuffix(name, "/"))
+}
@@ -0,0 +1,95 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import "testing"
+
+func TestWithTrackedSet(t *testing.T) {
+	tracked := map[string]bool{"debug.log": true, "build/version.txt": true}
+	ps, err := FromLinesWithOptions([]string{"*.log", "build/"}, WithTrackedSet(func(path string) bool {
+		return tracked[path]
+	}))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	cs, err := ps.Compile()
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want Decision
+		git  bool
+	}{
+		{"debug.log", Included, false},
+		{"error.log", Ignored, true},
+		{"build/", Ignored, true},
+		{"build/version.txt", Included, false},
+		{"build/main.o", Ignored, true},
+		{"main.go", Unmatched, false},
+	}
+	for _, test := range tests {
+		if got := ps.Decide(test.name); got != test.want {
+			t.Errorf("Decide('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+		if got := cs.Decide(test.name); got != test.want {
+			t.Errorf("CompiledSpec.Decide('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+		if got := ps.MatchGit(test.name); got != test.git {
+			t.Errorf("MatchGit('%s') returned '%v', want '%v'", test.name, got, test.git)
+		}
+		if got := ps.MatchBytes([]byte(test.name), false); got != (test.want == Ignored) {
+			t.Errorf("MatchBytes('%s') returned '%v', want '%v'", test.name, got, test.want == Ignored)
+		}
+	}
+
+	ps.SetTracked(nil)
+	if got := ps.Decide("debug.log"); got != Ignored {
+		t.Errorf("Decide('debug.log') returned '%v', want '%v'", got, Ignored)
+	}
+}
+
+func TestTrackedDecisionAPIs(t *testing.T) {
+	ps, err := FromLinesWithOptions([]string{"*.log", "build/"}, WithTrackedSet(func(path string) bool {
+		return path == "a.log" || path == "build"
+	}))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if r := ps.CheckIgnore([]string{"a.log"})[0]; r.Ignored() {
+		t.Errorf("CheckIgnore('a.log') returned '%+v', want not ignored", r)
+	}
+	if r := ps.MatchP("a.log"); r != nil {
+		t.Errorf("MatchP('a.log') returned '%+v', want nil", r)
+	}
+	if e := ps.Explain("a.log"); e.State != StateIncluded || !e.Tracked || e.Decider != nil {
+		t.Errorf("Explain('a.log') returned '%v' by '%v', want tracked and included", e.State, e.Decider)
+	}
+	for i, entry := range ps.Trace("a.log") {
+		if entry.Winner {
+			t.Errorf("Trace('a.log')[%d] is the winner, want none", i)
+		}
+	}
+	if ps.CanSkipDir("build") {
+		t.Errorf("CanSkipDir('build') returned 'true', want 'false'")
+	}
+	if r := ps.MatchP("b.log"); r == nil {
+		t.Errorf("MatchP('b.log') returned nil, want '*.log'")
+	}
+}
@@ -0,0 +1,129 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Walk walks the file tree of fsys like fs.WalkDir, but calls fn only for
+// entries which are not ignored by the PathSpec. Ignored directories are
+// pruned with fs.SkipDir, so their contents are never read. The root of the
+// walk, ".", is always visited. Errors are passed to fn unfiltered.
+//
+// By default, symbolic links are treated like git treats them: as files,
+// which "dir/" patterns never match, and which are not followed. See
+// WithSymlinkDirs and WithFollowSymlinks for other policies.
+func (ps *PathSpec) Walk(fsys fs.FS, fn fs.WalkDirFunc, opts ...WalkOption) error {
+	return ps.WalkContext(context.Background(), fsys, fn, opts...)
+}
+
+// WalkContext is like Walk, but stops and returns ctx.Err() as soon as ctx is
+// cancelled or its deadline expires.
+func (ps *PathSpec) WalkContext(ctx context.Context, fsys fs.FS, fn fs.WalkDirFunc, opts ...WalkOption) error {
+	o := newWalkOptions(opts)
+	m := ps.newWalkMatcher(fsys, o)
+	if o.followSymlinks {
+		return m.walkFollow(ctx, fn)
+	}
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return fn(name, d, err)
+		}
+		if name != "." {
+			isDir := d.IsDir()
+			if o.symlinkDirs && !isDir {
+				isDir = isSymlinkDir(fsys, name, d)
+			}
+			if m.match(name, isDir) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+		if err := fn(name, d, nil); err != nil || !d.IsDir() {
+			return err
+		}
+		if err := m.enter(name); err != nil {
+			return fn(name, d, err)
+		}
+		return nil
+	})
+}
+
+// IgnoreWalk walks the file tree rooted at root like filepath.WalkDir, but
+// skips the files and prunes the directories ignored by spec, like Walk.
+// Paths are matched relative to root and passed to fn joined to root, like
+// filepath.WalkDir does. A nil spec ignores nothing, which is useful with
+// WithIgnoreFiles.
+func IgnoreWalk(root string, spec *PathSpec, fn fs.WalkDirFunc, opts ...WalkOption) error {
+	if spec == nil {
+		spec = NewPathSpec()
+	}
+	return spec.Walk(os.DirFS(root), func(name string, d fs.DirEntry, err error) error {
+		if name == "." {
+			name = root
+		} else {
+			name = filepath.Join(root, filepath.FromSlash(name))
+		}
+		return fn(name, d, err)
+	}, opts...)
+}
+
+// walkMatcher decides which entries of fsys a walk skips.
+type walkMatcher struct {
+	ps   *PathSpec
+	fsys fs.FS
+	// tree holds the ignore files discovered so far, if enabled with
+	// WithIgnoreFiles. It consults ps after them.
+	tree  *GitIgnoreTree
+	names []string
+}
+
+// newWalkMatcher returns the matcher for a walk of fsys configured by o.
+func (ps *PathSpec) newWalkMatcher(fsys fs.FS, o *walkOptions) *walkMatcher {
+	m := &walkMatcher{ps: ps, fsys: fsys, names: o.ignoreFiles}
+	if len(m.names) > 0 {
+		m.tree = &GitIgnoreTree{specs: make(map[string]*PathSpec), excludes: []*PathSpec{ps}}
+	}
+	return m
+}
+
+// enter reads the ignore files of the directory dir, if enabled. The walk
+// must enter directories before matching their contents.
+func (m *walkMatcher) enter(dir string) error {
+	if m.tree == nil {
+		return nil
+	}
+	return m.tree.readDir(m.fsys, dir, m.names)
+}
+
+// match reports whether the walk skips name.
+func (m *walkMatcher) match(name string, isDir bool) bool {
+	if m.tree != nil {
+		return m.tree.MatchPath(name, isDir)
+	}
+	return m.ps.MatchPath(name, isDir)
+}
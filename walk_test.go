@@ -0,0 +1,186 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPathSpecWalk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":                        {},
+		"debug.log":                      {},
+		"docs/index.md":                  {},
+		"docs/build":                     {},
+		"build/out/main":                 {},
+		"node_modules/left-pad/index.js": {},
+		"src/node_modules/x/y.js":        {},
+		"src/app.go":                     {},
+	}
+	lines := []string{"*.log", "build/", "node_modules/"}
+	want := []string{".", "docs", "docs/build", "docs/index.md", "main.go", "src", "src/app.go"}
+
+	ps, err := FromLines(lines...)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	var visited []string
+	err = ps.Walk(fsys, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if strings.Join(visited, ",") != strings.Join(want, ",") {
+		t.Errorf("Walk('%s') visited '%s', want '%s'", lines, visited, want)
+	}
+}
+
+func TestPathSpecWalkContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/b/c": {},
+		"d/e/f": {},
+	}
+
+	ps, err := FromLines("*.log")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	visited := 0
+	err = ps.WalkContext(ctx, fsys, func(name string, d fs.DirEntry, err error) error {
+		visited++
+		if name == "a" {
+			cancel()
+		}
+		return err
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WalkContext() returned '%v', want '%v'", err, context.Canceled)
+	}
+	if visited != 2 {
+		t.Errorf("WalkContext() visited %d entries after cancellation, want 2", visited)
+	}
+}
+
+func TestPathSpecWalkIgnoreFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":         {Data: []byte("*.log\n/tmp/\n")},
+		"debug.log":          {},
+		"tmp/x":              {},
+		"src/.gitignore":     {Data: []byte("!keep.log\ngen/\n")},
+		"src/keep.log":       {},
+		"src/gen/x.go":       {},
+		"src/app.go":         {},
+		"src/tmp/.gitignore": {Data: []byte("*\n")},
+		"src/tmp/y":          {},
+		"vendor/a.go":        {},
+	}
+	want := []string{".", ".gitignore", "src", "src/.gitignore", "src/app.go", "src/keep.log", "src/tmp"}
+
+	ps, err := FromLines("vendor/")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	var visited []string
+	err = ps.Walk(fsys, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, name)
+		return nil
+	}, WithIgnoreFiles())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if strings.Join(visited, ",") != strings.Join(want, ",") {
+		t.Errorf("Walk(WithIgnoreFiles()) visited '%s', want '%s'", visited, want)
+	}
+}
+
+func TestIgnoreWalk(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		".gitignore":    "build/\n",
+		"main.go":       "",
+		"build/out":     "",
+		"docs/.ignore":  "*.tmp\n",
+		"docs/index.md": "",
+		"docs/x.tmp":    "",
+	} {
+		name = filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+	}
+
+	ps, err := FromLines("*.md")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	cases := []struct {
+		spec *PathSpec
+		opts []WalkOption
+		want []string
+	}{
+		{ps, nil, []string{"", ".gitignore", "build", "build/out", "docs", "docs/.ignore", "docs/x.tmp", "main.go"}},
+		{nil, []WalkOption{WithIgnoreFiles(".gitignore", ".ignore")}, []string{"", ".gitignore", "docs", "docs/.ignore", "docs/index.md", "main.go"}},
+		{ps, []WalkOption{WithIgnoreFiles(".gitignore", ".ignore")}, []string{"", ".gitignore", "docs", "docs/.ignore", "main.go"}},
+	}
+	for _, c := range cases {
+		var visited []string
+		err := IgnoreWalk(root, c.spec, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, name)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				rel = ""
+			}
+			visited = append(visited, filepath.ToSlash(rel))
+			return nil
+		}, c.opts...)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if strings.Join(visited, ",") != strings.Join(c.want, ",") {
+			t.Errorf("IgnoreWalk() visited '%s', want '%s'", visited, c.want)
+		}
+	}
+}
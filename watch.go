@@ -0,0 +1,201 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is how often WatchFile checks the file for changes,
+// unless configured otherwise with WithPollInterval.
+const DefaultPollInterval = 2 * time.Second
+
+// WatchOption configures how WatchFile notices changes.
+type WatchOption func(*watchOptions)
+
+// watchOptions holds the configuration of a FileWatcher.
+type watchOptions struct {
+	interval time.Duration
+	notify   <-chan struct{}
+}
+
+// newWatchOptions applies opts to the default configuration.
+func newWatchOptions(opts []WatchOption) *watchOptions {
+	o := &watchOptions{interval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithPollInterval checks the modification time and size of the file every
+// d instead of every DefaultPollInterval. d must be positive.
+func WithPollInterval(d time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.interval = d
+	}
+}
+
+// WithNotifier reloads the file whenever a value is received from notify,
+// instead of polling. It lets programs which already watch the file system,
+// e.g. with fsnotify, drive the reloads. The watcher stops when notify is
+// closed.
+func WithNotifier(notify <-chan struct{}) WatchOption {
+	return func(o *watchOptions) {
+		o.notify = notify
+	}
+}
+
+// FileWatcher keeps a PathSpec up to date with the gitignore file it was
+// read from, see WatchFile.
+type FileWatcher struct {
+	name     string
+	spec     SafeSpec
+	onChange func(*PathSpec, error)
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// WatchFile compiles the gitignore file name, like FromFile, and reloads it
+// in the background whenever it changes. Matching against the FileWatcher
+// always uses the latest spec; reloads swap it atomically, so they are safe
+// while other goroutines are matching.
+//
+// After every reload, onChange, if not nil, is called with the new spec, or
+// with the error if the file could not be read or compiled. On error the
+// previous spec stays in use. onChange is called from the goroutine of the
+// watcher, one call at a time.
+//
+// By default the file is polled every DefaultPollInterval, see
+// WithPollInterval and WithNotifier. WatchFile fails if the file cannot be
+// compiled initially. Call Close to stop watching.
+func WatchFile(name string, onChange func(*PathSpec, error), opts ...WatchOption) (*FileWatcher, error) {
+	o := newWatchOptions(opts)
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := FromFile(name)
+	if err != nil {
+		return nil, err
+	}
+	w := &FileWatcher{
+		name:     name,
+		onChange: onChange,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	w.spec.Store(ps)
+	if o.notify != nil {
+		go w.listen(o.notify)
+	} else {
+		go w.poll(o.interval, info)
+	}
+	return w, nil
+}
+
+// Load returns the current spec. It must not be modified.
+func (w *FileWatcher) Load() *PathSpec {
+	return w.spec.Load()
+}
+
+// Match is like PathSpec.Match of the current spec.
+func (w *FileWatcher) Match(name string) bool {
+	return w.spec.Match(name)
+}
+
+// MatchState is like PathSpec.MatchState of the current spec.
+func (w *FileWatcher) MatchState(name string) MatchState {
+	return w.spec.MatchState(name)
+}
+
+// Decide is like PathSpec.Decide of the current spec.
+func (w *FileWatcher) Decide(name string) Decision {
+	return w.spec.Decide(name)
+}
+
+// Close stops watching the file and waits for a running reload to finish.
+// The current spec stays available.
+func (w *FileWatcher) Close() error {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+	return nil
+}
+
+// poll reloads the file whenever its modification time or size differs from
+// the last check. A file which cannot be read is reported once.
+func (w *FileWatcher) poll(interval time.Duration, last os.FileInfo) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(w.name)
+		switch {
+		case err != nil && last == nil:
+			continue
+		case err != nil:
+			last = nil
+			w.report(nil, err)
+			continue
+		case last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size():
+			continue
+		}
+		last = info
+		w.reload()
+	}
+}
+
+// listen reloads the file whenever a value is received from notify.
+func (w *FileWatcher) listen(notify <-chan struct{}) {
+	defer close(w.done)
+	for {
+		select {
+		case <-w.stop:
+			return
+		case _, ok := <-notify:
+			if !ok {
+				return
+			}
+			w.reload()
+		}
+	}
+}
+
+// reload compiles the file again and swaps the spec on success.
+func (w *FileWatcher) reload() {
+	ps, err := FromFile(w.name)
+	if err == nil {
+		w.spec.Store(ps)
+	}
+	w.report(ps, err)
+}
+
+// report calls the onChange callback, if any.
+func (w *FileWatcher) report(ps *PathSpec, err error) {
+	if w.onChange != nil {
+		w.onChange(ps, err)
+	}
+}
@@ -0,0 +1,106 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitChange waits for the next call of a WatchFile callback.
+func waitChange(t *testing.T, changes <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-changes:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("The watcher did not reload the file")
+		return nil
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ".gitignore")
+	if err := os.WriteFile(name, []byte("*.o\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	changes := make(chan error, 10)
+	w, err := WatchFile(name, func(ps *PathSpec, err error) { changes <- err }, WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	defer w.Close()
+	if !w.Match("a.o") {
+		t.Errorf("Match('a.o') returned 'false', want 'true'")
+	}
+
+	if err := os.WriteFile(name, []byte("*.o\n!keep.o\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := waitChange(t, changes); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if got := w.MatchState("keep.o"); got != StateIncluded {
+		t.Errorf("MatchState('keep.o') returned '%v', want '%v'", got, StateIncluded)
+	}
+
+	// An invalid file is reported and the previous spec stays in use.
+	if err := os.WriteFile(name, []byte("!\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if err := waitChange(t, changes); err == nil {
+		t.Errorf("The watcher did not report the invalid pattern")
+	}
+	if got := w.MatchState("keep.o"); got != StateIncluded {
+		t.Errorf("MatchState('keep.o') returned '%v', want '%v'", got, StateIncluded)
+	}
+}
+
+func TestWatchFileNotifier(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ".gitignore")
+	if err := os.WriteFile(name, []byte("*.o\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	notify := make(chan struct{})
+	changes := make(chan error, 10)
+	w, err := WatchFile(name, func(ps *PathSpec, err error) { changes <- err }, WithNotifier(notify))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	if err := os.WriteFile(name, []byte("*.c\n"), 0o644); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	notify <- struct{}{}
+	if err := waitChange(t, changes); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if w.Match("a.o") || !w.Match("a.c") {
+		t.Errorf("The watcher did not reload the file on notification")
+	}
+	close(notify)
+	w.Close()
+}
+
+func TestWatchFileMissing(t *testing.T) {
+	if _, err := WatchFile(filepath.Join(t.TempDir(), ".gitignore"), nil); err == nil {
+		t.Errorf("WatchFile() of a missing file returned no error")
+	}
+}
@@ -0,0 +1,404 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithWildmatch matches patterns with a port of git's wildmatch algorithm
+// instead of translating them to regular expressions. Compiling a pattern is
+// then almost free, which cuts the startup cost of large ignore files, and
+// corner cases like bracket expressions and "**" behave exactly like in git.
+//
+// Unlike the default engine, the wildmatch engine also follows git in
+// anchoring patterns with a slash in the middle: "doc/frotz" only matches
+// "doc/frotz" relative to the root, not "a/doc/frotz". Like with the default
+// engine, a directory pattern like "build/" matches everything inside a
+// matching directory, too. The patterns have the syntax "wildmatch".
+func WithWildmatch() Option {
+	return func(o *options) {
+		o.wildmatch = true
+	}
+}
+
+// wildmatch flags, see wildmatch.h of git.
+const (
+	wmCaseFold = 1 << iota
+	wmPathname
+)
+
+// wildmatch results, see wildmatch.h of git.
+const (
+	wmMatch           = 0
+	wmNoMatch         = 1
+	wmAbortAll        = -1
+	wmAbortToStarStar = -2
+)
+
+// wildmatcher matches a single gitignore pattern with wildmatch, following
+// the rules of git's dir.c for directory, basename and anchored patterns.
+type wildmatcher struct {
+	pattern  string
+	mustDir  bool
+	basename bool
+	flags    int
+}
+
+// newWildmatchPattern compiles a gitignore pattern for the wildmatch engine.
+func newWildmatchPattern(line string, caseFold bool) (*Pattern, error) {
+	body := line
+	negate := strings.HasPrefix(body, "!")
+	if negate {
+		body = body[1:]
+	}
+	m := &wildmatcher{}
+	if caseFold {
+		m.flags = wmCaseFold
+	}
+	if strings.HasSuffix(body, "/") {
+		body = body[:len(body)-1]
+		m.mustDir = true
+	}
+	m.basename = !strings.Contains(body, "/")
+	if !m.basename {
+		body = strings.TrimPrefix(body, "/")
+	}
+	if body == "" {
+		return nil, fmt.Errorf("invalid pattern %q: %w", line, ErrEmptyPattern)
+	}
+	m.pattern = body
+	return &Pattern{syntax: "wildmatch", text: line, negate: negate, matcher: m, dir: m.mustDir}, nil
+}
+
+// Match matches the slash-separated path name, with a trailing slash for
+// directories. Directory patterns also match the paths inside a matching
+// directory.
+func (m *wildmatcher) Match(name string) bool {
+	isDir := strings.HasSuffix(name, "/")
+	name = strings.TrimSuffix(name, "/")
+	if m.matchPath(name, isDir) {
+		return true
+	}
+	if !m.mustDir {
+		return false
+	}
+	for i := strings.IndexByte(name, '/'); i >= 0; i = nextSlash(name, i) {
+		if m.matchPath(name[:i], true) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath matches the path name without a trailing slash, like git's
+// match_basename and match_pathname do.
+func (m *wildmatcher) matchPath(name string, isDir bool) bool {
+	if m.mustDir && !isDir {
+		return false
+	}
+	if m.basename {
+		return wildmatch(m.pattern, name[strings.LastIndexByte(name, '/')+1:], m.flags) == wmMatch
+	}
+	return wildmatch(m.pattern, name, m.flags|wmPathname) == wmMatch
+}
+
+// wildmatch matches text against the glob pattern like git's wildmatch does.
+// With wmPathname, wildcards do not match slashes, except for "**" forming
+// whole path segments.
+func wildmatch(pattern, text string, flags int) int {
+	return dowild(pattern, 0, text, 0, flags)
+}
+
+// at returns the byte at index i of s, or 0 past its end, like reading the
+// terminating NUL of a C string.
+func at(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return 0
+}
+
+// dowild is a port of the function of the same name in git's wildmatch.c,
+// matching text from index t against pattern from index p.
+func dowild(pattern string, p int, text string, t int, flags int) int {
+	for ; at(pattern, p) != 0; t, p = t+1, p+1 {
+		pCh := pattern[p]
+		tCh := at(text, t)
+		if tCh == 0 && pCh != '*' {
+			return wmAbortAll
+		}
+		if flags&wmCaseFold != 0 {
+			tCh = toLower(tCh)
+			pCh = toLower(pCh)
+		}
+		switch pCh {
+		case '\\':
+			// Literal match with the following character. A trailing
+			// backslash never matches, since tCh is not 0.
+			p++
+			pCh = at(pattern, p)
+			if tCh != pCh {
+				return wmNoMatch
+			}
+			continue
+		default:
+			if tCh != pCh {
+				return wmNoMatch
+			}
+			continue
+		case '?':
+			// Match anything but a slash.
+			if flags&wmPathname != 0 && tCh == '/' {
+				return wmNoMatch
+			}
+			continue
+		case '*':
+			var matchSlash bool
+			p++
+			if at(pattern, p) == '*' {
+				prev := p - 2
+				for p++; at(pattern, p) == '*'; p++ {
+				}
+				if flags&wmPathname == 0 {
+					// Without wmPathname, "*" is the same as "**".
+					matchSlash = true
+				} else if (prev < 0 || pattern[prev] == '/') &&
+					(at(pattern, p) == 0 || pattern[p] == '/' || (pattern[p] == '\\' && at(pattern, p+1) == '/')) {
+					// Assuming "foo/" is already matched and "**/"
+					// follows, try to match nothing first, so
+					// "foo/**/bar" matches both "foo/bar" and
+					// "foo/a/bar".
+					if at(pattern, p) == '/' && dowild(pattern, p+1, text, t, flags) == wmMatch {
+						return wmMatch
+					}
+					matchSlash = true
+				}
+			} else {
+				// Without wmPathname, "*" is the same as "**".
+				matchSlash = flags&wmPathname == 0
+			}
+			if at(pattern, p) == 0 {
+				// A trailing "**" matches everything, a trailing "*"
+				// only if there are no more slashes.
+				if !matchSlash && strings.IndexByte(text[t:], '/') >= 0 {
+					return wmNoMatch
+				}
+				return wmMatch
+			} else if !matchSlash && pattern[p] == '/' {
+				// A single asterisk followed by a slash matches the
+				// next directory.
+				slash := strings.IndexByte(text[t:], '/')
+				if slash < 0 {
+					return wmNoMatch
+				}
+				// The slash is consumed by the loop.
+				t += slash
+				break
+			}
+			for {
+				if tCh == 0 {
+					break
+				}
+				// Advance faster when the asterisk is followed by a
+				// literal, which must be preceded by what "*" matches.
+				if !isGlobSpecial(at(pattern, p)) {
+					pc := pattern[p]
+					if flags&wmCaseFold != 0 {
+						pc = toLower(pc)
+					}
+					for tCh = at(text, t); tCh != 0 && (matchSlash || tCh != '/'); tCh = at(text, t) {
+						if flags&wmCaseFold != 0 {
+							tCh = toLower(tCh)
+						}
+						if tCh == pc {
+							break
+						}
+						t++
+					}
+					if tCh != pc {
+						return wmNoMatch
+					}
+				}
+				if matched := dowild(pattern, p, text, t, flags); matched != wmNoMatch {
+					if !matchSlash || matched != wmAbortToStarStar {
+						return matched
+					}
+				} else if !matchSlash && tCh == '/' {
+					return wmAbortToStarStar
+				}
+				t++
+				tCh = at(text, t)
+			}
+			return wmAbortAll
+		case '[':
+			var matched int
+			if p, matched = matchBracket(pattern, p, tCh, flags); matched != wmMatch {
+				return matched
+			}
+		}
+	}
+	if t < len(text) {
+		return wmNoMatch
+	}
+	return wmMatch
+}
+
+// matchBracket matches tCh against the bracket expression starting at index p
+// of pattern. It returns the index of the closing bracket and wmMatch,
+// wmNoMatch, or wmAbortAll for a malformed expression.
+func matchBracket(pattern string, p int, tCh byte, flags int) (int, int) {
+	p++
+	pCh := at(pattern, p)
+	if pCh == '^' {
+		// "[^" is the same as "[!".
+		pCh = '!'
+	}
+	negated := pCh == '!'
+	if negated {
+		p++
+		pCh = at(pattern, p)
+	}
+	var prevCh byte
+	matched := false
+	for {
+		if pCh == 0 {
+			return p, wmAbortAll
+		}
+		switch {
+		case pCh == '\\':
+			p++
+			pCh = at(pattern, p)
+			if pCh == 0 {
+				return p, wmAbortAll
+			}
+			if tCh == pCh {
+				matched = true
+			}
+		case pCh == '-' && prevCh != 0 && at(pattern, p+1) != 0 && pattern[p+1] != ']':
+			p++
+			pCh = pattern[p]
+			if pCh == '\\' {
+				p++
+				pCh = at(pattern, p)
+				if pCh == 0 {
+					return p, wmAbortAll
+				}
+			}
+			if tCh <= pCh && tCh >= prevCh {
+				matched = true
+			} else if flags&wmCaseFold != 0 && isLower(tCh) {
+				if upper := tCh - 'a' + 'A'; upper <= pCh && upper >= prevCh {
+					matched = true
+				}
+			}
+			// A range cannot start another range.
+			pCh = 0
+		case pCh == '[' && at(pattern, p+1) == ':':
+			p += 2
+			s := p
+			for pCh = at(pattern, p); pCh != 0 && pCh != ']'; pCh = at(pattern, p) {
+				p++
+			}
+			if pCh == 0 {
+				return p, wmAbortAll
+			}
+			if i := p - s - 1; i < 0 || pattern[p-1] != ':' {
+				// Without a closing ":]", "[" is an ordinary
+				// character of the set.
+				p = s - 2
+				pCh = '['
+				if tCh == pCh {
+					matched = true
+				}
+			} else {
+				ok, valid := matchClass(pattern[s:s+i], tCh, flags)
+				if !valid {
+					return p, wmAbortAll
+				}
+				if ok {
+					matched = true
+				}
+				pCh = 0
+			}
+		case tCh == pCh:
+			matched = true
+		}
+		prevCh = pCh
+		p++
+		if pCh = at(pattern, p); pCh == ']' {
+			break
+		}
+	}
+	if matched == negated || (flags&wmPathname != 0 && tCh == '/') {
+		return p, wmNoMatch
+	}
+	return p, wmMatch
+}
+
+// matchClass reports whether c belongs to the character class, like
+// "alpha" for "[:alpha:]", and whether the class is valid at all. Classes
+// only cover ASCII, like in git.
+func matchClass(class string, c byte, flags int) (matched, valid bool) {
+	switch class {
+	case "alnum":
+		return isAlpha(c) || isDigit(c), true
+	case "alpha":
+		return isAlpha(c), true
+	case "blank":
+		return c == ' ' || c == '\t', true
+	case "cntrl":
+		return c < 0x20 || c == 0x7f, true
+	case "digit":
+		return isDigit(c), true
+	case "graph":
+		return c > 0x20 && c < 0x7f, true
+	case "lower":
+		return isLower(c), true
+	case "print":
+		return c >= 0x20 && c < 0x7f, true
+	case "punct":
+		return c > 0x20 && c < 0x7f && !isAlpha(c) && !isDigit(c), true
+	case "space":
+		return c == ' ' || (c >= '\t' && c <= '\r'), true
+	case "upper":
+		return isUpper(c) || (flags&wmCaseFold != 0 && isLower(c)), true
+	case "xdigit":
+		return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F'), true
+	}
+	return false, false
+}
+
+// isGlobSpecial reports whether c has a special meaning in patterns.
+func isGlobSpecial(c byte) bool {
+	return c == '*' || c == '?' || c == '[' || c == '\\'
+}
+
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
+func isAlpha(c byte) bool { return isLower(c) || isUpper(c) }
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// toLower converts an ASCII upper case letter to lower case.
+func toLower(c byte) byte {
+	if isUpper(c) {
+		return c - 'A' + 'a'
+	}
+	return c
+}
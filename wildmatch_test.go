@@ -0,0 +1,258 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+// The wildmatch tests are taken from t3070-wildmatch.sh of git.
+func TestWildmatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		text     string
+		pathname bool
+		icase    bool
+		nopath   bool
+	}{
+		{`foo`, `foo`, true, true, true},
+		{`bar`, `foo`, false, false, false},
+		{``, ``, true, true, true},
+		{`???`, `foo`, true, true, true},
+		{`??`, `foo`, false, false, false},
+		{`*`, `foo`, true, true, true},
+		{`f*`, `foo`, true, true, true},
+		{`*f`, `foo`, false, false, false},
+		{`*foo*`, `foo`, true, true, true},
+		{`*ob*a*r*`, `foobar`, true, true, true},
+		{`*ab`, `aaaaaaabababab`, true, true, true},
+		{`foo\*`, `foo*`, true, true, true},
+		{`foo\*bar`, `foobar`, false, false, false},
+		{`f\\oo`, `f\oo`, true, true, true},
+		{`*[al]?`, `ball`, true, true, true},
+		{`[ten]`, `ten`, false, false, false},
+		{`**[!te]`, `ten`, true, true, true},
+		{`**[!ten]`, `ten`, false, false, false},
+		{`t[a-g]n`, `ten`, true, true, true},
+		{`t[!a-g]n`, `ten`, false, false, false},
+		{`t[!a-g]n`, `ton`, true, true, true},
+		{`t[^a-g]n`, `ton`, true, true, true},
+		{`a[]]b`, `a]b`, true, true, true},
+		{`a[]-]b`, `a-b`, true, true, true},
+		{`a[]-]b`, `a]b`, true, true, true},
+		{`a[]-]b`, `aab`, false, false, false},
+		{`a[]a-]b`, `aab`, true, true, true},
+		{`]`, `]`, true, true, true},
+		{`foo*bar`, `foo/baz/bar`, false, false, true},
+		{`foo**bar`, `foo/baz/bar`, false, false, true},
+		{`foo**bar`, `foobazbar`, true, true, true},
+		{`foo/**/bar`, `foo/baz/bar`, true, true, true},
+		{`foo/**/**/bar`, `foo/baz/bar`, true, true, false},
+		{`foo/**/bar`, `foo/b/a/z/bar`, true, true, true},
+		{`foo/**/**/bar`, `foo/b/a/z/bar`, true, true, true},
+		{`foo/**/bar`, `foo/bar`, true, true, false},
+		{`foo/**/**/bar`, `foo/bar`, true, true, false},
+		{`foo?bar`, `foo/bar`, false, false, true},
+		{`foo[/]bar`, `foo/bar`, false, false, true},
+		{`foo[^a-z]bar`, `foo/bar`, false, false, true},
+		{`f[^eiu][^eiu][^eiu][^eiu][^eiu]r`, `foo/bar`, false, false, true},
+		{`f[^eiu][^eiu][^eiu][^eiu][^eiu]r`, `foo-bar`, true, true, true},
+		{`**/foo`, `foo`, true, true, false},
+		{`**/foo`, `bar/baz/foo`, true, true, true},
+		{`*/foo`, `bar/baz/foo`, false, false, true},
+		{`**/bar*`, `foo/bar/baz`, false, false, true},
+		{`**/bar/*`, `deep/foo/bar/baz`, true, true, true},
+		{`**/bar/*`, `deep/foo/bar/baz/`, false, false, true},
+		{`**/bar/**`, `deep/foo/bar/baz/`, true, true, true},
+		{`**/bar/*`, `deep/foo/bar`, false, false, false},
+		{`**/bar/**`, `deep/foo/bar/`, true, true, true},
+		{`**/bar**`, `foo/bar/baz`, false, false, true},
+		{`*/bar/**`, `foo/bar/baz/x`, true, true, true},
+		{`*/bar/**`, `deep/foo/bar/baz/x`, false, false, true},
+		{`**/bar/*/*`, `deep/foo/bar/baz/x`, true, true, true},
+		{`a[c-c]st`, `acrt`, false, false, false},
+		{`a[c-c]rt`, `acrt`, true, true, true},
+		{`[!]-]`, `]`, false, false, false},
+		{`[!]-]`, `a`, true, true, true},
+		{`\`, ``, false, false, false},
+		{`\`, `\`, false, false, false},
+		{`*/\`, `XXX/\`, false, false, false},
+		{`*/\\`, `XXX/\`, true, true, true},
+		{`@foo`, `@foo`, true, true, true},
+		{`@foo`, `foo`, false, false, false},
+		{`\[ab]`, `[ab]`, true, true, true},
+		{`[[]ab]`, `[ab]`, true, true, true},
+		{`[[:]ab]`, `[ab]`, true, true, true},
+		{`[[::]ab]`, `[ab]`, false, false, false},
+		{`[[:digit]ab]`, `[ab]`, true, true, true},
+		{`[\[:]ab]`, `[ab]`, true, true, true},
+		{`\??\?b`, `?a?b`, true, true, true},
+		{`\a\b\c`, `abc`, true, true, true},
+		{``, `foo`, false, false, false},
+		{`**/t[o]`, `foo/bar/baz/to`, true, true, true},
+		{`[[:alpha:]][[:digit:]][[:upper:]]`, `a1B`, true, true, true},
+		{`[[:digit:][:upper:][:space:]]`, `a`, false, true, false},
+		{`[[:digit:][:upper:][:space:]]`, `A`, true, true, true},
+		{`[[:digit:][:upper:][:space:]]`, `1`, true, true, true},
+		{`[[:digit:][:upper:][:spaci:]]`, `1`, false, false, false},
+		{`[[:digit:][:upper:][:space:]]`, `.`, false, false, false},
+		{`[[:digit:][:punct:][:space:]]`, `.`, true, true, true},
+		{`[[:xdigit:]]`, `5`, true, true, true},
+		{`[[:xdigit:]]`, `f`, true, true, true},
+		{`[[:xdigit:]]`, `D`, true, true, true},
+		{`[[:alnum:][:alpha:][:blank:][:cntrl:][:digit:][:graph:][:lower:][:print:][:punct:][:space:][:upper:][:xdigit:]]`, `_`, true, true, true},
+		{`[^[:alnum:][:alpha:][:blank:][:cntrl:][:digit:][:lower:][:space:][:upper:][:xdigit:]]`, `.`, true, true, true},
+		{`[a-c[:digit:]x-z]`, `5`, true, true, true},
+		{`[a-c[:digit:]x-z]`, `b`, true, true, true},
+		{`[a-c[:digit:]x-z]`, `y`, true, true, true},
+		{`[a-c[:digit:]x-z]`, `q`, false, false, false},
+		{`[\\-^]`, `]`, true, true, true},
+		{`[\\-^]`, `[`, false, false, false},
+		{`[\-_]`, `-`, true, true, true},
+		{`[\]]`, `]`, true, true, true},
+		{`[\]]`, `\]`, false, false, false},
+		{`[\]]`, `\`, false, false, false},
+		{`a[]b`, `ab`, false, false, false},
+		{`a[]b`, `a[]b`, false, false, false},
+		{`ab[`, `ab[`, false, false, false},
+		{`[!`, `ab`, false, false, false},
+		{`[-`, `ab`, false, false, false},
+		{`[-]`, `-`, true, true, true},
+		{`[a-`, `-`, false, false, false},
+		{`[!a-`, `-`, false, false, false},
+		{`[--A]`, `-`, true, true, true},
+		{`[--A]`, `5`, true, true, true},
+		{`[ --]`, `$`, true, true, true},
+		{`[---]`, `-`, true, true, true},
+		{`[------]`, `-`, true, true, true},
+		{`[a-e-n]`, `j`, false, false, false},
+		{`[a-e-n]`, `-`, true, true, true},
+		{`[!------]`, `a`, true, true, true},
+		{`[]-a]`, `[`, false, false, false},
+		{`[]-a]`, `^`, true, true, true},
+		{`[!]-a]`, `^`, false, false, false},
+		{`[!]-a]`, `[`, true, true, true},
+		{`[a^bc]`, `^`, true, true, true},
+		{`[a-]b]`, `-b]`, true, true, true},
+		{`[\]`, `\`, false, false, false},
+		{`[\\]`, `\`, true, true, true},
+		{`[!\\]`, `\`, false, false, false},
+		{`[A-\\]`, `G`, true, true, true},
+		{`b*a`, `aaabbb`, false, false, false},
+		{`*ba*`, `aabcaa`, false, false, false},
+		{`[,]`, `,`, true, true, true},
+		{`[\\,]`, `,`, true, true, true},
+		{`[\\,]`, `\`, true, true, true},
+		{`[,-.]`, `-`, true, true, true},
+		{`[,-.]`, `+`, false, false, false},
+		{`[,-.]`, `-.]`, false, false, false},
+		{`[\1-\3]`, `2`, true, true, true},
+		{`[\1-\3]`, `3`, true, true, true},
+		{`[\1-\3]`, `4`, false, false, false},
+		{`[[-\]]`, `\`, true, true, true},
+		{`[[-\]]`, `[`, true, true, true},
+		{`[[-\]]`, `]`, true, true, true},
+		{`[[-\]]`, `-`, false, false, false},
+		{`-*-*-*-*-*-*-12-*-*-*-m-*-*-*`, `-adobe-courier-bold-o-normal--12-120-75-75-m-70-iso8859-1`, true, true, true},
+		{`-*-*-*-*-*-*-12-*-*-*-m-*-*-*`, `-adobe-courier-bold-o-normal--12-120-75-75-X-70-iso8859-1`, false, false, false},
+		{`-*-*-*-*-*-*-12-*-*-*-m-*-*-*`, `-adobe-courier-bold-o-normal--12-120-75-75-/-70-iso8859-1`, false, false, false},
+		{`XXX/*/*/*/*/*/*/12/*/*/*/m/*/*/*`, `XXX/adobe/courier/bold/o/normal//12/120/75/75/m/70/iso8859/1`, true, true, true},
+		{`XXX/*/*/*/*/*/*/12/*/*/*/m/*/*/*`, `XXX/adobe/courier/bold/o/normal//12/120/75/75/X/70/iso8859/1`, false, false, false},
+		{`**/*a*b*g*n*t`, `abcd/abcdefg/abcdefghijk/abcdefghijklmnop.txt`, true, true, true},
+		{`**/*a*b*g*n*t`, `abcd/abcdefg/abcdefghijk/abcdefghijklmnop.txtz`, false, false, false},
+		{`*/*/*`, `foo`, false, false, false},
+		{`*/*/*`, `foo/bar`, false, false, false},
+		{`*/*/*`, `foo/bba/arr`, true, true, true},
+		{`*/*/*`, `foo/bb/aa/rr`, false, false, true},
+		{`**/**/**`, `foo/bb/aa/rr`, true, true, true},
+		{`*X*i`, `abcXdefXghi`, true, true, true},
+		{`*X*i`, `ab/cXd/efXg/hi`, false, false, true},
+		{`*/*X*/*/*i`, `ab/cXd/efXg/hi`, true, true, true},
+		{`**/*X*/**/*i`, `ab/cXd/efXg/hi`, true, true, true},
+		{`[A-Z]`, `a`, false, true, false},
+		{`[A-Z]`, `A`, true, true, true},
+		{`[a-z]`, `A`, false, true, false},
+		{`[a-z]`, `a`, true, true, true},
+		{`[[:upper:]]`, `a`, false, true, false},
+		{`[[:upper:]]`, `A`, true, true, true},
+		{`[[:lower:]]`, `A`, false, true, false},
+		{`[[:lower:]]`, `a`, true, true, true},
+		{`[B-Za]`, `A`, false, true, false},
+		{`[B-Za]`, `a`, true, true, true},
+		{`[B-a]`, `A`, false, true, false},
+		{`[B-a]`, `a`, true, true, true},
+		{`[Z-y]`, `z`, false, true, false},
+		{`[Z-y]`, `Z`, true, true, true}}
+	for _, test := range tests {
+		if got := wildmatch(test.pattern, test.text, wmPathname) == wmMatch; got != test.pathname {
+			t.Errorf("wildmatch('%s', '%s', wmPathname) returned '%v', want '%v'", test.pattern, test.text, got, test.pathname)
+		}
+		if got := wildmatch(test.pattern, test.text, wmPathname|wmCaseFold) == wmMatch; got != test.icase {
+			t.Errorf("wildmatch('%s', '%s', wmPathname|wmCaseFold) returned '%v', want '%v'", test.pattern, test.text, got, test.icase)
+		}
+		if got := wildmatch(test.pattern, test.text, 0) == wmMatch; got != test.nopath {
+			t.Errorf("wildmatch('%s', '%s', 0) returned '%v', want '%v'", test.pattern, test.text, got, test.nopath)
+		}
+	}
+}
+
+func TestWithWildmatch(t *testing.T) {
+	lines := []string{"doc/frotz", "*.log", "!keep.log", "build/", "/root.txt", "a/**/b", "foo[/]bar"}
+	ps, err := FromLinesWithOptions(lines, WithWildmatch())
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	// Verified with git check-ignore.
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"doc/frotz", true},
+		{"x/doc/frotz", false},
+		{"a.log", true},
+		{"d/keep.log", false},
+		{"d/x.log", true},
+		{"build/", true},
+		{"build/x", true},
+		{"x/build/y", true},
+		{"build", false},
+		{"root.txt", true},
+		{"x/root.txt", false},
+		{"a/b", true},
+		{"a/x/y/b", true},
+		{"x/a/b", false},
+		{"foo/bar", false},
+	}
+	for _, test := range tests {
+		if got := ps.Match(test.name); got != test.want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+
+	ps, err = FromLinesWithOptions([]string{"*.LOG"}, WithWildmatch(), WithCaseSensitive(false))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if got := ps.Match("debug.log"); !got {
+		t.Errorf("Match('debug.log') returned '%v', want '%v'", got, true)
+	}
+
+	if _, err := FromLinesWithOptions([]string{"/"}, WithWildmatch()); err == nil {
+		t.Errorf("FromLinesWithOptions('/') returned no error")
+	}
+}
@@ -0,0 +1,118 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// WithWindowsPaths makes the PathSpec match native Windows paths, on any
+// platform. Backslashes are path separators, and the long path prefix
+// "\\?\" is removed, also in its UNC form "\\?\UNC\server\share". If root is
+// not empty, it is stripped from names below it, so absolute paths like
+// `C:\repo\build\out.o` are matched relative to root `C:\repo`. Names
+// outside root are matched as they are. Drive letters and root are compared
+// case-insensitively.
+//
+// Like on NTFS, patterns match case-insensitively. Pass WithCaseSensitive
+// after WithWindowsPaths to match case-sensitively instead.
+func WithWindowsPaths(root string) Option {
+	return func(o *options) {
+		o.windows = true
+		o.windowsRoot = windowsSlashPath(root)
+		if len(o.windowsRoot) > 1 && !strings.HasSuffix(o.windowsRoot, ":/") {
+			o.windowsRoot = strings.TrimSuffix(o.windowsRoot, "/")
+		}
+		o.caseInsensitive = true
+	}
+}
+
+// WithCaseSensitive sets whether patterns match case-sensitively, which is
+// the default unless WithWindowsPaths is used.
+func WithCaseSensitive(sensitive bool) Option {
+	return func(o *options) {
+		o.caseInsensitive = !sensitive
+	}
+}
+
+// foldCase makes the pattern p match case-insensitively. Patterns matched by
+// custom Matchers are left unchanged. Case-insensitive patterns are not
+// indexed, because the index compares path segments exactly.
+func foldCase(p *Pattern) (*Pattern, error) {
+	if !p.hasRegex() {
+		return p, nil
+	}
+	if p.lazy != nil {
+		p.lazy = &lazyRegex{expr: "(?i)" + p.lazy.expr}
+	} else {
+		regex, err := regexp.Compile("(?i)" + p.regex.String())
+		if err != nil {
+			return nil, err
+		}
+		p.regex = regex
+	}
+	p.prefix = ""
+	p.base = ""
+	p.literal = ""
+	return p, nil
+}
+
+// windowsPathFunc returns a function converting native Windows paths to the
+// slash-separated paths relative to root patterns are matched against.
+func windowsPathFunc(root string) func(string) string {
+	return func(name string) string {
+		name = windowsSlashPath(name)
+		if root == "" || len(name) < len(root) || !strings.EqualFold(name[:len(root)], root) {
+			return name
+		}
+		rel := name[len(root):]
+		switch {
+		case rel == "" || rel == "/":
+			return "."
+		case rel[0] == '/':
+			return rel[1:]
+		case strings.HasSuffix(root, "/"):
+			// root is a volume root like "C:/".
+			return rel
+		}
+		return name
+	}
+}
+
+// windowsSlashPath removes the long path prefix of a Windows path and
+// converts its backslashes to slashes.
+func windowsSlashPath(name string) string {
+	switch {
+	case strings.HasPrefix(name, `\\?\UNC\`):
+		name = `\\` + name[len(`\\?\UNC\`):]
+	case strings.HasPrefix(name, `\\?\`):
+		name = name[len(`\\?\`):]
+	}
+	return strings.ReplaceAll(name, `\`, "/")
+}
+
+// slashPath converts name to the slash-separated form patterns are matched
+// against.
+func (ps *PathSpec) slashPath(name string) string {
+	if ps.pathFunc != nil {
+		return ps.pathFunc(name)
+	}
+	return filepath.ToSlash(name)
+}
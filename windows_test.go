@@ -0,0 +1,101 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"testing"
+)
+
+func TestWithWindowsPaths(t *testing.T) {
+	ps, err := FromLinesWithOptions([]string{"*.LOG", "/build/", "Docs/*.md", "!keep.log"}, WithWindowsPaths(`C:\Repo`))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{`C:\Repo\debug.log`, true},
+		{`c:\repo\src\debug.Log`, true},
+		{`C:\Repo\keep.log`, false},
+		{`C:\Repo\build\`, true},
+		{`C:\Repo\build\out.o`, true},
+		{`C:\Repo\src\build\out.o`, false},
+		{`C:\Repo\docs\README.MD`, true},
+		{`\\?\C:\Repo\build\out.o`, true},
+		{`C:\Repository\build\out.o`, false},
+		{`D:\build\out.o`, false},
+		{`src\debug.log`, true},
+	}
+	for _, test := range tests {
+		if got := ps.Match(test.name); got != test.want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+
+	cs, err := ps.Compile()
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if !cs.Match(`C:\Repo\build\out.o`) {
+		t.Errorf("CompiledSpec.Match('%s') returned 'false', want 'true'", `C:\Repo\build\out.o`)
+	}
+}
+
+func TestWithWindowsPathsUNC(t *testing.T) {
+	ps, err := FromLinesWithOptions([]string{"/bin/"}, WithWindowsPaths(`\\server\share\repo`), WithCaseSensitive(true))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{`\\server\share\repo\bin\tool.exe`, true},
+		{`\\?\UNC\server\share\repo\bin\tool.exe`, true},
+		{`\\SERVER\share\repo\bin\tool.exe`, true},
+		{`\\server\share\repo\BIN\tool.exe`, false},
+	}
+	for _, test := range tests {
+		if got := ps.Match(test.name); got != test.want {
+			t.Errorf("Match('%s') returned '%v', want '%v'", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWindowsPathFunc(t *testing.T) {
+	tests := []struct {
+		root, name, want string
+	}{
+		{`C:\`, `C:\a\b`, "a/b"},
+		{`C:\repo\`, `C:\repo`, "."},
+		{`C:\repo`, `C:\repo\a\`, "a/"},
+		{"", `C:\repo\a`, "C:/repo/a"},
+	}
+	for _, test := range tests {
+		ps, err := FromLinesWithOptions(nil, WithWindowsPaths(test.root))
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		if got := ps.slashPath(test.name); got != test.want {
+			t.Errorf("slashPath('%s') with root '%s' returned '%s', want '%s'", test.name, test.root, got, test.want)
+		}
+	}
+}
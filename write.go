@@ -0,0 +1,96 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteTo implements io.WriterTo. It writes the patterns as a gitignore file,
+// one pattern per line, escaping them where needed so reading the file back
+// yields the same patterns. Only patterns of the "gitwildmatch" syntax can be
+// written.
+func (ps *PathSpec) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, p := range ps.patterns {
+		line, err := formatLine(p)
+		if err != nil {
+			return 0, err
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// WriteTo implements io.WriterTo. It writes the document line by line,
+// keeping comments and blank lines. Lines are written as they were parsed,
+// unless the Pattern of a NodePattern was replaced, in which case the line is
+// formatted from the pattern like PathSpec.WriteTo does.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, n := range d.Nodes {
+		line := n.Text
+		if n.Kind == NodePattern && n.Pattern != nil {
+			if pattern, _ := patternFromLine(line); pattern != n.Pattern.text {
+				var err error
+				if line, err = formatLine(n.Pattern); err != nil {
+					return 0, err
+				}
+			}
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// formatLine returns the gitignore line reading back as p. A leading "#",
+// which would start a comment, and a trailing space, which would be trimmed,
+// are escaped with a backslash.
+func formatLine(p *Pattern) (string, error) {
+	if p.syntax != "gitwildmatch" {
+		return "", fmt.Errorf("pattern %q of syntax %q cannot be written as a gitignore line", p.text, p.syntax)
+	}
+	if strings.ContainsAny(p.text, "\r\n") {
+		return "", fmt.Errorf("pattern %q spans several lines", p.text)
+	}
+	line := p.text
+	if line[0] == '#' {
+		line = "\\" + line
+	}
+	if last := len(line) - 1; line[last] == ' ' && !isEscaped(line, last) {
+		line = line[:last] + "\\" + line[last:]
+	}
+	return line, nil
+}
+
+// isEscaped reports whether the byte at index i of s is preceded by an odd
+// number of backslashes.
+func isEscaped(s string, i int) bool {
+	n := 0
+	for i > 0 && s[i-1] == '\\' {
+		n++
+		i--
+	}
+	return n%2 == 1
+}
@@ -0,0 +1,98 @@
+//
+// Copyright 2014, Sander van Harmelen
+// Copyright 2020, Christian Rebischke
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pathspec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPathSpecWriteTo(t *testing.T) {
+	var patterns []*Pattern
+	for _, line := range []string{"*.o", "#hash", " lead", "trail ", "!#keep", "esc\\ ", "\\!bang"} {
+		p, err := NewPattern(line)
+		if err != nil {
+			t.Fatalf("Received an unexpected error: %s", err)
+		}
+		patterns = append(patterns, p)
+	}
+	ps := NewPathSpec(patterns...)
+
+	var b strings.Builder
+	n, err := ps.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := "*.o\n\\#hash\n lead\ntrail\\ \n!#keep\nesc\\ \n\\!bang\n"
+	if b.String() != want {
+		t.Errorf("WriteTo() wrote %q, want %q", b.String(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("WriteTo() returned %d, want %d", n, len(want))
+	}
+
+	read, err := FromReader(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	for _, name := range []string{"a.o", "#hash", " lead", "trail ", "esc ", "!bang", "#keep"} {
+		if got, want := read.MatchState(name), ps.MatchState(name); got != want {
+			t.Errorf("MatchState('%s') of the written spec returned '%v', want '%v'", name, got, want)
+		}
+	}
+}
+
+func TestPathSpecWriteToSyntax(t *testing.T) {
+	p, err := NewRegexPattern(`\.o$`)
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var b strings.Builder
+	if _, err := NewPathSpec(p).WriteTo(&b); err == nil {
+		t.Errorf("WriteTo() of a regex pattern returned no error")
+	}
+}
+
+func TestDocumentWriteTo(t *testing.T) {
+	text := "# objects\n*.o  \n\n  !keep.o\n"
+	d, err := ReadDocument(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	var b strings.Builder
+	if _, err := d.WriteTo(&b); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	if b.String() != text {
+		t.Errorf("WriteTo() wrote %q, want %q", b.String(), text)
+	}
+
+	p, err := NewPattern("#main.o")
+	if err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	d.Nodes[1].Pattern = p
+	b.Reset()
+	if _, err := d.WriteTo(&b); err != nil {
+		t.Fatalf("Received an unexpected error: %s", err)
+	}
+	want := "# objects\n\\#main.o\n\n  !keep.o\n"
+	if b.String() != want {
+		t.Errorf("WriteTo() wrote %q, want %q", b.String(), want)
+	}
+}